@@ -27,6 +27,7 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	v1 "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/L-F-Z/cri-t/internal/criocli"
@@ -77,6 +78,7 @@ func catchShutdown(ctx context.Context, cancel context.CancelFunc, gserver *grpc
 					log.Warnf(ctx, "Error shutting down opentelemetry tracer provider: %v", err)
 				}
 			}
+			streamingServer.WaitForInFlightCreates(ctx)
 			gserver.GracefulStop()
 			hserver.Shutdown(ctx) //nolint: errcheck
 			if err := streamingServer.StopStreamServer(); err != nil {
@@ -317,7 +319,7 @@ func main() {
 				logrus.Fatalf("Failed to initialize tracer provider: %v", err)
 			}
 		}
-		grpcServer := grpc.NewServer(
+		grpcServerOpts := []grpc.ServerOption{
 			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 				interceptors.UnaryInterceptor(),
 			)),
@@ -327,7 +329,16 @@ func main() {
 			grpc.StatsHandler(otelgrpc.NewServerHandler(opts...)),
 			grpc.MaxSendMsgSize(config.GRPCMaxSendMsgSize),
 			grpc.MaxRecvMsgSize(config.GRPCMaxRecvMsgSize),
-		)
+			// grpc uses its defaults for zero values.
+			grpc.KeepaliveParams(keepalive.ServerParameters{
+				Time:    config.GRPCKeepaliveTime,
+				Timeout: config.GRPCKeepaliveTimeout,
+			}),
+		}
+		if config.GRPCMaxConcurrentStreams > 0 {
+			grpcServerOpts = append(grpcServerOpts, grpc.MaxConcurrentStreams(uint32(config.GRPCMaxConcurrentStreams)))
+		}
+		grpcServer := grpc.NewServer(grpcServerOpts...)
 
 		crioServer, err := server.New(ctx, config)
 		if err != nil {
@@ -53,12 +53,16 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l default-ulimits -r -d 'Uli
 complete -c crio -n '__fish_crio_no_subcommand' -f -l device-ownership-from-security-context -d 'Set devices\' uid/gid ownership from runAsUser/runAsGroup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l disable-hostport-mapping -d 'If true, CRI-O would disable the hostport mapping.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l drop-infra-ctr -d 'Determines whether pods are created without an infra container, when the pod is not using a pod level PID namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-criu-support -d 'Enable checkpointing and restoring containers with CRIU, which requires the criu binary in $PATH.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-metrics -d 'Enable metrics endpoint for the server.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-nri -d 'Enable NRI (Node Resource Interface) support.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-pod-events -d 'If true, CRI-O starts sending the container events to the kubelet'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-profile-unix-socket -d 'Enable pprof profiler on crio unix domain socket.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-tracing -d 'Enable OpenTelemetry trace data exporting.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l gid-mappings -r -d 'Specify the GID mappings to use for the user namespace. This option is deprecated, and will be replaced with Kubernetes user namespace (KEP-127) support in the future.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l grpc-keepalive-time -r -d 'Duration after which the grpc server pings an idle client connection to check whether it is still alive. If set to 0, the grpc default of 2 hours is used.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l grpc-keepalive-timeout -r -d 'Duration the grpc server waits for the answer to a keepalive ping before it closes the connection. If set to 0, the grpc default of 20 seconds is used.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l grpc-max-concurrent-streams -r -d 'Maximum number of concurrent grpc streams of each client connection. If set to 0, the streams are not limited.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l grpc-max-recv-msg-size -r -d 'Maximum grpc receive message size in bytes.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l grpc-max-send-msg-size -r -d 'Maximum grpc receive message size.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l hooks-dir -r -d 'Set the OCI hooks directory path (may be set multiple times)
@@ -81,6 +85,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l hooks-dir -r -d 'Set the O
     Kubernetes configuration are considered. Bind mounts that CRI-O
     inserts by default (e.g. \'/dev/shm\') are not considered.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l hostnetwork-disable-selinux -d 'Determines whether SELinux should be disabled within a pod when it is running in the host network namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-mounts-copy-on-write -d 'Make image mounts which are not requested read-only writable through a per container overlay upper directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l image-volumes -r -d 'Image volume handling (\'mkdir\', \'bind\', or \'ignore\')
     1. mkdir: A directory is created inside the container root filesystem for
        the volumes.
@@ -90,6 +95,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l image-volumes -r -d 'Image
 complete -c crio -n '__fish_crio_no_subcommand' -l imagestore -r -d 'Store newly pulled images in the specified path, rather than the path provided by --root.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l included-pod-metrics -r -d 'A list of pod metrics to include. Specify the names of the metrics to include in this list.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l infra-ctr-cpuset -r -d 'CPU set to run infra containers, if not specified CRI-O will use all online CPUs to run infra containers.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l infra-ctr-oom-score-adj -r -d 'The OOM score adjustment of infra containers, between -1000 and 1000.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l internal-repair -d 'If true, CRI-O will check if the container and image storage was corrupted after a sudden restart, and attempt to repair the storage if it was.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l internal-wipe -d 'Whether CRI-O should wipe containers after a reboot and images after an upgrade when the server starts. If set to false, one must run \'crio wipe\' to wipe the containers and images in these situations. This option is deprecated, and will be removed in the future.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l irqbalance-config-file -r -d 'The irqbalance service config file which is used by CRI-O.'
@@ -142,8 +148,9 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l storage-driver -s s -r -d
 complete -c crio -n '__fish_crio_no_subcommand' -f -l storage-opt -r -d 'OCI storage driver option.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l stream-address -r -d 'Bind address for streaming socket.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l stream-enable-tls -d 'Enable encrypted TLS transport of the stream server.'
-complete -c crio -n '__fish_crio_no_subcommand' -f -l stream-idle-timeout -r -d 'Length of time until open streams terminate due to lack of activity.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l stream-idle-timeout -r -d 'Length of time until open streams of exec, attach and port forward requests terminate due to lack of activity. Setting it to "0" keeps idle streams open.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l stream-port -r -d 'Bind port for streaming socket. If the port is set to \'0\', then CRI-O will allocate a random free port number.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l stream-require-client-cert -d 'Enforce that clients of the encrypted stream present a certificate signed by the stream-tls-ca, by refusing to start without stream-tls-ca. Client certificates are always required if stream-tls-ca is set.'
 complete -c crio -n '__fish_crio_no_subcommand' -l stream-tls-ca -r -d 'Path to the x509 CA(s) file used to verify and authenticate client communication with the encrypted stream. This file can change and CRI-O will automatically pick up the changes.'
 complete -c crio -n '__fish_crio_no_subcommand' -l stream-tls-cert -r -d 'Path to the x509 certificate file used to serve the encrypted stream. This file can change and CRI-O will automatically pick up the changes.'
 complete -c crio -n '__fish_crio_no_subcommand' -l stream-tls-key -r -d 'Path to the key file used to serve the encrypted stream. This file can change and CRI-O will automatically pick up the changes.'
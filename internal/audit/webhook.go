@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	json "github.com/json-iterator/go"
+
+	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+// ErrDenied is returned by Review if the webhook denied the operation.
+var ErrDenied = errors.New("denied by the audit webhook")
+
+// maxMessageLength is the maximum number of bytes of a webhook response
+// which are read as denial message.
+const maxMessageLength = 4096
+
+// Operation is the operation on a container sent to the webhook.
+type Operation string
+
+const (
+	// OperationCreate is sent before a container gets created. The webhook
+	// can deny the creation.
+	OperationCreate Operation = "create"
+	// OperationRemove is sent after a container got removed.
+	OperationRemove Operation = "remove"
+)
+
+// Request is the body POSTed to the webhook.
+type Request struct {
+	Operation Operation `json:"operation"`
+	Pod       Pod       `json:"pod"`
+	Container Container `json:"container"`
+}
+
+// Pod identifies the pod of a container.
+type Pod struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	UID         string `json:"uid"`
+	HostNetwork bool   `json:"hostNetwork"`
+	HostPID     bool   `json:"hostPID"`
+	HostIPC     bool   `json:"hostIPC"`
+}
+
+// Container identifies a container.
+type Container struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// Security is only sent for creations.
+	Security *oci.SecurityPosture `json:"security,omitempty"`
+}
+
+// Webhook POSTs container operations to an external audit endpoint.
+type Webhook struct {
+	url      string
+	timeout  time.Duration
+	failOpen bool
+	client   *http.Client
+}
+
+// New creates a webhook POSTing to url. Calls taking longer than timeout are
+// aborted, and if failOpen is set, failed calls do not fail the operation. New
+// returns nil for an empty url, which disables the webhook.
+func New(url string, timeout time.Duration, failOpen bool) *Webhook {
+	if url == "" {
+		return nil
+	}
+	return &Webhook{
+		url:      url,
+		timeout:  timeout,
+		failOpen: failOpen,
+		client:   &http.Client{},
+	}
+}
+
+// Review sends the request to the webhook. It returns an error wrapping
+// ErrDenied with the message of the webhook if it answered with a non 2xx
+// status. If the webhook cannot be reached or times out, the error is only
+// returned if the webhook fails closed. Review on a nil webhook does nothing.
+func (w *Webhook) Review(ctx context.Context, req *Request) error {
+	if w == nil {
+		return nil
+	}
+
+	err := w.post(ctx, req)
+	if err == nil || errors.Is(err, ErrDenied) {
+		return err
+	}
+	if w.failOpen && ctx.Err() == nil {
+		log.Warnf(ctx, "Ignoring failed audit webhook call for %s of container %s: %v", req.Operation, req.Container.Name, err)
+		return nil
+	}
+	return fmt.Errorf("audit webhook call: %w", err)
+}
+
+func (w *Webhook) post(ctx context.Context, req *Request) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	// The status alone denies the operation, even if the message cannot be read.
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxMessageLength))
+	return fmt.Errorf("%w with status %d: %s", ErrDenied, resp.StatusCode, strings.TrimSpace(string(message)))
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/json-iterator/go"
+
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+func newTestRequest() *Request {
+	return &Request{
+		Operation: OperationCreate,
+		Pod:       Pod{ID: "podID", Name: "pod", Namespace: "namespace", UID: "uid"},
+		Container: Container{
+			ID:       "containerID",
+			Name:     "container",
+			Image:    "image",
+			Security: &oci.SecurityPosture{Privileged: true, CapabilitiesAdded: []string{"CAP_SYS_ADMIN"}},
+		},
+	}
+}
+
+func TestWebhookAllow(t *testing.T) {
+	var received Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+	sut := New(server.URL, time.Minute, false)
+
+	if err := sut.Review(context.Background(), newTestRequest()); err != nil {
+		t.Fatalf("expected the creation to be allowed, got %v", err)
+	}
+	if received.Container.ID != "containerID" || received.Pod.Namespace != "namespace" {
+		t.Errorf("unexpected identity in request %+v", received)
+	}
+	if received.Container.Security == nil || !received.Container.Security.Privileged {
+		t.Errorf("expected the security attributes in request %+v", received)
+	}
+}
+
+func TestWebhookDeny(t *testing.T) {
+	for _, failOpen := range []bool{false, true} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "privileged containers are not allowed", http.StatusForbidden)
+		}))
+		defer server.Close()
+		sut := New(server.URL, time.Minute, failOpen)
+
+		err := sut.Review(context.Background(), newTestRequest())
+
+		if !errors.Is(err, ErrDenied) {
+			t.Fatalf("expected the creation to be denied with fail open %v, got %v", failOpen, err)
+		}
+		if !strings.Contains(err.Error(), "privileged containers are not allowed") {
+			t.Errorf("expected the message of the webhook, got %v", err)
+		}
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		failOpen bool
+		wantErr  bool
+	}{
+		{"fail open", true, false},
+		{"fail closed", false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			unblock := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-unblock:
+				case <-r.Context().Done():
+				}
+			}))
+			defer server.Close()
+			defer close(unblock)
+			sut := New(server.URL, 10*time.Millisecond, tc.failOpen)
+
+			err := sut.Review(context.Background(), newTestRequest())
+
+			if tc.wantErr {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("expected the creation to fail with a timeout, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected the timeout to be ignored, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookDisabled(t *testing.T) {
+	sut := New("", time.Minute, false)
+
+	if err := sut.Review(context.Background(), newTestRequest()); err != nil {
+		t.Fatalf("expected a disabled webhook to allow everything, got %v", err)
+	}
+}
@@ -24,13 +24,17 @@ type Config struct {
 	TLSCert string
 	TLSKey  string
 	TLSCA   string
+	// RequireClientCert makes sure that client certificates are enforced,
+	// by failing if TLSCA is not set.
+	RequireClientCert bool
 }
 
-func NewCertConfig(ctx context.Context, doneChan chan struct{}, certPath, keyPath, caPath string) (*Config, error) {
+func NewCertConfig(ctx context.Context, doneChan chan struct{}, certPath, keyPath, caPath string, requireClientCert bool) (*Config, error) {
 	cc := &Config{
-		TLSCert: certPath,
-		TLSKey:  keyPath,
-		TLSCA:   caPath,
+		TLSCert:           certPath,
+		TLSKey:            keyPath,
+		TLSCA:             caPath,
+		RequireClientCert: requireClientCert,
 	}
 
 	if err := cc.reload(ctx); err != nil {
@@ -116,6 +120,10 @@ func (cc *Config) reload(ctx context.Context) error {
 
 	config.Certificates = []tls.Certificate{certificate}
 
+	if cc.RequireClientCert && cc.TLSCA == "" {
+		return errors.New("client certificates are required, but no TLS CA is set")
+	}
+
 	// Set up mTLS configurations if TLSCA is set
 	if cc.TLSCA != "" {
 		caBytes, err := os.ReadFile(cc.TLSCA)
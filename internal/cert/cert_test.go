@@ -0,0 +1,58 @@
+package cert
+
+import (
+	"context"
+	"crypto/tls"
+	"path/filepath"
+	"testing"
+)
+
+func TestReloadClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	if err := GenerateSelfSignedCertKey(context.Background(), certPath, keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name              string
+		ca                string
+		requireClientCert bool
+		want              tls.ClientAuthType
+		wantErr           bool
+	}{
+		{name: "no CA", want: tls.NoClientCert},
+		{name: "CA", ca: certPath, want: tls.RequireAndVerifyClientCert},
+		{name: "CA and required client cert", ca: certPath, requireClientCert: true, want: tls.RequireAndVerifyClientCert},
+		{name: "required client cert without CA", requireClientCert: true, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := &Config{
+				TLSCert:           certPath,
+				TLSKey:            keyPath,
+				TLSCA:             tc.ca,
+				RequireClientCert: tc.requireClientCert,
+			}
+
+			err := sut.reload(context.Background())
+
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := sut.GetConfigForClient(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.ClientAuth != tc.want {
+				t.Errorf("expected client auth %v, got %v", tc.want, config.ClientAuth)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ const (
 type CrioClient interface {
 	DaemonInfo(context.Context) (types.CrioInfo, error)
 	ContainerInfo(context.Context, string) (*types.ContainerInfo, error)
+	SandboxNamespaces(context.Context, string) ([]types.NamespaceInfo, error)
 	ConfigInfo(context.Context) (string, error)
 	GoRoutinesInfo(context.Context) (string, error)
 	HeapInfo(context.Context) ([]byte, error)
@@ -110,6 +111,20 @@ func (c *crioClientImpl) ContainerInfo(ctx context.Context, id string) (*types.C
 	return &cInfo, nil
 }
 
+// SandboxNamespaces returns the namespaces managed by a sandbox by querying
+// the cri-o sandbox namespaces endpoint.
+func (c *crioClientImpl) SandboxNamespaces(ctx context.Context, id string) ([]types.NamespaceInfo, error) {
+	body, err := c.doGetRequest(ctx, server.InspectSandboxesEndpoint+"/"+id+"/namespaces")
+	if err != nil {
+		return nil, err
+	}
+	infos := []types.NamespaceInfo{}
+	if err := json.Unmarshal(body, &infos); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
 // ConfigInfo returns current config as TOML string.
 func (c *crioClientImpl) ConfigInfo(ctx context.Context) (string, error) {
 	body, err := c.doGetRequest(ctx, server.InspectConfigEndpoint)
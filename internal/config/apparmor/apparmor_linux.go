@@ -141,6 +141,24 @@ func (c *Config) Apply(p *runtimeapi.LinuxContainerSecurityContext) (string, err
 	return securityProfile, nil
 }
 
+// CheckLoaded returns an error if the profile returned by Apply is not loaded
+// into the kernel. The default and unconfined profiles are not checked,
+// because they are always available.
+func (c *Config) CheckLoaded(profile string) error {
+	if profile == DefaultProfile || profile == c.defaultProfile ||
+		strings.EqualFold(profile, v1.DeprecatedAppArmorBetaProfileNameUnconfined) {
+		return nil
+	}
+	isLoaded, err := apparmor.IsLoaded(profile)
+	if err != nil {
+		return fmt.Errorf("checking if AppArmor profile %s is loaded: %w", profile, err)
+	}
+	if !isLoaded {
+		return fmt.Errorf("apparmor profile %q is not loaded", profile)
+	}
+	return nil
+}
+
 // reloadDefaultProfile reloads the default AppArmor profile and returns an
 // error on any failure.
 func reloadDefaultProfile() error {
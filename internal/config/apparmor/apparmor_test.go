@@ -151,4 +151,30 @@ var _ = t.Describe("Config", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	t.Describe("CheckLoaded", func() {
+		It("should succeed for the default profile", func() {
+			// When
+			err := sut.CheckLoaded(apparmor.DefaultProfile)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should succeed for the unconfined profile", func() {
+			// When
+			err := sut.CheckLoaded("unconfined")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if the profile is not loaded", func() {
+			// When
+			err := sut.CheckLoaded("not-loaded-profile")
+
+			// Then
+			Expect(err).To(MatchError(ContainSubstring(`apparmor profile "not-loaded-profile" is not loaded`)))
+		})
+	})
 })
@@ -4,17 +4,27 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/intel/goresctrl/pkg/blockio"
 	"github.com/sirupsen/logrus"
 	"sigs.k8s.io/yaml"
 )
 
+// reloadInterval is the minimum time between two reloads by
+// ReloadDebounced.
+const reloadInterval = time.Second
+
 type Config struct {
 	enabled bool
 	reload  bool
 	path    string
 	config  *blockio.Config
+
+	// reloadMutex serializes reloads and protects lastReload.
+	reloadMutex sync.Mutex
+	lastReload  time.Time
 }
 
 // New creates a new blockio config instance.
@@ -43,6 +53,24 @@ func (c *Config) ReloadRequired() bool {
 
 // Reload (re-)reads the configuration file and rescans block devices in the system.
 func (c *Config) Reload() error {
+	c.reloadMutex.Lock()
+	defer c.reloadMutex.Unlock()
+	return c.reloadLocked()
+}
+
+// ReloadDebounced reloads like Reload, unless the last successful reload
+// happened less than reloadInterval ago. Concurrent callers wait for a running
+// reload and then use its result instead of reloading again.
+func (c *Config) ReloadDebounced() error {
+	c.reloadMutex.Lock()
+	defer c.reloadMutex.Unlock()
+	if !c.lastReload.IsZero() && time.Since(c.lastReload) < reloadInterval {
+		return nil
+	}
+	return c.reloadLocked()
+}
+
+func (c *Config) reloadLocked() error {
 	if c.path == "" {
 		return nil
 	}
@@ -61,6 +89,7 @@ func (c *Config) Reload() error {
 		return fmt.Errorf("configuring blockio failed: %w", err)
 	}
 	c.config = tmpCfg
+	c.lastReload = time.Now()
 	return nil
 }
 
@@ -86,3 +86,25 @@ var _ = t.Describe("Load", func() {
 		})
 	})
 })
+
+var _ = t.Describe("ReloadDebounced", func() {
+	It("should not reload right after a reload", func() {
+		// Given
+		sut := blockio.New()
+		f := tempFileWithData(`classes:
+  lowprio:
+  - Weight: 20
+`)
+		Expect(sut.Load(f)).To(Succeed())
+		Expect(os.WriteFile(f, []byte(`classes:
+- Weight: 10
+`), 0o644)).To(Succeed())
+
+		// When
+		err := sut.ReloadDebounced()
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sut.Reload()).NotTo(Succeed())
+	})
+})
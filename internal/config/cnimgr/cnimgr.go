@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -96,6 +97,16 @@ func (c *CNIManager) AddWatcher() chan bool {
 	return watcher
 }
 
+// RemoveWatcher removes a watcher added by AddWatcher, which is no longer
+// waited on.
+func (c *CNIManager) RemoveWatcher(watcher chan bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.watchers = slices.DeleteFunc(c.watchers, func(w chan bool) bool {
+		return w == watcher
+	})
+}
+
 // Shutdown shuts down the CNI manager, and notifies the watcher
 // that the CNI manager is not ready.
 func (c *CNIManager) Shutdown() {
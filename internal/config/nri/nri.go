@@ -1,6 +1,8 @@
 package nri
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	nri "github.com/containerd/nri/pkg/adaptation"
@@ -8,6 +10,17 @@ import (
 	"github.com/containerd/ttrpc"
 )
 
+// FailurePolicy defines how a failing NRI hook affects a creation.
+type FailurePolicy string
+
+const (
+	// FailurePolicyFail fails the creation if the NRI hook fails.
+	FailurePolicyFail FailurePolicy = "fail"
+	// FailurePolicyIgnore logs the failure of the NRI hook and continues
+	// the creation.
+	FailurePolicyIgnore FailurePolicy = "ignore"
+)
+
 // Config represents the CRI-O NRI configuration.
 type Config struct {
 	Enabled                   bool          `toml:"enable_nri"`
@@ -17,26 +30,52 @@ type Config struct {
 	PluginRegistrationTimeout time.Duration `toml:"nri_plugin_registration_timeout"`
 	PluginRequestTimeout      time.Duration `toml:"nri_plugin_request_timeout"`
 	DisableConnections        bool          `toml:"nri_disable_connections"`
-	withTracing               bool
+	// CallTimeout limits the time all plugins may take together to handle
+	// the NRI hooks of pod sandbox and container creations.
+	CallTimeout time.Duration `toml:"nri_call_timeout"`
+	// RunPodSandboxFailurePolicy applies if the RunPodSandbox hook fails.
+	RunPodSandboxFailurePolicy FailurePolicy `toml:"nri_run_pod_sandbox_failure_policy"`
+	// CreateContainerFailurePolicy applies if the CreateContainer hook fails.
+	CreateContainerFailurePolicy FailurePolicy `toml:"nri_create_container_failure_policy"`
+	withTracing                  bool
 }
 
 // New returns the default CRI-O NRI configuration.
 func New() *Config {
 	return &Config{
-		Enabled:                   true,
-		SocketPath:                nri.DefaultSocketPath,
-		PluginPath:                nri.DefaultPluginPath,
-		PluginConfigPath:          nri.DefaultPluginConfigPath,
-		PluginRegistrationTimeout: nri.DefaultPluginRegistrationTimeout,
-		PluginRequestTimeout:      nri.DefaultPluginRequestTimeout,
+		Enabled:                      true,
+		SocketPath:                   nri.DefaultSocketPath,
+		PluginPath:                   nri.DefaultPluginPath,
+		PluginConfigPath:             nri.DefaultPluginConfigPath,
+		PluginRegistrationTimeout:    nri.DefaultPluginRegistrationTimeout,
+		PluginRequestTimeout:         nri.DefaultPluginRequestTimeout,
+		RunPodSandboxFailurePolicy:   FailurePolicyFail,
+		CreateContainerFailurePolicy: FailurePolicyFail,
 	}
 }
 
 // Validate loads and validates the effective runtime NRI configuration.
 func (c *Config) Validate(onExecution bool) error {
+	if c.CallTimeout < 0 {
+		return errors.New("nri_call_timeout must not be negative")
+	}
+	if err := c.RunPodSandboxFailurePolicy.validate(); err != nil {
+		return fmt.Errorf("invalid nri_run_pod_sandbox_failure_policy: %w", err)
+	}
+	if err := c.CreateContainerFailurePolicy.validate(); err != nil {
+		return fmt.Errorf("invalid nri_create_container_failure_policy: %w", err)
+	}
 	return nil
 }
 
+func (p FailurePolicy) validate() error {
+	switch p {
+	case FailurePolicyFail, FailurePolicyIgnore:
+		return nil
+	}
+	return fmt.Errorf("unknown failure policy %q, must be %q or %q", p, FailurePolicyFail, FailurePolicyIgnore)
+}
+
 func (c *Config) WithTracing(enable bool) *Config {
 	if c != nil {
 		c.withTracing = enable
@@ -200,6 +200,68 @@ func (mgr *NamespaceManager) NamespaceFromProcEntry(pid int, nsType NSType) (_ N
 	return GetNamespace(pinnedNamespace, nsType)
 }
 
+// nsTypeToCloneFlag maps the namespace types to the CLONE_NEW* flags returned by
+// the NS_GET_NSTYPE ioctl.
+var nsTypeToCloneFlag = map[NSType]int{
+	NETNS:  unix.CLONE_NEWNET,
+	IPCNS:  unix.CLONE_NEWIPC,
+	UTSNS:  unix.CLONE_NEWUTS,
+	USERNS: unix.CLONE_NEWUSER,
+	PIDNS:  unix.CLONE_NEWPID,
+}
+
+// ValidateNamespacePath verifies that nsPath is a namespace of type nsType
+// pinned in the namespaces directory of the manager. It returns an error if
+// the path is outside of the directory, does not exist, is not a namespace or
+// is a namespace of another type.
+func (mgr *NamespaceManager) ValidateNamespacePath(nsPath string, nsType NSType) error {
+	wantFlag, ok := nsTypeToCloneFlag[nsType]
+	if !ok {
+		return fmt.Errorf("invalid namespace type: %s", nsType)
+	}
+	if nsDir := mgr.dirForType(nsType); filepath.Dir(filepath.Clean(nsPath)) != nsDir {
+		return fmt.Errorf("%s namespace path %s is not in %s", nsType, nsPath, nsDir)
+	}
+
+	f, err := os.Open(nsPath)
+	if err != nil {
+		return fmt.Errorf("%s namespace path: %w", nsType, err)
+	}
+	defer f.Close()
+
+	var stat unix.Statfs_t
+	if err := unix.Fstatfs(int(f.Fd()), &stat); err != nil {
+		return fmt.Errorf("statfs %s namespace path %s: %w", nsType, nsPath, err)
+	}
+	if stat.Type != unix.NSFS_MAGIC {
+		return fmt.Errorf("%s namespace path %s is not a namespace", nsType, nsPath)
+	}
+
+	gotFlag, err := unix.IoctlRetInt(int(f.Fd()), unix.NS_GET_NSTYPE)
+	if err != nil {
+		return fmt.Errorf("get type of %s namespace path %s: %w", nsType, nsPath, err)
+	}
+	if gotFlag != wantFlag {
+		for gotType, flag := range nsTypeToCloneFlag {
+			if flag == gotFlag {
+				return fmt.Errorf("%s namespace path %s is a %s namespace", nsType, nsPath, gotType)
+			}
+		}
+		return fmt.Errorf("%s namespace path %s is a namespace of unknown type %#x", nsType, nsPath, gotFlag)
+	}
+	return nil
+}
+
+// IsNamespacePinned returns true if nsPath is a namespace, which is the case
+// as long as the namespace is bind mounted there.
+func IsNamespacePinned(nsPath string) bool {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(nsPath, &stat); err != nil {
+		return false
+	}
+	return stat.Type == unix.NSFS_MAGIC
+}
+
 // dirForType returns the sub-directory for that particular NSType
 // which is of the form `$namespaceDir/$nsType+"ns"`.
 func (mgr *NamespaceManager) dirForType(ns NSType) string {
@@ -0,0 +1,132 @@
+package nsmgr_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/L-F-Z/cri-t/internal/config/nsmgr"
+)
+
+var _ = t.Describe("NamespaceManager", func() {
+	var (
+		namespacesDir string
+		sut           *nsmgr.NamespaceManager
+	)
+	BeforeEach(func() {
+		namespacesDir = t.MustTempDir("nsmgr")
+		sut = nsmgr.New(namespacesDir, "")
+		Expect(sut.Initialize()).To(Succeed())
+	})
+
+	// pinned returns a path in the namespaces directory of nsType which links
+	// to target.
+	pinned := func(nsType nsmgr.NSType, target string) string {
+		path := filepath.Join(namespacesDir, string(nsType)+"ns", "pinned")
+		Expect(os.Symlink(target, path)).To(Succeed())
+		return path
+	}
+
+	t.Describe("ValidateNamespacePath", func() {
+		It("should succeed with a namespace of the right type", func() {
+			// Given
+			path := pinned(nsmgr.NETNS, "/proc/self/ns/net")
+
+			// When
+			err := sut.ValidateNamespacePath(path, nsmgr.NETNS)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail with a missing path", func() {
+			// Given
+			path := filepath.Join(namespacesDir, "netns", "missing")
+
+			// When
+			err := sut.ValidateNamespacePath(path, nsmgr.NETNS)
+
+			// Then
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+
+		It("should fail with a namespace of the wrong type", func() {
+			// Given
+			path := pinned(nsmgr.NETNS, "/proc/self/ns/ipc")
+
+			// When
+			err := sut.ValidateNamespacePath(path, nsmgr.NETNS)
+
+			// Then
+			Expect(err).To(MatchError(ContainSubstring("is a ipc namespace")))
+		})
+
+		It("should fail with a file which is not a namespace", func() {
+			// Given
+			path := filepath.Join(namespacesDir, "utsns", "file")
+			Expect(os.WriteFile(path, nil, 0o644)).To(Succeed())
+
+			// When
+			err := sut.ValidateNamespacePath(path, nsmgr.UTSNS)
+
+			// Then
+			Expect(err).To(MatchError(ContainSubstring("is not a namespace")))
+		})
+
+		It("should fail with a path outside of the namespaces directory", func() {
+			// Given
+			// When
+			err := sut.ValidateNamespacePath("/proc/self/ns/net", nsmgr.NETNS)
+
+			// Then
+			Expect(err).To(MatchError(ContainSubstring("is not in")))
+		})
+
+		It("should fail with a path in the directory of another type", func() {
+			// Given
+			path := pinned(nsmgr.IPCNS, "/proc/self/ns/net")
+
+			// When
+			err := sut.ValidateNamespacePath(path, nsmgr.NETNS)
+
+			// Then
+			Expect(err).To(MatchError(ContainSubstring("is not in")))
+		})
+	})
+
+	t.Describe("IsNamespacePinned", func() {
+		It("should be true for a namespace", func() {
+			// Given
+			path := pinned(nsmgr.NETNS, "/proc/self/ns/net")
+
+			// When
+			res := nsmgr.IsNamespacePinned(path)
+
+			// Then
+			Expect(res).To(BeTrue())
+		})
+
+		It("should be false for a file which is not a namespace", func() {
+			// Given
+			path := filepath.Join(namespacesDir, "netns", "file")
+			Expect(os.WriteFile(path, nil, 0o644)).To(Succeed())
+
+			// When
+			res := nsmgr.IsNamespacePinned(path)
+
+			// Then
+			Expect(res).To(BeFalse())
+		})
+
+		It("should be false for a missing path", func() {
+			// Given
+			// When
+			res := nsmgr.IsNamespacePinned(filepath.Join(namespacesDir, "netns", "missing"))
+
+			// Then
+			Expect(res).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package nsmgr_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/L-F-Z/cri-t/test/framework"
+)
+
+func TestNamespaceManager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "NamespaceManager")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
@@ -91,10 +91,14 @@ func loadConfigFile(path string) (*rdt.Config, error) {
 	return c, nil
 }
 
+// ContainerClassFromAnnotations returns the RDT class requested for a
+// container by its or its pod's annotations. goresctrl verifies that the class
+// exists in the RDT configuration, so a class which does not exist results in
+// an error naming it instead of an invalid ClosID.
 func (c *Config) ContainerClassFromAnnotations(containerName string, containerAnnotations, podAnnotations map[string]string) (string, error) {
 	cls, err := rdt.ContainerClassFromAnnotations(containerName, containerAnnotations, podAnnotations)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("RDT class of container %q: %w", containerName, err)
 	}
 	if cls != "" && !c.Enabled() {
 		return "", fmt.Errorf("RDT disabled, refusing to set RDT class of container %q to %q", containerName, cls)
@@ -45,3 +45,19 @@ var _ = t.Describe("When parsing RDT config file", func() {
 		})
 	})
 })
+
+var _ = t.Describe("When getting the RDT class of a container", func() {
+	It("should return no class without annotations", func() {
+		cls, err := (&Config{}).ContainerClassFromAnnotations("ctr", nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cls).To(BeEmpty())
+	})
+
+	It("should fail naming a class which is not available", func() {
+		_, err := (&Config{}).ContainerClassFromAnnotations("ctr", nil, map[string]string{
+			"rdt.resources.beta.kubernetes.io/pod": "unknown",
+		})
+		Expect(err).To(MatchError(ContainSubstring(`"unknown"`)))
+		Expect(err).To(MatchError(ContainSubstring(`container "ctr"`)))
+	})
+})
@@ -0,0 +1,34 @@
+package seccomp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+)
+
+// ErrProfileNotFound is returned by ProfileFromDir if the profile directory
+// does not contain the named profile.
+var ErrProfileNotFound = errors.New("seccomp profile not found")
+
+// ProfileFromDir returns the path of the profile called name in the profile
+// directory dir, which is dir/name.json. The name cannot refer to a file
+// outside of dir.
+func ProfileFromDir(dir, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("empty seccomp profile name")
+	}
+	profilePath, err := securejoin.SecureJoin(dir, name+".json")
+	if err != nil {
+		return "", fmt.Errorf("resolve seccomp profile %q in %s: %w", name, dir, err)
+	}
+	info, err := os.Stat(profilePath)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && info.IsDir()) {
+		return "", fmt.Errorf("%w: %q in %s", ErrProfileNotFound, name, dir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("seccomp profile %q in %s: %w", name, dir, err)
+	}
+	return profilePath, nil
+}
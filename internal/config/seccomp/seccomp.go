@@ -20,6 +20,7 @@ import (
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/pkg/annotations"
 )
 
 var (
@@ -146,6 +147,7 @@ type Config struct {
 	enabled      bool
 	profile      *seccomp.Seccomp
 	notifierPath string
+	profileDir   string
 }
 
 // New creates a new default seccomp configuration instance.
@@ -167,6 +169,18 @@ func (c *Config) NotifierPath() string {
 	return c.notifierPath
 }
 
+// SetProfileDir sets the directory of the profiles which can be requested by
+// name with the seccomp profile annotation.
+func (c *Config) SetProfileDir(dir string) {
+	c.profileDir = dir
+}
+
+// ProfileDir returns the directory of the profiles which can be requested by
+// name.
+func (c *Config) ProfileDir() string {
+	return c.profileDir
+}
+
 // LoadProfile can be used to load a seccomp profile from the provided path.
 // This method will not fail if seccomp is disabled.
 func (c *Config) LoadProfile(profilePath string) error {
@@ -265,6 +279,26 @@ func (c *Config) Setup(
 	}
 
 	if profileField.ProfileType == types.SecurityProfile_RuntimeDefault {
+		if name := profileName(containerName, sandboxAnnotations, imageAnnotations); name != "" {
+			if c.profileDir == "" {
+				log.Warnf(ctx, "Ignoring seccomp profile %q of container %s because no seccomp_profile_dir is configured", name, containerName)
+			} else {
+				profilePath, err := ProfileFromDir(c.profileDir, name)
+				if err != nil {
+					return nil, "", err
+				}
+				file, err := os.ReadFile(profilePath)
+				if err != nil {
+					return nil, "", fmt.Errorf("unable to load seccomp profile %q: %w", profilePath, err)
+				}
+				notifier, err := c.applyProfileFromBytes(ctx, file, msgChan, containerID, sandboxAnnotations, specGenerator)
+				if err != nil {
+					return nil, "", fmt.Errorf("apply profile from bytes: %w", err)
+				}
+				return notifier, profilePath, nil
+			}
+		}
+
 		linuxSpecs, err := seccomp.LoadProfileFromConfig(
 			c.Profile(), specGenerator.Config,
 		)
@@ -296,6 +330,20 @@ func (c *Config) Setup(
 	return notifier, localhostRef, nil
 }
 
+// profileName returns the name of the profile requested for a container by
+// the SeccompProfileAnnotation. The annotation of the container on the pod
+// takes precedence over the one for the whole pod, which takes precedence
+// over the plain annotation on the image.
+func profileName(containerName string, sandboxAnnotations, imageAnnotations map[string]string) string {
+	if name := sandboxAnnotations[annotations.SeccompProfileAnnotation+"/"+containerName]; name != "" {
+		return name
+	}
+	if name := sandboxAnnotations[annotations.SeccompProfileAnnotation+"/POD"]; name != "" {
+		return name
+	}
+	return imageAnnotations[annotations.SeccompProfileAnnotation]
+}
+
 // Setup can be used to setup the seccomp profile.
 func (c *Config) applyProfileFromBytes(
 	ctx context.Context,
@@ -3,6 +3,7 @@ package seccomp_test
 import (
 	"context"
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -174,4 +175,45 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	t.Describe("ProfileFromDir", func() {
+		It("should resolve a named profile", func() {
+			// Given
+			dir := t.MustTempDir("profiles")
+			profile := filepath.Join(dir, "profile.json")
+			Expect(os.WriteFile(profile, []byte("{}"), 0o644)).To(Succeed())
+
+			// When
+			res, err := seccomp.ProfileFromDir(dir, "profile")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(profile))
+		})
+
+		It("should fail if the named profile does not exist", func() {
+			// Given
+			dir := t.MustTempDir("profiles")
+
+			// When
+			_, err := seccomp.ProfileFromDir(dir, "missing")
+
+			// Then
+			Expect(err).To(MatchError(seccomp.ErrProfileNotFound))
+		})
+
+		It("should not resolve a profile outside of the directory", func() {
+			// Given
+			root := t.MustTempDir("root")
+			dir := filepath.Join(root, "profiles")
+			Expect(os.Mkdir(dir, 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(root, "outside.json"), []byte("{}"), 0o644)).To(Succeed())
+
+			// When
+			_, err := seccomp.ProfileFromDir(dir, "../outside")
+
+			// Then
+			Expect(err).To(MatchError(seccomp.ErrProfileNotFound))
+		})
+	})
 })
@@ -49,6 +49,17 @@ func (c *Config) NotifierPath() string {
 	return ""
 }
 
+// SetProfileDir sets the directory of the profiles which can be requested by
+// name with the seccomp profile annotation.
+func (c *Config) SetProfileDir(dir string) {
+}
+
+// ProfileDir returns the directory of the profiles which can be requested by
+// name.
+func (c *Config) ProfileDir() string {
+	return ""
+}
+
 // LoadProfile can be used to load a seccomp profile from the provided path.
 // This method will not fail if seccomp is disabled.
 func (c *Config) LoadProfile(profilePath string) error {
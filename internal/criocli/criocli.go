@@ -212,6 +212,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("image-volumes") {
 		config.ImageVolumes = libconfig.ImageVolumesType(ctx.String("image-volumes"))
 	}
+	if ctx.IsSet("image-mounts-copy-on-write") {
+		config.ImageMountsCopyOnWrite = ctx.Bool("image-mounts-copy-on-write")
+	}
 	if ctx.IsSet("read-only") {
 		config.ReadOnly = ctx.Bool("read-only")
 	}
@@ -272,6 +275,15 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("grpc-max-send-msg-size") {
 		config.GRPCMaxSendMsgSize = ctx.Int("grpc-max-send-msg-size")
 	}
+	if ctx.IsSet("grpc-max-concurrent-streams") {
+		config.GRPCMaxConcurrentStreams = ctx.Int("grpc-max-concurrent-streams")
+	}
+	if ctx.IsSet("grpc-keepalive-time") {
+		config.GRPCKeepaliveTime = ctx.Duration("grpc-keepalive-time")
+	}
+	if ctx.IsSet("grpc-keepalive-timeout") {
+		config.GRPCKeepaliveTimeout = ctx.Duration("grpc-keepalive-timeout")
+	}
 	if ctx.IsSet("drop-infra-ctr") {
 		config.DropInfraCtr = ctx.Bool("drop-infra-ctr")
 	}
@@ -296,6 +308,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("stream-tls-key") {
 		config.StreamTLSKey = ctx.String("stream-tls-key")
 	}
+	if ctx.IsSet("stream-require-client-cert") {
+		config.StreamRequireClientCert = ctx.Bool("stream-require-client-cert")
+	}
 	if ctx.IsSet("stream-idle-timeout") {
 		config.StreamIdleTimeout = ctx.String("stream-idle-timeout")
 	}
@@ -383,6 +398,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("infra-ctr-cpuset") {
 		config.InfraCtrCPUSet = ctx.String("infra-ctr-cpuset")
 	}
+	if ctx.IsSet("infra-ctr-oom-score-adj") {
+		config.InfraCtrOOMScoreAdj = ctx.Int("infra-ctr-oom-score-adj")
+	}
 	if ctx.IsSet("shared-cpuset") {
 		config.SharedCPUSet = ctx.String("shared-cpuset")
 	}
@@ -398,6 +416,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("enable-pod-events") {
 		config.EnablePodEvents = ctx.Bool("enable-pod-events")
 	}
+	if ctx.IsSet("enable-criu-support") {
+		config.EnableCriuSupport = ctx.Bool("enable-criu-support")
+	}
 	if ctx.IsSet("hostnetwork-disable-selinux") {
 		config.HostNetworkDisableSELinux = ctx.Bool("hostnetwork-disable-selinux")
 	}
@@ -658,6 +679,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 	3. ignore: All volumes are just ignored and no action is taken.`,
 			EnvVars: []string{"CONTAINER_IMAGE_VOLUMES"},
 		},
+		&cli.BoolFlag{
+			Name:    "image-mounts-copy-on-write",
+			Usage:   "Make image mounts which are not requested read-only writable through a per container overlay upper directory.",
+			Value:   defConf.ImageMountsCopyOnWrite,
+			EnvVars: []string{"CONTAINER_IMAGE_MOUNTS_COPY_ON_WRITE"},
+		},
 		&cli.StringSliceFlag{
 			Name: "hooks-dir",
 			Usage: `Set the OCI hooks directory path (may be set multiple times)
@@ -946,6 +973,24 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.GRPCMaxSendMsgSize,
 			EnvVars: []string{"CONTAINER_GRPC_MAX_SEND_MSG_SIZE"},
 		},
+		&cli.IntFlag{
+			Name:    "grpc-max-concurrent-streams",
+			Usage:   "Maximum number of concurrent grpc streams of each client connection. If set to 0, the streams are not limited.",
+			Value:   defConf.GRPCMaxConcurrentStreams,
+			EnvVars: []string{"CONTAINER_GRPC_MAX_CONCURRENT_STREAMS"},
+		},
+		&cli.DurationFlag{
+			Name:    "grpc-keepalive-time",
+			Usage:   "Duration after which the grpc server pings an idle client connection to check whether it is still alive. If set to 0, the grpc default of 2 hours is used.",
+			Value:   defConf.GRPCKeepaliveTime,
+			EnvVars: []string{"CONTAINER_GRPC_KEEPALIVE_TIME"},
+		},
+		&cli.DurationFlag{
+			Name:    "grpc-keepalive-timeout",
+			Usage:   "Duration the grpc server waits for the answer to a keepalive ping before it closes the connection. If set to 0, the grpc default of 20 seconds is used.",
+			Value:   defConf.GRPCKeepaliveTimeout,
+			EnvVars: []string{"CONTAINER_GRPC_KEEPALIVE_TIMEOUT"},
+		},
 		&cli.BoolFlag{
 			Name:    "drop-infra-ctr",
 			Usage:   "Determines whether pods are created without an infra container, when the pod is not using a pod level PID namespace.",
@@ -995,9 +1040,15 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars:   []string{"CONTAINER_TLS_KEY"},
 			TakesFile: true,
 		},
+		&cli.BoolFlag{
+			Name:    "stream-require-client-cert",
+			Usage:   "Enforce that clients of the encrypted stream present a certificate signed by the stream-tls-ca, by refusing to start without stream-tls-ca. Client certificates are always required if stream-tls-ca is set.",
+			EnvVars: []string{"CONTAINER_TLS_REQUIRE_CLIENT_CERT"},
+			Value:   defConf.StreamRequireClientCert,
+		},
 		&cli.StringFlag{
 			Name:    "stream-idle-timeout",
-			Usage:   "Length of time until open streams terminate due to lack of activity.",
+			Usage:   "Length of time until open streams of exec, attach and port forward requests terminate due to lack of activity. Setting it to \"0\" keeps idle streams open.",
 			EnvVars: []string{"STREAM_IDLE_TIMEOUT"},
 			Value:   defConf.StreamIdleTimeout,
 		},
@@ -1038,6 +1089,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_INFRA_CTR_CPUSET"},
 			Value:   defConf.InfraCtrCPUSet,
 		},
+		&cli.IntFlag{
+			Name:    "infra-ctr-oom-score-adj",
+			Usage:   "The OOM score adjustment of infra containers, between -1000 and 1000.",
+			EnvVars: []string{"CONTAINER_INFRA_CTR_OOM_SCORE_ADJ"},
+			Value:   defConf.InfraCtrOOMScoreAdj,
+		},
 		&cli.StringFlag{
 			Name:    "shared-cpuset",
 			Usage:   "CPUs set that will be used for guaranteed containers that want access to shared cpus",
@@ -1080,6 +1137,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "If true, CRI-O starts sending the container events to the kubelet",
 			EnvVars: []string{"ENABLE_POD_EVENTS"},
 		},
+		&cli.BoolFlag{
+			Name:    "enable-criu-support",
+			Usage:   "Enable checkpointing and restoring containers with CRIU, which requires the criu binary in $PATH.",
+			Value:   defConf.EnableCriuSupport,
+			EnvVars: []string{"CONTAINER_ENABLE_CRIU_SUPPORT"},
+		},
 		&cli.StringFlag{
 			Name:  "irqbalance-config-restore-file",
 			Value: defConf.IrqBalanceConfigRestoreFile,
@@ -30,6 +30,7 @@ import (
 	"github.com/L-F-Z/cri-t/internal/config/nsmgr"
 	"github.com/L-F-Z/cri-t/internal/lib/constants"
 	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/naming"
 	"github.com/L-F-Z/cri-t/internal/oci"
 	"github.com/L-F-Z/cri-t/pkg/annotations"
 	"github.com/L-F-Z/cri-t/pkg/config"
@@ -46,7 +47,7 @@ type Container interface {
 	// SetNameAndID sets a container name and ID
 	// It can either generate a new ID or use an existing ID
 	// if specified as parameter (for container restore)
-	SetNameAndID(string) error
+	SetNameAndID(naming.Strategy, string) error
 
 	// Config returns the container CRI configuration
 	Config() *types.ContainerConfig
@@ -102,7 +103,7 @@ type Container interface {
 
 	// SpecAddMount adds a mount to the container's spec
 	// it takes the rspec mount object
-	// if there is already a mount at the path specified, it removes it.
+	// if there are already mounts at the path specified, it replaces them.
 	SpecAddMount(rspec.Mount)
 
 	// SpecAddAnnotations adds annotations to the spec.
@@ -162,12 +163,23 @@ func New() (Container, error) {
 	}, nil
 }
 
-// SpecAddMount adds a specified mount to the spec
+// SpecAddMount adds a specified mount to the spec. Mounts which were added
+// before at the same destination get replaced, so that there is only ever a
+// single mount per destination.
 //
 //nolint:gocritic // passing the spec mount around here is intentional
 func (c *container) SpecAddMount(r rspec.Mount) {
-	c.spec.RemoveMount(r.Destination)
-	c.spec.AddMount(r)
+	dest := filepath.Clean(r.Destination)
+	mounts := c.spec.Mounts()
+	kept := make([]rspec.Mount, 0, len(mounts)+1)
+	for i := range mounts {
+		if filepath.Clean(mounts[i].Destination) != dest {
+			kept = append(kept, mounts[i])
+			continue
+		}
+		logrus.Debugf("Overriding mount of %s at %s with %s", mounts[i].Source, mounts[i].Destination, r.Source)
+	}
+	c.spec.Config.Mounts = append(kept, r)
 }
 
 // SpecAddAnnotation adds all annotations to the spec.
@@ -331,8 +343,8 @@ func (c *container) SetConfig(cfg *types.ContainerConfig, sboxConfig *types.PodS
 	return nil
 }
 
-// SetNameAndID sets a container name and ID.
-func (c *container) SetNameAndID(oldID string) error {
+// SetNameAndID sets a container name, generated by the naming strategy, and ID.
+func (c *container) SetNameAndID(strategy naming.Strategy, oldID string) error {
 	if c.config == nil {
 		return errors.New("config is not set")
 	}
@@ -351,17 +363,8 @@ func (c *container) SetNameAndID(oldID string) error {
 	} else {
 		id = oldID
 	}
-	name := strings.Join([]string{
-		"k8s",
-		c.config.Metadata.Name,
-		c.sboxConfig.Metadata.Name,
-		c.sboxConfig.Metadata.Namespace,
-		c.sboxConfig.Metadata.Uid,
-		strconv.FormatUint(uint64(c.config.Metadata.Attempt), 10),
-	}, "_")
-
 	c.id = id
-	c.name = name
+	c.name = strategy.ContainerName(c.config.Metadata, c.sboxConfig.Metadata)
 	return nil
 }
 
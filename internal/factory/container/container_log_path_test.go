@@ -4,6 +4,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/naming"
 )
 
 const (
@@ -66,7 +68,7 @@ var _ = t.Describe("Container:LogPath", func() {
 
 		// When
 		Expect(sut.SetConfig(config, sboxConfig)).To(Succeed())
-		Expect(sut.SetNameAndID("")).To(Succeed())
+		Expect(sut.SetNameAndID(naming.Default(), "")).To(Succeed())
 
 		// Then
 		logPath, err := sut.LogPath(providedLogDir)
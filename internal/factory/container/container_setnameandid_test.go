@@ -6,6 +6,7 @@ import (
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/L-F-Z/cri-t/internal/factory/container"
+	"github.com/L-F-Z/cri-t/internal/naming"
 )
 
 // The actual test suite.
@@ -27,7 +28,7 @@ var _ = t.Describe("Container:SetNameAndID", func() {
 		setupContainerWithMetadata(metadata)
 
 		// When
-		err := sut.SetNameAndID("")
+		err := sut.SetNameAndID(naming.Default(), "")
 
 		// Then
 		Expect(err).ToNot(HaveOccurred())
@@ -50,7 +51,7 @@ var _ = t.Describe("Container:SetNameAndID", func() {
 		setupContainerWithMetadata(metadata)
 
 		// When
-		err := sut.SetNameAndID("use-this-ID")
+		err := sut.SetNameAndID(naming.Default(), "use-this-ID")
 
 		// Then
 		Expect(err).ToNot(HaveOccurred())
@@ -60,13 +61,30 @@ var _ = t.Describe("Container:SetNameAndID", func() {
 		Expect(sut.Name()).To(ContainSubstring(uid))
 	})
 
+	It("should succeed with a custom naming strategy", func() {
+		// Given
+		metadata := &types.PodSandboxMetadata{
+			Name: "pod", Uid: "uid", Namespace: "namespace",
+		}
+		setupContainerWithMetadata(metadata)
+		strategy, err := naming.Get(naming.Descriptive)
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		err = sut.SetNameAndID(strategy, "")
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sut.Name()).To(Equal("namespace_pod_name_0_uid"))
+	})
+
 	It("should succeed with empty sandbox metadata", func() {
 		// Given
 		metadata := &types.PodSandboxMetadata{}
 		setupContainerWithMetadata(metadata)
 
 		// When
-		err := sut.SetNameAndID("")
+		err := sut.SetNameAndID(naming.Default(), "")
 
 		// Then
 		Expect(err).ToNot(HaveOccurred())
@@ -78,7 +96,7 @@ var _ = t.Describe("Container:SetNameAndID", func() {
 		container, err := container.New()
 		Expect(err).ToNot(HaveOccurred())
 
-		err = container.SetNameAndID("")
+		err = container.SetNameAndID(naming.Default(), "")
 
 		// Then
 		Expect(container).ToNot(BeNil())
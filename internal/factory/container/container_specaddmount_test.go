@@ -0,0 +1,80 @@
+package container_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// The actual test suite.
+var _ = t.Describe("Container:SpecAddMount", func() {
+	mountsAt := func(dest string) []rspec.Mount {
+		mounts := []rspec.Mount{}
+		for _, m := range sut.Spec().Mounts() {
+			if m.Destination == dest || m.Destination == dest+"/" {
+				mounts = append(mounts, m)
+			}
+		}
+		return mounts
+	}
+
+	It("should add a mount", func() {
+		// Given
+		mount := rspec.Mount{Destination: "/data", Source: "/host/data", Type: "bind"}
+
+		// When
+		sut.SpecAddMount(mount)
+
+		// Then
+		Expect(mountsAt("/data")).To(Equal([]rspec.Mount{mount}))
+	})
+
+	It("should replace a mount at the same destination", func() {
+		// Given
+		sut.SpecAddMount(rspec.Mount{Destination: "/etc/hostname", Source: "/first", Type: "bind"})
+		mount := rspec.Mount{Destination: "/etc/hostname", Source: "/second", Type: "bind"}
+
+		// When
+		sut.SpecAddMount(mount)
+
+		// Then
+		Expect(mountsAt("/etc/hostname")).To(Equal([]rspec.Mount{mount}))
+	})
+
+	It("should replace a mount at an unclean destination", func() {
+		// Given
+		sut.SpecAddMount(rspec.Mount{Destination: "/data/", Source: "/first", Type: "bind"})
+		mount := rspec.Mount{Destination: "/data", Source: "/second", Type: "bind"}
+
+		// When
+		sut.SpecAddMount(mount)
+
+		// Then
+		Expect(mountsAt("/data")).To(Equal([]rspec.Mount{mount}))
+	})
+
+	It("should replace all duplicate mounts at the same destination", func() {
+		// Given
+		sut.Spec().AddMount(rspec.Mount{Destination: "/data", Source: "/first", Type: "bind"})
+		sut.Spec().AddMount(rspec.Mount{Destination: "/data", Source: "/second", Type: "bind"})
+		mount := rspec.Mount{Destination: "/data", Source: "/third", Type: "bind"}
+
+		// When
+		sut.SpecAddMount(mount)
+
+		// Then
+		Expect(mountsAt("/data")).To(Equal([]rspec.Mount{mount}))
+	})
+
+	It("should keep mounts at other destinations", func() {
+		// Given
+		other := rspec.Mount{Destination: "/other", Source: "/host/other", Type: "bind"}
+		sut.SpecAddMount(other)
+
+		// When
+		sut.SpecAddMount(rspec.Mount{Destination: "/data", Source: "/host/data", Type: "bind"})
+
+		// Then
+		Expect(mountsAt("/other")).To(Equal([]rspec.Mount{other}))
+	})
+})
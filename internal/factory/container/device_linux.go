@@ -19,6 +19,10 @@ import (
 	"github.com/L-F-Z/cri-t/utils"
 )
 
+// overflowID is the ID owning files whose host owner is not mapped into the
+// user namespace of the container.
+const overflowID = 65534
+
 func (c *container) SpecAddDevices(configuredDevices, annotationDevices []devicecfg.Device, privilegedWithoutHostDevices, enableDeviceOwnershipFromSecurityContext bool) error {
 	// First, clear the existing devices from the spec
 	c.Spec().Config.Linux.Devices = []rspec.LinuxDevice{}
@@ -61,14 +65,17 @@ func (c *container) specAddHostDevicesIfPrivileged(privilegedWithoutHostDevices
 	if err != nil {
 		return err
 	}
+	uidMappings, gidMappings := c.usernsMappings()
 	for _, hostDevice := range hostDevices {
+		uid := hostToContainerID(hostDevice.Uid, uidMappings)
+		gid := hostToContainerID(hostDevice.Gid, gidMappings)
 		rd := rspec.LinuxDevice{
 			Path:  hostDevice.Path,
 			Type:  string(hostDevice.Type),
 			Major: hostDevice.Major,
 			Minor: hostDevice.Minor,
-			UID:   &hostDevice.Uid,
-			GID:   &hostDevice.Gid,
+			UID:   &uid,
+			GID:   &gid,
 		}
 		if hostDevice.Major == 0 && hostDevice.Minor == 0 {
 			// Invalid device, most likely a symbolic link, skip it.
@@ -87,6 +94,7 @@ func (c *container) specAddHostDevicesIfPrivileged(privilegedWithoutHostDevices
 
 func (c *container) specAddContainerConfigDevices(enableDeviceOwnershipFromSecurityContext bool) error {
 	sp := c.Spec().Config
+	uidMappings, gidMappings := c.usernsMappings()
 
 	for _, device := range c.Config().Devices {
 		// If we are privileged, we have access to devices on the host.
@@ -115,8 +123,8 @@ func (c *container) specAddContainerConfigDevices(enableDeviceOwnershipFromSecur
 				Type:  string(dev.Type),
 				Major: dev.Major,
 				Minor: dev.Minor,
-				UID:   getDeviceUserGroupID(c.Config().Linux.SecurityContext.RunAsUser, dev.Uid, enableDeviceOwnershipFromSecurityContext),
-				GID:   getDeviceUserGroupID(c.Config().Linux.SecurityContext.RunAsGroup, dev.Gid, enableDeviceOwnershipFromSecurityContext),
+				UID:   getDeviceUserGroupID(c.Config().Linux.SecurityContext.RunAsUser, hostToContainerID(dev.Uid, uidMappings), enableDeviceOwnershipFromSecurityContext),
+				GID:   getDeviceUserGroupID(c.Config().Linux.SecurityContext.RunAsGroup, hostToContainerID(dev.Gid, gidMappings), enableDeviceOwnershipFromSecurityContext),
 			}
 			c.Spec().AddDevice(rd)
 			sp.Linux.Resources.Devices = append(sp.Linux.Resources.Devices, rspec.LinuxDeviceCgroup{
@@ -146,13 +154,15 @@ func (c *container) specAddContainerConfigDevices(enableDeviceOwnershipFromSecur
 						return nil
 					}
 					cPath := strings.Replace(dpath, path, device.ContainerPath, 1)
+					uid := hostToContainerID(childDevice.Uid, uidMappings)
+					gid := hostToContainerID(childDevice.Gid, gidMappings)
 					rd := rspec.LinuxDevice{
 						Path:  cPath,
 						Type:  string(childDevice.Type),
 						Major: childDevice.Major,
 						Minor: childDevice.Minor,
-						UID:   &childDevice.Uid,
-						GID:   &childDevice.Gid,
+						UID:   &uid,
+						GID:   &gid,
 					}
 					c.Spec().AddDevice(rd)
 					sp.Linux.Resources.Devices = append(sp.Linux.Resources.Devices, rspec.LinuxDeviceCgroup{
@@ -243,16 +253,52 @@ func (c *container) specInjectCDIDevices() error {
 	return nil
 }
 
+// usernsMappings returns the UID and GID mappings of the user namespace in
+// the generated spec of the container, or nil if the spec has no user
+// namespace. Devices only need to be remapped if the runtime actually creates
+// the container in a user namespace with these mappings.
+func (c *container) usernsMappings() (uidMappings, gidMappings []rspec.LinuxIDMapping) {
+	linux := c.Spec().Config.Linux
+	if linux == nil {
+		return nil, nil
+	}
+	for _, ns := range linux.Namespaces {
+		if ns.Type == rspec.UserNamespace {
+			return linux.UIDMappings, linux.GIDMappings
+		}
+	}
+	return nil, nil
+}
+
+// hostToContainerID translates the host ID hostID into the ID space of a user
+// namespace with the provided mappings. Without mappings, hostID is returned
+// unchanged. Host IDs which are not mapped appear as overflowID in the user
+// namespace.
+func hostToContainerID(hostID uint32, mappings []rspec.LinuxIDMapping) uint32 {
+	if len(mappings) == 0 {
+		return hostID
+	}
+	for _, m := range mappings {
+		if hostID >= m.HostID && hostID-m.HostID < m.Size {
+			return m.ContainerID + hostID - m.HostID
+		}
+	}
+	return overflowID
+}
+
 // getDeviceUserGroupID() is used to find the right uid/gid
 // value for the device node created in the container namespace.
 // The runtime executes mknod() and chmod()s the created
 // device with the values returned here.
 //
+// In case of user namespaces, hostVal has to be translated into the ID
+// space of the user namespace by hostToContainerID already, while
+// RunAsUser/RunAsGroup are IDs of the user namespace and used as is.
+//
 // TODO(mythi): In case of user namespaces, the runtime simply bind
 // mounts the devices from the host. Additional logic is needed
 // to check that the runtimes effective UID/GID on the host has the
-// permissions to access the device node and/or the right user namespace
-// mappings are created.
+// permissions to access the device node.
 //
 // CRI-O has an experimental support for setting user namespace mappings
 // via annotations when pod's securitycontext runs as root/uid=0. When
@@ -185,6 +185,116 @@ var _ = t.Describe("Container", func() {
 		}
 	})
 
+	t.Describe("SpecAddDevice", func() {
+		type testdata struct {
+			testDescription   string
+			usernsOptions     *types.UserNamespace
+			specUIDMappings   []rspec.LinuxIDMapping
+			specGIDMappings   []rspec.LinuxIDMapping
+			expectedDeviceUID uint32
+			expectedDeviceGID uint32
+		}
+		hostDevices, err := devices.HostDevices()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hostDevices).NotTo(BeEmpty())
+		testDevice := hostDevices[0]
+
+		tests := []testdata{
+			{
+				testDescription: "Expect Devices Uid/Gid to be the same as the device Uid/Gid on the host without user namespace",
+				usernsOptions: &types.UserNamespace{
+					Mode: types.NamespaceMode_NODE,
+				},
+				expectedDeviceUID: testDevice.Uid,
+				expectedDeviceGID: testDevice.Gid,
+			},
+			{
+				testDescription: "Expect Devices Uid/Gid to be unchanged if the spec has no user namespace, even if the pod requests one",
+				usernsOptions: &types.UserNamespace{
+					Mode: types.NamespaceMode_POD,
+					Uids: []*types.IDMapping{
+						{HostId: testDevice.Uid, ContainerId: 1000, Length: 1},
+					},
+					Gids: []*types.IDMapping{
+						{HostId: testDevice.Gid, ContainerId: 2000, Length: 1},
+					},
+				},
+				expectedDeviceUID: testDevice.Uid,
+				expectedDeviceGID: testDevice.Gid,
+			},
+			{
+				testDescription: "Expect Devices Uid/Gid to be remapped into the user namespace of the spec",
+				specUIDMappings: []rspec.LinuxIDMapping{
+					{HostID: testDevice.Uid + 1, ContainerID: 0, Size: 1},
+					{HostID: testDevice.Uid, ContainerID: 1000, Size: 1},
+				},
+				specGIDMappings: []rspec.LinuxIDMapping{
+					{HostID: testDevice.Gid, ContainerID: 2000, Size: 1},
+				},
+				expectedDeviceUID: 1000,
+				expectedDeviceGID: 2000,
+			},
+			{
+				testDescription: "Expect Devices Uid/Gid to be the overflow ID if they are not mapped into the user namespace of the spec",
+				specUIDMappings: []rspec.LinuxIDMapping{
+					{HostID: testDevice.Uid + 1, ContainerID: 0, Size: 65536},
+				},
+				specGIDMappings: []rspec.LinuxIDMapping{
+					{HostID: testDevice.Gid + 1, ContainerID: 0, Size: 65536},
+				},
+				expectedDeviceUID: 65534,
+				expectedDeviceGID: 65534,
+			},
+		}
+
+		for _, test := range tests {
+			It(test.testDescription, func() {
+				// Given
+				config := &types.ContainerConfig{
+					Metadata: &types.ContainerMetadata{Name: "name"},
+					Linux: &types.LinuxContainerConfig{
+						SecurityContext: &types.LinuxContainerSecurityContext{
+							NamespaceOptions: &types.NamespaceOption{
+								UsernsOptions: test.usernsOptions,
+							},
+						},
+					},
+					Devices: []*types.Device{
+						{
+							ContainerPath: testDevice.Path,
+							HostPath:      testDevice.Path,
+							Permissions:   "r",
+						},
+					},
+				}
+				sboxConfig := &types.PodSandboxConfig{
+					Linux: &types.LinuxPodSandboxConfig{
+						SecurityContext: &types.LinuxSandboxSecurityContext{},
+					},
+				}
+				Expect(sut.SetConfig(config, sboxConfig)).To(Succeed())
+				if test.specUIDMappings != nil {
+					Expect(sut.Spec().AddOrReplaceLinuxNamespace(string(rspec.UserNamespace), "")).To(Succeed())
+					for _, m := range test.specUIDMappings {
+						sut.Spec().AddLinuxUIDMapping(m.HostID, m.ContainerID, m.Size)
+					}
+					for _, m := range test.specGIDMappings {
+						sut.Spec().AddLinuxGIDMapping(m.HostID, m.ContainerID, m.Size)
+					}
+				}
+
+				// When
+				err := sut.SpecAddDevices(nil, nil, false, false)
+				// Then
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(sut.Spec().Config.Linux.Devices).To(HaveLen(1))
+				Expect(*sut.Spec().Config.Linux.Devices[0].UID).To(Equal(test.expectedDeviceUID))
+				Expect(*sut.Spec().Config.Linux.Devices[0].GID).To(Equal(test.expectedDeviceGID))
+			})
+		}
+	})
+
 	t.Describe("SpecAdd(CDI)Devices", func() {
 		writeCDISpecFiles := func(content []string) error {
 			if len(content) == 0 {
@@ -20,6 +20,12 @@ func (c *container) SpecAddNamespaces(sb SandboxIFace, targetCtr *oci.Container,
 		return fmt.Errorf("failed to configure namespaces in container create: %w", err)
 	}
 
+	if sb.HostUTS() {
+		if err := c.spec.RemoveLinuxNamespace(string(rspec.UTSNamespace)); err != nil {
+			return err
+		}
+	}
+
 	sc := c.config.Linux.SecurityContext
 
 	if sc.NamespaceOptions.Network == types.NamespaceMode_NODE {
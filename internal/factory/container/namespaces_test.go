@@ -2,6 +2,7 @@ package container_test
 
 import (
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -116,6 +117,40 @@ var _ = t.Describe("Container:SpecAddNamespaces", func() {
 			Expect(specNs.Type).NotTo(Equal(rspec.PIDNamespace))
 		}
 	})
+	It("should drop UTS if the sandbox uses the host UTS", func() {
+		// Given
+		ctrConfig := &types.ContainerConfig{
+			Metadata: &types.ContainerMetadata{Name: "name"},
+			Linux: &types.LinuxContainerConfig{
+				SecurityContext: &types.LinuxContainerSecurityContext{
+					NamespaceOptions: &types.NamespaceOption{
+						Network: types.NamespaceMode_NODE,
+						Ipc:     types.NamespaceMode_POD,
+						Pid:     types.NamespaceMode_CONTAINER,
+					},
+				},
+			},
+		}
+
+		sboxConfig := &types.PodSandboxConfig{}
+		builder := sandbox.NewBuilder()
+		builder.SetCreatedAt(time.Now())
+		Expect(builder.SetCRISandbox("sandboxID", map[string]string{}, map[string]string{}, &types.PodSandboxMetadata{})).To(Succeed())
+		builder.SetHostNetwork(true)
+		builder.SetHostUTS(true)
+		sb, err := builder.GetSandbox()
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		Expect(sut.SetConfig(ctrConfig, sboxConfig)).To(Succeed())
+		Expect(sut.SpecAddNamespaces(sb, nil, nil)).To(Succeed())
+
+		// Then
+		spec := sut.Spec()
+		for _, specNs := range spec.Config.Linux.Namespaces {
+			Expect(specNs.Type).NotTo(Equal(rspec.UTSNamespace))
+		}
+	})
 	It("should use pod PID", func() {
 		// Given
 		ctrConfig := &types.ContainerConfig{
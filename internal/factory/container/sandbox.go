@@ -35,4 +35,7 @@ type SandboxIFace interface {
 
 	// NamespaceOptions returns the namespace options for the sandbox.
 	NamespaceOptions() *types.NamespaceOption
+
+	// HostUTS returns whether the sandbox runs in the host UTS namespace.
+	HostUTS() bool
 }
@@ -0,0 +1,173 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/L-F-Z/TaskC/pkg/bundle"
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	"github.com/containers/storage/pkg/archive"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+// ErrCheckpointImageMismatch is returned by ImportCheckpoint if the checkpoint
+// was taken from a container of another image.
+var ErrCheckpointImageMismatch = errors.New("checkpoint does not match the image of the container")
+
+// ContainerCheckpointOptions are the options of ContainerCheckpoint.
+type ContainerCheckpointOptions struct {
+	// TargetFile is the archive the checkpoint gets exported to. It must not
+	// exist yet. If it is empty, the checkpoint is only kept in the
+	// checkpoint directory of the container.
+	TargetFile string
+	// KeepRunning leaves the container running after the checkpoint. If it
+	// is not set, the container is stopped by the checkpoint.
+	KeepRunning bool
+}
+
+// ContainerCheckpoint checkpoints a running container with CRIU into its
+// checkpoint directory, and exports the checkpoint if requested. It returns
+// the ID of the checkpointed container. The error wraps
+// oci.ErrCheckpointRestoreNotSupported if CRIU or the runtime of the
+// container cannot checkpoint it.
+func (c *ContainerServer) ContainerCheckpoint(ctx context.Context, ctr *oci.Container, opts *ContainerCheckpointOptions) (string, error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	if state := ctr.State(); state.Status != oci.ContainerStateRunning {
+		return "", fmt.Errorf("container %s is not running: %s", ctr.ID(), state.Status)
+	}
+
+	specgen := ctr.Spec()
+	if specgen.Linux == nil {
+		return "", fmt.Errorf("container %s has no linux spec", ctr.ID())
+	}
+
+	if err := c.writeCheckpointMetadata(ctr, &specgen); err != nil {
+		return "", err
+	}
+
+	if err := c.runtime.CheckpointContainer(ctx, ctr, &specgen, opts.KeepRunning); err != nil {
+		return "", fmt.Errorf("checkpoint container %s: %w", ctr.ID(), err)
+	}
+
+	if !opts.KeepRunning {
+		if err := c.ContainerStateToDisk(ctx, ctr); err != nil {
+			log.Warnf(ctx, "Unable to write containers %s state to disk: %v", ctr.ID(), err)
+		}
+	}
+
+	if opts.TargetFile != "" {
+		if err := exportCheckpoint(ctr, opts.TargetFile); err != nil {
+			return "", err
+		}
+	}
+
+	return ctr.ID(), nil
+}
+
+// writeCheckpointMetadata writes the config and spec dumps, which are needed
+// to restore the checkpoint, to the directory of the container.
+func (c *ContainerServer) writeCheckpointMetadata(ctr *oci.Container, specgen *rspec.Spec) error {
+	config := &metadata.ContainerConfig{
+		ID:              ctr.ID(),
+		Name:            ctr.Name(),
+		RootfsImageName: ctr.UserRequestedImage(),
+		OCIRuntime:      ctr.RuntimeHandler(),
+		CreatedTime:     ctr.CreatedAt(),
+		CheckpointedAt:  time.Now(),
+	}
+	if imageID := ctr.ImageID(); imageID != nil {
+		config.RootfsImageRef = imageID.String()
+	}
+	if imageName := ctr.SomeNameOfTheImage(); imageName != nil {
+		config.RootfsImage = imageName.String()
+	}
+
+	if _, err := metadata.WriteJSONFile(config, ctr.Dir(), metadata.ConfigDumpFile); err != nil {
+		return fmt.Errorf("write checkpoint config of container %s: %w", ctr.ID(), err)
+	}
+	if _, err := metadata.WriteJSONFile(specgen, ctr.Dir(), metadata.SpecDumpFile); err != nil {
+		return fmt.Errorf("write checkpoint spec of container %s: %w", ctr.ID(), err)
+	}
+	return nil
+}
+
+// exportCheckpoint writes the checkpoint and its metadata to the new tar
+// archive targetFile. Existing files, including symlinks, are not
+// overwritten.
+func exportCheckpoint(ctr *oci.Container, targetFile string) (retErr error) {
+	input, err := archive.TarWithOptions(ctr.Dir(), &archive.TarOptions{
+		Compression:      archive.Uncompressed,
+		IncludeSourceDir: true,
+		IncludeFiles: []string{
+			metadata.CheckpointDirectory,
+			metadata.ConfigDumpFile,
+			metadata.SpecDumpFile,
+			metadata.DumpLogFile,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create checkpoint archive of container %s: %w", ctr.ID(), err)
+	}
+	defer input.Close()
+
+	output, err := os.OpenFile(targetFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("open checkpoint archive %s: %w", targetFile, err)
+	}
+	defer func() {
+		if err := output.Close(); err != nil {
+			retErr = errors.Join(retErr, err)
+		}
+		if retErr != nil {
+			os.Remove(targetFile)
+		}
+	}()
+
+	if _, err := io.Copy(output, input); err != nil {
+		return fmt.Errorf("write checkpoint archive %s: %w", targetFile, err)
+	}
+	return nil
+}
+
+// ImportCheckpoint copies the checkpoint at source into dir, the directory of
+// the container to restore. The source is either an archive written by
+// ContainerCheckpoint or a directory holding its content. The checkpoint has to
+// be taken from a container of imageID, otherwise the returned error wraps
+// ErrCheckpointImageMismatch.
+func ImportCheckpoint(source, dir string, imageID bundle.BundleId) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("checkpoint %s: %w", source, err)
+	}
+	if info.IsDir() {
+		err = archive.NewDefaultArchiver().CopyWithTar(source, dir)
+	} else {
+		err = archive.UntarPath(source, dir)
+	}
+	if err != nil {
+		return fmt.Errorf("import checkpoint %s: %w", source, err)
+	}
+
+	config, _, err := metadata.ReadContainerCheckpointConfigDump(dir)
+	if err != nil {
+		return fmt.Errorf("read config of checkpoint %s: %w", source, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, metadata.CheckpointDirectory, "inventory.img")); err != nil {
+		return fmt.Errorf("checkpoint %s is incomplete: %w", source, err)
+	}
+	if config.RootfsImageRef != imageID.String() {
+		return fmt.Errorf("%w: checkpoint %s of container %s was taken with image %s, the container uses image %s",
+			ErrCheckpointImageMismatch, source, config.Name, config.RootfsImageRef, imageID)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package lib
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/L-F-Z/TaskC/pkg/bundle"
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+// newCheckpointedContainer returns a container of imageID with the files of
+// a checkpoint in its directory.
+func newCheckpointedContainer(t *testing.T, imageID bundle.BundleId) *oci.Container {
+	t.Helper()
+	dir := t.TempDir()
+	ctr, err := oci.NewContainer("containerID", "containerName", "", "",
+		map[string]string{}, map[string]string{}, map[string]string{}, "", nil, &imageID, "",
+		&types.ContainerMetadata{}, "sandboxID", false, false,
+		false, "", dir, time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{
+		filepath.Join(metadata.CheckpointDirectory, "inventory.img"),
+		"userdata.json",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sut := &ContainerServer{}
+	if err := sut.writeCheckpointMetadata(ctr, &rspec.Spec{}); err != nil {
+		t.Fatal(err)
+	}
+	return ctr
+}
+
+func TestExportCheckpoint(t *testing.T) {
+	ctr := newCheckpointedContainer(t, "imageID")
+	target := filepath.Join(t.TempDir(), "checkpoint.tar")
+
+	if err := exportCheckpoint(ctr, target); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var names []string
+	reader := tar.NewReader(f)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, filepath.Clean(header.Name))
+	}
+	for _, want := range []string{
+		filepath.Join(metadata.CheckpointDirectory, "inventory.img"),
+		metadata.ConfigDumpFile,
+		metadata.SpecDumpFile,
+	} {
+		if !slices.Contains(names, want) {
+			t.Errorf("expected %s in the checkpoint archive, got %v", want, names)
+		}
+	}
+	if slices.Contains(names, "userdata.json") {
+		t.Errorf("expected only checkpoint files in the archive, got %v", names)
+	}
+}
+
+func TestExportCheckpointExistingTarget(t *testing.T) {
+	ctr := newCheckpointedContainer(t, "imageID")
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing")
+	if err := os.WriteFile(existing, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(filepath.Join(dir, "linked"), link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{existing, link} {
+		if err := exportCheckpoint(ctr, target); err == nil {
+			t.Errorf("expected the export to %s to fail", target)
+		}
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "data" {
+		t.Errorf("expected the existing file to be kept, got %q, %v", data, err)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "linked")); !os.IsNotExist(err) {
+		t.Errorf("expected the symlink not to be followed, got %v", err)
+	}
+}
+
+func TestImportCheckpoint(t *testing.T) {
+	ctr := newCheckpointedContainer(t, "imageID")
+	archive := filepath.Join(t.TempDir(), "checkpoint.tar")
+	if err := exportCheckpoint(ctr, archive); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		source  string
+		imageID bundle.BundleId
+		wantErr error
+	}{
+		{"archive", archive, "imageID", nil},
+		{"directory", ctr.Dir(), "imageID", nil},
+		{"other image", archive, "otherImageID", ErrCheckpointImageMismatch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			err := ImportCheckpoint(tc.source, dir, tc.imageID)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := os.Stat(filepath.Join(dir, metadata.CheckpointDirectory, "inventory.img")); err != nil {
+				t.Errorf("expected the checkpoint to be imported: %v", err)
+			}
+		})
+	}
+}
+
+func TestImportCheckpointIncomplete(t *testing.T) {
+	ctr := newCheckpointedContainer(t, "imageID")
+	if err := os.RemoveAll(filepath.Join(ctr.Dir(), metadata.CheckpointDirectory)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ImportCheckpoint(ctr.Dir(), t.TempDir(), "imageID"); err == nil {
+		t.Fatal("expected the import of an incomplete checkpoint to fail")
+	}
+}
@@ -83,7 +83,7 @@ func New(ctx context.Context, configIface libconfig.Iface) (*ContainerServer, er
 		return nil, errors.New("cannot create container server: interface is nil")
 	}
 
-	storageService, err := storage.NewStorageService(ctx, config.Root, config.RunRoot)
+	storageService, err := storage.NewStorageService(ctx, config.Root, config.RunRoot, config.ImageQuarantineDuration)
 	if err != nil {
 		return nil, err
 	}
@@ -213,6 +213,7 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 	sbox.SetHostname(m.Annotations[annotations.HostName])
 	sbox.SetPortMappings(portMappings)
 	sbox.SetHostNetwork(hostNetwork)
+	sbox.SetHostUTS(hostNetwork && sandbox.HostUTSRequested(kubeAnnotations))
 	sbox.SetUsernsMode(m.Annotations[annotations.UsernsModeAnnotation])
 	sbox.SetPodLinuxOverhead(&podLinuxOverhead)
 	sbox.SetPodLinuxResources(&podLinuxResources)
@@ -713,12 +714,26 @@ func (c *ContainerServer) RemoveSandbox(ctx context.Context, id string) error {
 	return nil
 }
 
+// SandboxCount returns the number of sandboxes in the state store.
+func (c *ContainerServer) SandboxCount() int {
+	return c.state.sandboxes.Size()
+}
+
+// ContainerCount returns the number of containers in the state store, not
+// counting infra containers.
+func (c *ContainerServer) ContainerCount() int {
+	return c.state.containers.Size()
+}
+
 // ListSandboxes lists all sandboxes in the state store.
 func (c *ContainerServer) ListSandboxes() []*sandbox.Sandbox {
 	return c.state.sandboxes.List()
 }
 
-func (c *ContainerServer) UpdateContainerLinuxResources(ctr *oci.Container, resources *rspec.LinuxResources) {
+// UpdateContainerLinuxResources merges the updated resources into the spec of
+// the container and writes the spec back to the container and bundle
+// directories, so that the update survives a restart.
+func (c *ContainerServer) UpdateContainerLinuxResources(ctr *oci.Container, resources *rspec.LinuxResources) error {
 	updatedSpec := ctr.Spec()
 	if updatedSpec.Linux == nil {
 		updatedSpec.Linux = &rspec.Linux{}
@@ -732,41 +747,73 @@ func (c *ContainerServer) UpdateContainerLinuxResources(ctr *oci.Container, reso
 		updatedSpec.Linux.Resources.CPU = &rspec.LinuxCPU{}
 	}
 
-	if resources.CPU.Shares != nil {
-		updatedSpec.Linux.Resources.CPU.Shares = resources.CPU.Shares
-	}
+	if resources.CPU != nil {
+		if resources.CPU.Shares != nil {
+			updatedSpec.Linux.Resources.CPU.Shares = resources.CPU.Shares
+		}
 
-	if resources.CPU.Quota != nil {
-		updatedSpec.Linux.Resources.CPU.Quota = resources.CPU.Quota
-	}
+		if resources.CPU.Quota != nil {
+			updatedSpec.Linux.Resources.CPU.Quota = resources.CPU.Quota
+		}
 
-	if resources.CPU.Period != nil {
-		updatedSpec.Linux.Resources.CPU.Period = resources.CPU.Period
-	}
+		if resources.CPU.Period != nil {
+			updatedSpec.Linux.Resources.CPU.Period = resources.CPU.Period
+		}
 
-	if resources.CPU.Cpus != "" {
-		updatedSpec.Linux.Resources.CPU.Cpus = resources.CPU.Cpus
-	}
+		if resources.CPU.Cpus != "" {
+			updatedSpec.Linux.Resources.CPU.Cpus = resources.CPU.Cpus
+		}
 
-	if resources.CPU.Mems != "" {
-		updatedSpec.Linux.Resources.CPU.Mems = resources.CPU.Mems
+		if resources.CPU.Mems != "" {
+			updatedSpec.Linux.Resources.CPU.Mems = resources.CPU.Mems
+		}
 	}
 
 	if updatedSpec.Linux.Resources.Memory == nil {
 		updatedSpec.Linux.Resources.Memory = &rspec.LinuxMemory{}
 	}
 
-	if resources.Memory.Limit != nil {
-		updatedSpec.Linux.Resources.Memory.Limit = resources.Memory.Limit
+	if resources.Memory != nil {
+		if resources.Memory.Limit != nil {
+			updatedSpec.Linux.Resources.Memory.Limit = resources.Memory.Limit
+		}
+
+		if resources.Memory.Swap != nil {
+			updatedSpec.Linux.Resources.Memory.Swap = resources.Memory.Swap
+		}
 	}
 
-	if resources.Memory.Swap != nil {
-		updatedSpec.Linux.Resources.Memory.Swap = resources.Memory.Swap
+	if len(resources.Unified) != 0 {
+		if updatedSpec.Linux.Resources.Unified == nil {
+			updatedSpec.Linux.Resources.Unified = make(map[string]string, len(resources.Unified))
+		}
+		for key, value := range resources.Unified {
+			updatedSpec.Linux.Resources.Unified[key] = value
+		}
 	}
 
 	ctr.SetSpec(&updatedSpec)
 
 	c.state.containers.Add(ctr.ID(), ctr)
+
+	for _, dir := range []string{ctr.Dir(), ctr.BundlePath()} {
+		if dir == "" {
+			continue
+		}
+		if err := writeSpec(filepath.Join(dir, "config.json"), &updatedSpec); err != nil {
+			return fmt.Errorf("write updated spec of container %s: %w", ctr.ID(), err)
+		}
+	}
+	return nil
+}
+
+// writeSpec atomically replaces the spec at path.
+func writeSpec(path string, spec *rspec.Spec) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(path, data, 0o644)
 }
 
 func ShutdownWasUnclean(config *libconfig.Config) bool {
@@ -2,8 +2,6 @@ package sandbox
 
 import (
 	"errors"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/containers/storage/pkg/stringid"
@@ -13,6 +11,7 @@ import (
 	"github.com/L-F-Z/cri-t/internal/factory/container"
 	"github.com/L-F-Z/cri-t/internal/hostport"
 	"github.com/L-F-Z/cri-t/internal/memorystore"
+	"github.com/L-F-Z/cri-t/internal/naming"
 	"github.com/L-F-Z/cri-t/internal/oci"
 	"github.com/L-F-Z/cri-t/internal/storage"
 	libconfig "github.com/L-F-Z/cri-t/pkg/config"
@@ -24,7 +23,7 @@ type Builder interface {
 	SetConfig(*types.PodSandboxConfig) error
 
 	// GenerateNameAndID sets the sandbox name and ID
-	GenerateNameAndID() error
+	GenerateNameAndID(naming.Strategy) error
 
 	// Config returns the sandbox configuration
 	Config() *types.PodSandboxConfig
@@ -99,6 +98,9 @@ type Builder interface {
 	// SetHostNetwork sets the host network.
 	SetHostNetwork(bool)
 
+	// SetHostUTS sets the host UTS namespace.
+	SetHostUTS(bool)
+
 	// SetUsernsMode sets the user namespace mode.
 	SetUsernsMode(string)
 
@@ -188,8 +190,9 @@ func (b *sandboxBuilder) SetConfig(config *types.PodSandboxConfig) error {
 	return nil
 }
 
-// GenerateNameAndID sets the sandbox name and ID.
-func (b *sandboxBuilder) GenerateNameAndID() error {
+// GenerateNameAndID sets the sandbox name, generated by the naming strategy,
+// and ID.
+func (b *sandboxBuilder) GenerateNameAndID(strategy naming.Strategy) error {
 	if b.config == nil {
 		return errors.New("config is nil")
 	}
@@ -208,13 +211,7 @@ func (b *sandboxBuilder) GenerateNameAndID() error {
 
 	id := stringid.GenerateNonCryptoID()
 	b.SetID(id)
-	b.sandboxRef.name = strings.Join([]string{
-		"k8s",
-		b.config.Metadata.Name,
-		b.config.Metadata.Namespace,
-		b.config.Metadata.Uid,
-		strconv.FormatUint(uint64(b.config.Metadata.Attempt), 10),
-	}, "_")
+	b.sandboxRef.name = strategy.SandboxName(b.config.Metadata)
 
 	return nil
 }
@@ -346,6 +343,11 @@ func (b *sandboxBuilder) SetHostNetwork(hostNetwork bool) {
 	b.sandboxRef.hostNetwork = hostNetwork
 }
 
+// SetHostUTS sets the host UTS namespace flag for the sidecar container.
+func (b *sandboxBuilder) SetHostUTS(hostUTS bool) {
+	b.sandboxRef.hostUTS = hostUTS
+}
+
 // SetUsernsMode sets the user namespace mode for the sidecar container.
 func (b *sandboxBuilder) SetUsernsMode(usernsMode string) {
 	b.sandboxRef.usernsMode = usernsMode
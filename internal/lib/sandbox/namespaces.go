@@ -51,7 +51,8 @@ func (s *Sandbox) NamespacePaths() []*namespace.ManagedNamespace {
 	if net := nsPathGivenInfraPid(s.netns, nsmgr.NETNS, pid); net != "" {
 		typesAndPaths = append(typesAndPaths, namespace.NewManagedNamespace(net, nsmgr.NETNS))
 	}
-	if uts := nsPathGivenInfraPid(s.utsns, nsmgr.UTSNS, pid); uts != "" {
+	// The UTS namespace of the infra container is the one of the host, which must not be joined.
+	if uts := nsPathGivenInfraPid(s.utsns, nsmgr.UTSNS, pid); uts != "" && !s.HostUTS() {
 		typesAndPaths = append(typesAndPaths, namespace.NewManagedNamespace(uts, nsmgr.UTSNS))
 	}
 	if user := nsPathGivenInfraPid(s.userns, nsmgr.USERNS, pid); user != "" {
@@ -60,6 +61,18 @@ func (s *Sandbox) NamespacePaths() []*namespace.ManagedNamespace {
 	return typesAndPaths
 }
 
+// ManagedNamespaces returns the namespaces managed by the sandbox. Namespaces
+// of the infra container or the host are not included.
+func (s *Sandbox) ManagedNamespaces() []nsmgr.Namespace {
+	namespaces := make([]nsmgr.Namespace, 0, nsmgr.ManagedNamespacesNum)
+	for _, ns := range []nsmgr.Namespace{s.utsns, s.ipcns, s.netns, s.userns} {
+		if ns != nil {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
 // RemoveManagedNamespaces removes the formerly mounted namespace.
 // Must be stopped first or this will fail.
 func (s *Sandbox) RemoveManagedNamespaces() error {
@@ -2,12 +2,15 @@ package sandbox_test
 
 import (
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/L-F-Z/cri-t/internal/config/nsmgr"
 	nsmgrtest "github.com/L-F-Z/cri-t/internal/config/nsmgr/test"
+	libsandbox "github.com/L-F-Z/cri-t/internal/lib/sandbox"
 )
 
 const numNamespaces = 4
@@ -308,6 +311,27 @@ var _ = t.Describe("SandboxManagedNamespaces", func() {
 			Expect(nsPaths).To(HaveLen(numNamespaces))
 			Expect(testSandbox.PidNsPath()).To(ContainSubstring("/proc"))
 		})
+		It("should not get the uts namespace of infra with host uts", func() {
+			// Given
+			sbox := libsandbox.NewBuilder()
+			sbox.SetCreatedAt(time.Now())
+			Expect(sbox.SetCRISandbox("sandboxID", map[string]string{}, map[string]string{}, &types.PodSandboxMetadata{})).To(Succeed())
+			sbox.SetHostNetwork(true)
+			sbox.SetHostUTS(true)
+			sb, err := sbox.GetSandbox()
+			Expect(err).ToNot(HaveOccurred())
+			infra, err := nsmgrtest.ContainerWithPid(os.Getpid())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sb.SetInfraContainer(infra)).To(Succeed())
+			// When
+			nsPaths := sb.NamespacePaths()
+			// Then
+			Expect(sb.HostUTS()).To(BeTrue())
+			Expect(nsPaths).To(HaveLen(numNamespaces - 1))
+			for _, ns := range nsPaths {
+				Expect(ns.Type()).NotTo(Equal(nsmgr.UTSNS))
+			}
+		})
 		It("should get nothing when infra set with pid not running", func() {
 			// Given
 			// max valid pid is 4194304
@@ -346,4 +370,30 @@ var _ = t.Describe("SandboxManagedNamespaces", func() {
 			Expect(nsPaths).To(BeEmpty())
 		})
 	})
+	t.Describe("ManagedNamespaces", func() {
+		It("should get nothing when namespaces not set", func() {
+			// Given
+			// When
+			namespaces := testSandbox.ManagedNamespaces()
+			// Then
+			Expect(namespaces).To(BeEmpty())
+		})
+		It("should get all managed namespaces", func() {
+			// Given
+			testSandbox.AddManagedNamespaces(nsmgrtest.AllSpoofedNamespaces)
+			// When
+			namespaces := testSandbox.ManagedNamespaces()
+			// Then
+			Expect(namespaces).To(HaveLen(numNamespaces))
+		})
+		It("should only get the set namespaces", func() {
+			// Given
+			netns := &nsmgrtest.SpoofedNamespace{NsType: nsmgr.NETNS}
+			testSandbox.AddManagedNamespaces([]nsmgr.Namespace{netns})
+			// When
+			namespaces := testSandbox.ManagedNamespaces()
+			// Then
+			Expect(namespaces).To(Equal([]nsmgr.Namespace{netns}))
+		})
+	})
 })
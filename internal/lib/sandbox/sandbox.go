@@ -18,6 +18,7 @@ import (
 	"github.com/L-F-Z/cri-t/internal/log"
 	"github.com/L-F-Z/cri-t/internal/memorystore"
 	"github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/pkg/annotations"
 )
 
 // DevShmPath is the default system wide shared memory path.
@@ -64,6 +65,7 @@ type Sandbox struct {
 	networkStopped     bool
 	privileged         bool
 	hostNetwork        bool
+	hostUTS            bool
 	usernsMode         string
 	containerEnvPath   string
 	podLinuxOverhead   *types.LinuxContainerResources
@@ -233,6 +235,21 @@ func (s *Sandbox) HostNetwork() bool {
 	return s.hostNetwork
 }
 
+// HostUTS returns whether the sandbox runs in the host UTS namespace. The CRI
+// has no option for the UTS namespace, so sandboxes only share the host UTS
+// namespace if they run in the host network namespace and opt in with the
+// HostUTS annotation.
+func (s *Sandbox) HostUTS() bool {
+	return s.hostUTS
+}
+
+// HostUTSRequested returns whether the kube annotations of a pod opt in to the
+// host UTS namespace. Creating and restoring a sandbox both use it, so that
+// they agree on the value of the HostUTS annotation.
+func HostUTSRequested(kubeAnnotations map[string]string) bool {
+	return kubeAnnotations[annotations.HostUTSAnnotation] == "true"
+}
+
 // ResolvPath returns the resolv path for the sandbox.
 func (s *Sandbox) ResolvPath() string {
 	return s.resolvPath
@@ -8,6 +8,7 @@ import (
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	libsandbox "github.com/L-F-Z/cri-t/internal/lib/sandbox"
+	"github.com/L-F-Z/cri-t/internal/naming"
 )
 
 var _ = Describe("Sandbox:Builder", func() {
@@ -36,7 +37,7 @@ var _ = Describe("Sandbox:Builder", func() {
 			Expect(builder.SetConfig(config)).To(Succeed())
 
 			// When
-			err := builder.GenerateNameAndID()
+			err := builder.GenerateNameAndID(naming.Default())
 
 			// Then
 			Expect(err).ToNot(HaveOccurred())
@@ -49,7 +50,7 @@ var _ = Describe("Sandbox:Builder", func() {
 		It("should fail with empty config", func() {
 			// Given
 			// When
-			err := builder.GenerateNameAndID()
+			err := builder.GenerateNameAndID(naming.Default())
 
 			// Then
 			Expect(err).To(HaveOccurred())
@@ -68,7 +69,7 @@ var _ = Describe("Sandbox:Builder", func() {
 			Expect(builder.SetConfig(config)).NotTo(Succeed())
 
 			// When
-			err := builder.GenerateNameAndID()
+			err := builder.GenerateNameAndID(naming.Default())
 
 			// Then
 			Expect(err).To(HaveOccurred())
@@ -87,7 +88,7 @@ var _ = Describe("Sandbox:Builder", func() {
 			Expect(builder.SetConfig(config)).To(Succeed())
 
 			// When
-			err := builder.GenerateNameAndID()
+			err := builder.GenerateNameAndID(naming.Default())
 
 			// Then
 			Expect(err).To(HaveOccurred())
@@ -106,7 +107,7 @@ var _ = Describe("Sandbox:Builder", func() {
 			Expect(builder.SetConfig(config)).To(Succeed())
 
 			// When
-			err := builder.GenerateNameAndID()
+			err := builder.GenerateNameAndID(naming.Default())
 
 			// Then
 			Expect(err).To(HaveOccurred())
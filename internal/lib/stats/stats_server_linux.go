@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/vishvananda/netlink"
@@ -66,7 +67,7 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 		if c.StateNoLock().Status == oci.ContainerStateStopped {
 			continue
 		}
-		cgstats, err := ss.Runtime().ContainerStats(ss.ctx, c, sb.CgroupParent())
+		cgstats, err := ss.containerCgroupStats(c, sb)
 		if err != nil {
 			log.Errorf(ss.ctx, "Error getting container stats %s: %v", c.ID(), err)
 			continue
@@ -107,7 +108,7 @@ func (ss *StatsServer) updateContainerStats(c *oci.Container, sb *sandbox.Sandbo
 	if c.StateNoLock().Status == oci.ContainerStateStopped {
 		return nil
 	}
-	cgstats, err := ss.Runtime().ContainerStats(ss.ctx, c, sb.CgroupParent())
+	cgstats, err := ss.containerCgroupStats(c, sb)
 	if err != nil {
 		log.Errorf(ss.ctx, "Error getting container stats %s: %v", c.ID(), err)
 		return nil
@@ -121,6 +122,31 @@ func (ss *StatsServer) updateContainerStats(c *oci.Container, sb *sandbox.Sandbo
 	return cStats
 }
 
+// containerCgroupStats calls into the runtime handler to get the cgroup stats of the container.
+// Spoofed infra containers have no cgroup, and the cgroup of a created container may not
+// exist before it gets started, so both get zeroed stats instead of an error.
+func (ss *StatsServer) containerCgroupStats(c *oci.Container, sb *sandbox.Sandbox) (*cgmgr.CgroupStats, error) {
+	if c.Spoofed() {
+		return zeroCgroupStats(), nil
+	}
+	cgstats, err := ss.Runtime().ContainerStats(ss.ctx, c, sb.CgroupParent())
+	if err != nil && c.StateNoLock().Status == oci.ContainerStateCreated {
+		log.Debugf(ss.ctx, "Using zeroed stats for created container %s: %v", c.ID(), err)
+		return zeroCgroupStats(), nil
+	}
+	return cgstats, err
+}
+
+// zeroCgroupStats returns cgroup stats without any usage, taken now.
+func zeroCgroupStats() *cgmgr.CgroupStats {
+	return &cgmgr.CgroupStats{
+		Memory:     &cgmgr.MemoryStats{},
+		CPU:        &cgmgr.CPUStats{},
+		Pid:        &cgmgr.PidsStats{},
+		SystemNano: time.Now().UnixNano(),
+	}
+}
+
 // populateNetworkUsage gathers information about the network from within the sandbox's network namespace.
 func (ss *StatsServer) populateNetworkUsage(stats *types.PodSandboxStats, sb *sandbox.Sandbox) error {
 	return ns.WithNetNSPath(sb.NetNsPath(), func(_ ns.NetNS) error {
@@ -0,0 +1,83 @@
+package statsserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
+	"github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/pkg/config"
+)
+
+func TestContainerCgroupStatsSpoofed(t *testing.T) {
+	sut := &StatsServer{ctx: context.Background()}
+	ctr := oci.NewSpoofedContainer("sandboxID", "infra", map[string]string{}, "sandboxID", time.Now(), "")
+
+	cgstats, err := sut.containerCgroupStats(ctr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := containerCRIStats(cgstats, ctr, cgstats.SystemNano)
+	if stats.Cpu.UsageCoreNanoSeconds.Value != 0 {
+		t.Errorf("expected zeroed CPU usage, got %d", stats.Cpu.UsageCoreNanoSeconds.Value)
+	}
+	if stats.Memory.WorkingSetBytes.Value != 0 {
+		t.Errorf("expected zeroed working set, got %d", stats.Memory.WorkingSetBytes.Value)
+	}
+	if stats.Cpu.Timestamp == 0 {
+		t.Error("expected the zeroed stats to be timestamped")
+	}
+}
+
+// statsTestServer provides the runtime to the stats server.
+type statsTestServer struct {
+	parentServerIface
+	runtime *oci.Runtime
+}
+
+func (s *statsTestServer) Runtime() *oci.Runtime {
+	return s.runtime
+}
+
+func TestContainerCgroupStatsCreated(t *testing.T) {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ContainerAttachSocketDir = t.TempDir()
+	runtime, err := oci.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sut := &StatsServer{ctx: context.Background(), parentServerIface: &statsTestServer{runtime: runtime}}
+	// The container has no cgroup, as it never got started.
+	ctr, err := oci.NewContainer("ctrID", "ctr", "", "", map[string]string{}, map[string]string{}, map[string]string{}, "image", nil, nil, "", &types.ContainerMetadata{}, "sandboxID", false, false, false, "", "", time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb := &sandbox.Sandbox{}
+
+	ctr.SetState(&oci.ContainerState{State: specs.State{Status: oci.ContainerStateCreated}})
+	cgstats, err := sut.containerCgroupStats(ctr, sb)
+	if err != nil {
+		t.Fatalf("expected zeroed stats for a created container, got error: %v", err)
+	}
+	stats := containerCRIStats(cgstats, ctr, cgstats.SystemNano)
+	if stats.Cpu.UsageCoreNanoSeconds.Value != 0 || stats.Memory.WorkingSetBytes.Value != 0 {
+		t.Errorf("expected zeroed stats, got %+v", stats)
+	}
+	if stats.Cpu.Timestamp == 0 {
+		t.Error("expected the zeroed stats to be timestamped")
+	}
+
+	// A running container is expected to have a cgroup.
+	ctr.SetState(&oci.ContainerState{State: specs.State{Status: oci.ContainerStateRunning}})
+	if _, err := sut.containerCgroupStats(ctr, sb); err == nil {
+		t.Error("expected an error for a running container without cgroup")
+	}
+}
@@ -0,0 +1,136 @@
+package naming
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// Kubernetes is the default strategy, naming pod sandboxes
+	// k8s_<pod>_<namespace>_<uid>_<attempt> and containers
+	// k8s_<container>_<pod>_<namespace>_<uid>_<attempt>.
+	Kubernetes = "kubernetes"
+
+	// Descriptive leads the names with the namespace and pod, so that the
+	// containers of a namespace or pod sort together in ps and logs. Pod
+	// sandboxes are named <namespace>_<pod>_<attempt>_<uid> and containers
+	// <namespace>_<pod>_<container>_<attempt>_<uid>. Names longer than
+	// MaxNameLength get truncated.
+	Descriptive = "descriptive"
+
+	// MaxNameLength is the maximum length of the names generated by the
+	// descriptive strategy.
+	MaxNameLength = 253
+
+	kubePrefix = "k8s"
+	delimiter  = "_"
+	// hashLength is the number of hex digits of the hash appended to
+	// truncated names.
+	hashLength = 16
+)
+
+// Strategy generates the names of pod sandboxes and containers. Names have
+// to be the same for the same metadata, so that retried requests find the
+// resources of their previous attempts, and distinct for distinct metadata.
+type Strategy interface {
+	// SandboxName returns the name of a pod sandbox.
+	SandboxName(sandbox *types.PodSandboxMetadata) string
+
+	// ContainerName returns the name of a container of a pod sandbox.
+	ContainerName(container *types.ContainerMetadata, sandbox *types.PodSandboxMetadata) string
+}
+
+var strategies = map[string]Strategy{
+	Kubernetes:  kubernetesStrategy{},
+	Descriptive: descriptiveStrategy{},
+}
+
+// Names returns the names of the available strategies.
+func Names() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Default returns the Kubernetes strategy, which is used unless configured
+// otherwise.
+func Default() Strategy {
+	return strategies[Kubernetes]
+}
+
+// Get returns the strategy with the provided name. An empty name selects the
+// Kubernetes strategy.
+func Get(name string) (Strategy, error) {
+	if name == "" {
+		return Default(), nil
+	}
+	strategy, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown naming strategy %q, must be one of %s", name, strings.Join(Names(), ", "))
+	}
+	return strategy, nil
+}
+
+type kubernetesStrategy struct{}
+
+func (kubernetesStrategy) SandboxName(sandbox *types.PodSandboxMetadata) string {
+	return strings.Join([]string{
+		kubePrefix,
+		sandbox.Name,
+		sandbox.Namespace,
+		sandbox.Uid,
+		strconv.FormatUint(uint64(sandbox.Attempt), 10),
+	}, delimiter)
+}
+
+func (kubernetesStrategy) ContainerName(container *types.ContainerMetadata, sandbox *types.PodSandboxMetadata) string {
+	return strings.Join([]string{
+		kubePrefix,
+		container.Name,
+		sandbox.Name,
+		sandbox.Namespace,
+		sandbox.Uid,
+		strconv.FormatUint(uint64(container.Attempt), 10),
+	}, delimiter)
+}
+
+type descriptiveStrategy struct{}
+
+func (descriptiveStrategy) SandboxName(sandbox *types.PodSandboxMetadata) string {
+	return limitLength(strings.Join([]string{
+		sandbox.Namespace,
+		sandbox.Name,
+		strconv.FormatUint(uint64(sandbox.Attempt), 10),
+		sandbox.Uid,
+	}, delimiter))
+}
+
+func (descriptiveStrategy) ContainerName(container *types.ContainerMetadata, sandbox *types.PodSandboxMetadata) string {
+	return limitLength(strings.Join([]string{
+		sandbox.Namespace,
+		sandbox.Name,
+		container.Name,
+		strconv.FormatUint(uint64(container.Attempt), 10),
+		sandbox.Uid,
+	}, delimiter))
+}
+
+// limitLength truncates names longer than MaxNameLength. The truncated name
+// ends with a hash of the full name, which keeps it unique.
+func limitLength(name string) string {
+	if len(name) <= MaxNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:hashLength]
+	return name[:MaxNameLength-len(delimiter)-hashLength] + delimiter + hash
+}
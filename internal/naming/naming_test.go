@@ -0,0 +1,125 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func testSandboxes() []*types.PodSandboxMetadata {
+	return []*types.PodSandboxMetadata{
+		{Name: "pod", Namespace: "namespace", Uid: "uid", Attempt: 0},
+		{Name: "pod", Namespace: "namespace", Uid: "uid", Attempt: 1},
+		{Name: "pod", Namespace: "namespace", Uid: "other-uid", Attempt: 0},
+		{Name: "pod", Namespace: "other-namespace", Uid: "uid", Attempt: 0},
+		{Name: "other-pod", Namespace: "namespace", Uid: "uid", Attempt: 0},
+		{Name: strings.Repeat("p", 253), Namespace: "namespace", Uid: "uid", Attempt: 0},
+		{Name: strings.Repeat("p", 253), Namespace: "namespace", Uid: "uid", Attempt: 1},
+	}
+}
+
+func TestKubernetesNames(t *testing.T) {
+	sut := Default()
+	sandbox := &types.PodSandboxMetadata{Name: "pod", Namespace: "namespace", Uid: "uid", Attempt: 1}
+
+	if name := sut.SandboxName(sandbox); name != "k8s_pod_namespace_uid_1" {
+		t.Errorf("unexpected sandbox name %s", name)
+	}
+	container := &types.ContainerMetadata{Name: "ctr", Attempt: 2}
+	if name := sut.ContainerName(container, sandbox); name != "k8s_ctr_pod_namespace_uid_2" {
+		t.Errorf("unexpected container name %s", name)
+	}
+}
+
+func TestDescriptiveNames(t *testing.T) {
+	sut, err := Get(Descriptive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sandbox := &types.PodSandboxMetadata{Name: "pod", Namespace: "namespace", Uid: "uid", Attempt: 1}
+
+	if name := sut.SandboxName(sandbox); name != "namespace_pod_1_uid" {
+		t.Errorf("unexpected sandbox name %s", name)
+	}
+	container := &types.ContainerMetadata{Name: "ctr", Attempt: 2}
+	if name := sut.ContainerName(container, sandbox); name != "namespace_pod_ctr_2_uid" {
+		t.Errorf("unexpected container name %s", name)
+	}
+}
+
+func TestNamesAreUnique(t *testing.T) {
+	for _, strategy := range Names() {
+		t.Run(strategy, func(t *testing.T) {
+			sut, err := Get(strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := map[string]bool{}
+			add := func(name string) {
+				if names[name] {
+					t.Errorf("name %s generated twice", name)
+				}
+				names[name] = true
+			}
+			for _, sandbox := range testSandboxes() {
+				add(sut.SandboxName(sandbox))
+				// Container names do not include the sandbox attempt,
+				// a pod has only one sandbox at a time.
+				if sandbox.Attempt != 0 {
+					continue
+				}
+				for _, container := range []*types.ContainerMetadata{
+					{Name: "ctr", Attempt: 0},
+					{Name: "ctr", Attempt: 1},
+					{Name: "other-ctr", Attempt: 0},
+				} {
+					add(sut.ContainerName(container, sandbox))
+				}
+			}
+		})
+	}
+}
+
+func TestNamesAreStable(t *testing.T) {
+	for _, strategy := range Names() {
+		t.Run(strategy, func(t *testing.T) {
+			sut, err := Get(strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, sandbox := range testSandboxes() {
+				if sut.SandboxName(sandbox) != sut.SandboxName(sandbox) {
+					t.Errorf("expected the same sandbox name for %v", sandbox)
+				}
+			}
+		})
+	}
+}
+
+func TestDescriptiveNamesLengthLimit(t *testing.T) {
+	sut, err := Get(Descriptive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sandbox := range testSandboxes() {
+		container := &types.ContainerMetadata{Name: strings.Repeat("c", 63)}
+		for _, name := range []string{sut.SandboxName(sandbox), sut.ContainerName(container, sandbox)} {
+			if len(name) > MaxNameLength {
+				t.Errorf("name %s is longer than %d characters", name, MaxNameLength)
+			}
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	if sut, err := Get(""); err != nil || sut != Default() {
+		t.Errorf("expected the default strategy for an empty name, got %v, %v", sut, err)
+	}
+	if _, err := Get("invalid"); err == nil {
+		t.Error("expected an unknown strategy to fail")
+	}
+}
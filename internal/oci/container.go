@@ -42,6 +42,8 @@ type Container struct {
 	name           string
 	logPath        string
 	runtimeHandler string
+	// restore is set if the container gets restored from a checkpoint
+	restore bool
 	// this is the /var/run/storage/... directory, erased on reboot
 	bundlePath string
 	// this is the /var/lib/storage/... directory
@@ -52,6 +54,7 @@ type Container struct {
 	imageID            *bundle.BundleId // nil for infra containers.
 	mountPoint         string
 	seccompProfilePath string
+	securityPosture    *SecurityPosture
 	conmonCgroupfsPath string
 	crioAnnotations    fields.Set
 	state              *ContainerState
@@ -90,7 +93,8 @@ type ContainerVolume struct {
 	RecursiveReadOnly bool                   `json:"recursive_read_only"`
 	Propagation       types.MountPropagation `json:"propagation"`
 	SelinuxRelabel    bool                   `json:"selinux_relabel"`
-	Image             *types.ImageSpec       `json:"image,omitempty"` // A possible image for OCI volume mounts
+	Image             *types.ImageSpec       `json:"image,omitempty"`         // A possible image for OCI volume mounts
+	CopyOnWrite       bool                   `json:"copy_on_write,omitempty"` // Image mount writable through a per container overlay upper directory
 }
 
 // ContainerState represents the status of a container.
@@ -213,6 +217,36 @@ func (c *Container) CRIContainer() *types.Container {
 	return c.criContainer
 }
 
+// SecurityPosture summarizes the security settings of a container. It is
+// derived from the spec when the container gets created or restored.
+type SecurityPosture struct {
+	Privileged bool   `json:"privileged"`
+	UID        uint32 `json:"uid"`
+	GID        uint32 `json:"gid"`
+	// CapabilitiesAdded and CapabilitiesDropped are relative to the default
+	// capabilities.
+	CapabilitiesAdded   []string `json:"capabilitiesAdded"`
+	CapabilitiesDropped []string `json:"capabilitiesDropped"`
+	SeccompProfile      string   `json:"seccompProfile"`
+	AppArmorProfile     string   `json:"apparmorProfile"`
+	SELinuxLabel        string   `json:"selinuxLabel"`
+	ReadOnlyRootfs      bool     `json:"readOnlyRootfs"`
+	HostNetwork         bool     `json:"hostNetwork"`
+	HostPID             bool     `json:"hostPID"`
+	HostIPC             bool     `json:"hostIPC"`
+}
+
+// SetSecurityPosture sets the security posture of the container.
+func (c *Container) SetSecurityPosture(posture *SecurityPosture) {
+	c.securityPosture = posture
+}
+
+// SecurityPosture returns the security posture of the container, or nil if
+// it has not been set.
+func (c *Container) SecurityPosture() *SecurityPosture {
+	return c.securityPosture
+}
+
 // SetSpec loads the OCI spec in the container struct.
 func (c *Container) SetSpec(s *specs.Spec) {
 	c.spec = s
@@ -376,6 +410,21 @@ func (c *Container) SetSandbox(podSandboxID string) {
 	c.criContainer.PodSandboxId = podSandboxID
 }
 
+// Restore returns whether the container is restored from a checkpoint.
+func (c *Container) Restore() bool {
+	return c.restore
+}
+
+// SetRestore marks the container as being restored from a checkpoint.
+func (c *Container) SetRestore(restore bool) {
+	c.restore = restore
+}
+
+// RuntimeHandler returns the runtime handler of the container.
+func (c *Container) RuntimeHandler() string {
+	return c.runtimeHandler
+}
+
 // Dir returns the dir of the container.
 func (c *Container) Dir() string {
 	return c.dir
@@ -50,8 +50,22 @@ type Runtime struct {
 	config              *config.Config
 	runtimeImplMap      map[string]RuntimeImpl
 	runtimeImplMapMutex sync.RWMutex
+
+	// platformRuntimePaths caches the PlatformRuntimePath lookups per
+	// platformRuntimePathKey.
+	platformRuntimePaths sync.Map
+}
+
+// platformRuntimePathKey identifies a cached platform runtime path.
+type platformRuntimePathKey struct {
+	handler  string
+	platform string
 }
 
+// ErrCheckpointRestoreNotSupported is returned if CRIU or the runtime of a
+// container cannot checkpoint or restore containers.
+var ErrCheckpointRestoreNotSupported = errors.New("checkpoint/restore not supported")
+
 // RuntimeImpl is an interface used by the caller to interact with the
 // container runtime. The purpose of this interface being to abstract
 // implementations and their associated assumptions regarding the way to
@@ -152,16 +166,28 @@ func (r *Runtime) PrivilegedWithoutHostDevices(handler string) (bool, error) {
 }
 
 // PlatformRuntimePath returns the runtime path for a given platform.
+// The result is cached per handler and platform until
+// ClearPlatformRuntimePathCache gets called.
 func (r *Runtime) PlatformRuntimePath(handler, platform string) (string, error) {
+	key := platformRuntimePathKey{handler: handler, platform: platform}
+	if runtimePath, ok := r.platformRuntimePaths.Load(key); ok {
+		return runtimePath.(string), nil
+	}
+
 	rh, err := r.getRuntimeHandler(handler)
 	if err != nil {
 		return "", err
 	}
-	if runtimePath, ok := rh.PlatformRuntimePaths[platform]; ok {
-		return runtimePath, nil
-	}
+	runtimePath := rh.PlatformRuntimePaths[platform]
+	r.platformRuntimePaths.Store(key, runtimePath)
 
-	return "", nil
+	return runtimePath, nil
+}
+
+// ClearPlatformRuntimePathCache drops all cached platform runtime paths. It
+// has to be called whenever the runtime handlers got reloaded.
+func (r *Runtime) ClearPlatformRuntimePathCache() {
+	r.platformRuntimePaths.Clear()
 }
 
 // AllowedAnnotations returns the allowed annotations for this runtime.
@@ -216,6 +242,17 @@ func (r *Runtime) RuntimeSupportsIDMap(runtimeHandler string) bool {
 	return rh.RuntimeSupportsIDMap()
 }
 
+// RuntimeFeaturesLoaded returns whether the "runtime features" of the runtime of
+// runtimeHandler have been loaded successfully.
+func (r *Runtime) RuntimeFeaturesLoaded(runtimeHandler string) bool {
+	rh, err := r.getRuntimeHandler(runtimeHandler)
+	if err != nil {
+		return false
+	}
+
+	return rh.RuntimeFeaturesLoaded()
+}
+
 // RuntimeSupportsRROMounts returns whether the runtime of runtimeHandler supports
 // the "runtime features" command and that the output advertises support for the
 // Recursive Read-only (RRO) mount as an option.
@@ -238,6 +275,38 @@ func (r *Runtime) RuntimeDefaultAnnotations(runtimeHandler string) (map[string]s
 	return rh.RuntimeDefaultAnnotations(), nil
 }
 
+// RuntimeDefaultEnv returns the default environment variables for this runtime handler.
+func (r *Runtime) RuntimeDefaultEnv(runtimeHandler string) ([]string, error) {
+	rh, err := r.getRuntimeHandler(runtimeHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return rh.DefaultEnv, nil
+}
+
+// RuntimeAbsentMountSourcesToReject returns the paths which, when absent from
+// the host, cause the creation of containers of this runtime handler to fail.
+func (r *Runtime) RuntimeAbsentMountSourcesToReject(runtimeHandler string) ([]string, error) {
+	rh, err := r.getRuntimeHandler(runtimeHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return rh.AbsentMountSourcesToReject, nil
+}
+
+// RuntimeAllowedUnsafeSysctls returns the patterns of the sysctls which pods
+// of this runtime handler may set, even if they are not known to be namespaced.
+func (r *Runtime) RuntimeAllowedUnsafeSysctls(runtimeHandler string) ([]string, error) {
+	rh, err := r.getRuntimeHandler(runtimeHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return rh.AllowedUnsafeSysctls, nil
+}
+
 func (r *Runtime) newRuntimeImpl(c *Container) (RuntimeImpl, error) {
 	rh, err := r.getRuntimeHandler(c.runtimeHandler)
 	if err != nil {
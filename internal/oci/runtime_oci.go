@@ -1567,10 +1567,10 @@ func (r *runtimeOCI) RestoreContainer(ctx context.Context, c *Container, cgroupP
 
 func (r *runtimeOCI) checkpointRestoreSupported(runtimePath string) error {
 	if err := criu.CheckForCriu(criu.PodCriuVersion); err != nil {
-		return fmt.Errorf("check for CRIU %w", err)
+		return fmt.Errorf("%w: check for CRIU: %w", ErrCheckpointRestoreNotSupported, err)
 	}
 	if !crutils.CRRuntimeSupportsCheckpointRestore(runtimePath) {
-		return errors.New("configured runtime does not support checkpoint/restore")
+		return fmt.Errorf("%w: configured runtime %s does not support it", ErrCheckpointRestoreNotSupported, runtimePath)
 	}
 	return nil
 }
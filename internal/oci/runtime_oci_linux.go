@@ -12,6 +12,12 @@ import (
 	"github.com/L-F-Z/cri-t/internal/log"
 )
 
+// portForwardDrainTimeout bounds how long a port forward waits for the
+// container to finish its response after the client stopped sending. The
+// stream of the client lives as long as its whole session, so it does not
+// bound the wait for a container which never closes the connection.
+var portForwardDrainTimeout = 30 * time.Second
+
 // PortForwardContainer forwards the specified port into the provided container.
 func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
@@ -43,7 +49,8 @@ func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, net
 		}
 		defer conn.Close()
 
-		errCh := make(chan error, 2)
+		ctrDone := make(chan error, 1)
+		clientDone := make(chan error, 1)
 
 		debug := func(format string, args ...any) {
 			log.Debugf(ctx, fmt.Sprintf(
@@ -55,43 +62,69 @@ func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, net
 		go func() {
 			debug("copy data from container to client")
 			_, err := io.Copy(stream, conn)
-			errCh <- err
+			ctrDone <- err
 		}()
 
 		// Copy from the client stream to the namespace port connection
 		go func() {
 			debug("copy data from client to container")
 			_, err := io.Copy(conn, stream)
-			errCh <- err
+			// Let the application in the container see that the client
+			// disconnected, so that it can finish its response gracefully.
+			if tcpConn, ok := conn.(*net.TCPConn); ok {
+				if closeErr := tcpConn.CloseWrite(); closeErr != nil {
+					debug("close write side of connection: %v", closeErr)
+				}
+			}
+			clientDone <- err
 		}()
 
-		// Wait until the first error is returned by one of the connections we
-		// use errFwd to store the result of the port forwarding operation if
-		// the context is cancelled close everything and return
+		// Wait until one of the directions stops, we use errFwd to store the
+		// result of the port forwarding operation if the context is
+		// cancelled close everything and return
 		var errFwd error
 		select {
-		case errFwd = <-errCh:
-			debug("stop forwarding in direction: %v", errFwd)
-		case <-ctx.Done():
-			debug("cancelled: %v", ctx.Err())
-			return ctx.Err()
-		}
-
-		// give a chance to terminate gracefully or timeout
-		const timeout = time.Second
-		select {
-		case e := <-errCh:
-			if errFwd == nil {
-				errFwd = e
+		case errFwd = <-clientDone:
+			// The container got the end of the data, so wait for it to
+			// finish its response instead of cutting it off.
+			debug("client stopped sending: %v", errFwd)
+			select {
+			case e := <-ctrDone:
+				if errFwd == nil {
+					errFwd = e
+				}
+				debug("stopped forwarding in both directions")
+
+			case <-time.After(portForwardDrainTimeout):
+				debug("timed out waiting for the container to finish its response")
+
+			case <-ctx.Done():
+				debug("cancelled: %v", ctx.Err())
+				errFwd = ctx.Err()
 			}
-			debug("stopped forwarding in both directions")
 
-		case <-time.After(timeout):
-			debug("timed out waiting to close the connection")
+		case errFwd = <-ctrDone:
+			debug("container stopped sending: %v", errFwd)
+			// give the client a chance to terminate gracefully or timeout
+			const timeout = time.Second
+			select {
+			case e := <-clientDone:
+				if errFwd == nil {
+					errFwd = e
+				}
+				debug("stopped forwarding in both directions")
+
+			case <-time.After(timeout):
+				debug("timed out waiting to close the connection")
+
+			case <-ctx.Done():
+				debug("cancelled: %v", ctx.Err())
+				errFwd = ctx.Err()
+			}
 
 		case <-ctx.Done():
 			debug("cancelled: %v", ctx.Err())
-			errFwd = ctx.Err()
+			return ctx.Err()
 		}
 
 		return errFwd
@@ -0,0 +1,131 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// portForwardTestStream is a client stream which sends request and collects
+// the response.
+type portForwardTestStream struct {
+	io.Reader
+	mu       sync.Mutex
+	response bytes.Buffer
+}
+
+func (s *portForwardTestStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.response.Write(p)
+}
+
+func (s *portForwardTestStream) Close() error {
+	return nil
+}
+
+func (s *portForwardTestStream) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.response.String()
+}
+
+func TestPortForwardContainerWaitsForResponse(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("entering a network namespace requires root")
+	}
+	const netNsPath = "/proc/self/ns/net"
+
+	// The echo server only answers once the client finished sending, and
+	// takes longer than the grace period of the forwarding.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		request, err := io.ReadAll(conn)
+		if err != nil {
+			return
+		}
+		time.Sleep(1500 * time.Millisecond)
+		_, _ = conn.Write(append([]byte("echo: "), request...))
+	}()
+
+	ctr, err := NewContainer("ctrID", "ctr", "", "", nil, nil, nil, "", nil, nil, "", &types.ContainerMetadata{}, "sandboxID", false, false, false, "", t.TempDir(), time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := &portForwardTestStream{Reader: strings.NewReader("request")}
+	port := int32(ln.Addr().(*net.TCPAddr).Port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := (&runtimeOCI{}).PortForwardContainer(ctx, ctr, netNsPath, port, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stream.String(); got != "echo: request" {
+		t.Errorf("expected the complete response, got %q", got)
+	}
+}
+
+func TestPortForwardContainerDrainTimeout(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("entering a network namespace requires root")
+	}
+	const netNsPath = "/proc/self/ns/net"
+
+	defer func(timeout time.Duration) { portForwardDrainTimeout = timeout }(portForwardDrainTimeout)
+	portForwardDrainTimeout = 100 * time.Millisecond
+
+	// The server reads the request, but never answers nor closes the
+	// connection.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	serverDone := make(chan struct{})
+	defer close(serverDone)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.ReadAll(conn)
+		<-serverDone
+	}()
+
+	ctr, err := NewContainer("ctrID", "ctr", "", "", nil, nil, nil, "", nil, nil, "", &types.ContainerMetadata{}, "sandboxID", false, false, false, "", t.TempDir(), time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := &portForwardTestStream{Reader: strings.NewReader("request")}
+	port := int32(ln.Addr().(*net.TCPAddr).Port)
+
+	// The session outlives the drain timeout by far.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	start := time.Now()
+	if err := (&runtimeOCI{}).PortForwardContainer(ctx, ctr, netNsPath, port, stream); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the port forward to stop after the drain timeout, took %v", elapsed)
+	}
+}
@@ -3,6 +3,7 @@ package resourcestore
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -20,7 +21,84 @@ type ResourceCleaner struct {
 
 // A cleanupFunc is a function that cleans up one piece of
 // the associated resource.
-type cleanupFunc func() error
+type cleanupFunc struct {
+	resourceType string
+	resourceID   string
+	description  string
+	fn           func() error
+}
+
+// CleanupOutcome is the outcome of a single cleanup step.
+type CleanupOutcome string
+
+const (
+	// CleanupSucceeded means the resource has been reclaimed.
+	CleanupSucceeded CleanupOutcome = "succeeded"
+	// CleanupFailed means the resource could not be reclaimed.
+	CleanupFailed CleanupOutcome = "failed"
+	// CleanupSkipped means the cleanup step did not run because an earlier
+	// one failed, so the resource has not been reclaimed either.
+	CleanupSkipped CleanupOutcome = "skipped"
+)
+
+// CleanupResult records the outcome of a single cleanup step.
+type CleanupResult struct {
+	ResourceType string         `json:"resourceType"`
+	ResourceID   string         `json:"resourceID"`
+	Description  string         `json:"description"`
+	Outcome      CleanupOutcome `json:"outcome"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// CleanupError is returned by Cleanup if a cleanup step failed. It lists the
+// outcome of every step, so that leaked resources can be reclaimed manually.
+type CleanupError struct {
+	Results []CleanupResult
+	err     error
+}
+
+func (e *CleanupError) Error() string {
+	return fmt.Sprintf("%v (%s)", e.err, e.summary())
+}
+
+func (e *CleanupError) Unwrap() error {
+	return e.err
+}
+
+// Fields returns the resources of the cleanup grouped by their outcome, to be
+// used as structured log fields.
+func (e *CleanupError) Fields() map[string]any {
+	return map[string]any{
+		"reclaimed":    e.resources(CleanupSucceeded),
+		"notReclaimed": e.resources(CleanupFailed),
+		"notAttempted": e.resources(CleanupSkipped),
+	}
+}
+
+func (e *CleanupError) resources(outcome CleanupOutcome) []string {
+	resources := []string{}
+	for i := range e.Results {
+		if e.Results[i].Outcome != outcome {
+			continue
+		}
+		resource := e.Results[i].Description
+		if e.Results[i].ResourceType != "" {
+			resource = e.Results[i].ResourceType + " " + e.Results[i].ResourceID
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+func (e *CleanupError) summary() string {
+	parts := []string{}
+	for _, outcome := range []CleanupOutcome{CleanupSucceeded, CleanupFailed, CleanupSkipped} {
+		if resources := e.resources(outcome); len(resources) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", outcome, strings.Join(resources, ", ")))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
 
 // NewResourceCleaner creates a new ResourceCleaner.
 func NewResourceCleaner() *ResourceCleaner {
@@ -29,6 +107,13 @@ func NewResourceCleaner() *ResourceCleaner {
 
 // Add adds a new CleanupFunc to the ResourceCleaner.
 func (r *ResourceCleaner) Add(ctx context.Context, description string, fn func() error) {
+	r.AddResource(ctx, "", "", description, fn)
+}
+
+// AddResource adds a new CleanupFunc reclaiming the resource of the given
+// type and ID to the ResourceCleaner. The resource is named in the
+// CleanupError if the cleanup fails.
+func (r *ResourceCleaner) AddResource(ctx context.Context, resourceType, resourceID, description string, fn func() error) {
 	// Create a retry task on top of the provided function
 	task := func() error {
 		err := retry(ctx, description, fn)
@@ -42,16 +127,39 @@ func (r *ResourceCleaner) Add(ctx context.Context, description string, fn func()
 	}
 
 	// Prepend reverse iterate by default
-	r.funcs = append([]cleanupFunc{task}, r.funcs...)
+	r.funcs = append([]cleanupFunc{{
+		resourceType: resourceType,
+		resourceID:   resourceID,
+		description:  description,
+		fn:           task,
+	}}, r.funcs...)
 }
 
 // Cleanup cleans up the resource, running
 // the cleanup funcs in opposite chronological order.
+// It stops at the first failing cleanup func and returns a CleanupError.
 func (r *ResourceCleaner) Cleanup() error {
+	var cleanupErr *CleanupError
+	results := make([]CleanupResult, 0, len(r.funcs))
 	for _, f := range r.funcs {
-		if err := f(); err != nil {
-			return err
+		result := CleanupResult{
+			ResourceType: f.resourceType,
+			ResourceID:   f.resourceID,
+			Description:  f.description,
+			Outcome:      CleanupSucceeded,
+		}
+		if cleanupErr != nil {
+			result.Outcome = CleanupSkipped
+		} else if err := f.fn(); err != nil {
+			result.Outcome = CleanupFailed
+			result.Error = err.Error()
+			cleanupErr = &CleanupError{err: err}
 		}
+		results = append(results, result)
+	}
+	if cleanupErr != nil {
+		cleanupErr.Results = results
+		return cleanupErr
 	}
 	return nil
 }
@@ -80,4 +80,41 @@ var _ = t.Describe("ResourceCleaner", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(failureCnt).To(Equal(3))
 	})
+	It("should summarize the outcome of the cleanup steps", func() {
+		// Given
+		sut := resourcestore.NewResourceCleaner()
+		skipped := false
+		sut.AddResource(context.Background(), "container name", "name", "release name", func() error {
+			skipped = true
+			return nil
+		})
+		sut.AddResource(context.Background(), "container storage", "id", "delete storage", func() error {
+			return errors.New("busy")
+		})
+		sut.AddResource(context.Background(), "runtime container", "id", "delete container", func() error {
+			return nil
+		})
+
+		// When
+		err := sut.Cleanup()
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(skipped).To(BeFalse())
+		var cleanupErr *resourcestore.CleanupError
+		Expect(errors.As(err, &cleanupErr)).To(BeTrue())
+		Expect(cleanupErr.Results).To(HaveLen(3))
+		Expect(cleanupErr.Results[0].ResourceType).To(Equal("runtime container"))
+		Expect(cleanupErr.Results[0].Outcome).To(Equal(resourcestore.CleanupSucceeded))
+		Expect(cleanupErr.Results[1].ResourceType).To(Equal("container storage"))
+		Expect(cleanupErr.Results[1].Outcome).To(Equal(resourcestore.CleanupFailed))
+		Expect(cleanupErr.Results[1].Error).NotTo(BeEmpty())
+		Expect(cleanupErr.Results[2].ResourceType).To(Equal("container name"))
+		Expect(cleanupErr.Results[2].Outcome).To(Equal(resourcestore.CleanupSkipped))
+		Expect(cleanupErr.Fields()).To(Equal(map[string]any{
+			"reclaimed":    []string{"runtime container id"},
+			"notReclaimed": []string{"container storage id"},
+			"notAttempted": []string{"container name name"},
+		}))
+	})
 })
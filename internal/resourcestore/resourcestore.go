@@ -41,6 +41,8 @@ type Resource struct {
 	stale    bool
 	name     string
 	stage    string
+	// stageStart is the time the current stage was set.
+	stageStart time.Time
 }
 
 // wasPut checks that a resource has been fully defined yet.
@@ -180,6 +182,19 @@ func (rc *ResourceStore) Put(name string, resource IdentifiableCreatable, cleane
 	return nil
 }
 
+// Stage returns the last stage set for the resource name, or StageUnknown if
+// the resource is not in the store or has no stage set.
+func (rc *ResourceStore) Stage(name string) string {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	r, ok := rc.resources[name]
+	if !ok || r.stage == "" {
+		return StageUnknown
+	}
+	return r.stage
+}
+
 // Delete deletes the specified resource from the store.
 // Any resource that has a stage set, but was never Put should have Delete called, or else it will leak.
 func (rc *ResourceStore) Delete(name string) {
@@ -212,19 +227,30 @@ func (rc *ResourceStore) WatcherForResource(name string) (watcher chan struct{},
 	return watcher, r.stage
 }
 
-func (rc *ResourceStore) SetStageForResource(ctx context.Context, name, stage string) {
+// SetStageForResource sets the current creation stage of the resource name.
+// It returns the stage the resource left and how long the resource has been
+// in it, or an empty stage if the resource had no stage set before.
+func (rc *ResourceStore) SetStageForResource(ctx context.Context, name, stage string) (previous string, elapsed time.Duration) {
 	rc.mutex.Lock()
 	defer rc.mutex.Unlock()
+	now := time.Now()
 	r, ok := rc.resources[name]
 	if !ok {
 		log.Debugf(ctx, "Initializing stage for resource %s to %s", name, stage)
 		rc.resources[name] = &Resource{
-			watchers: []chan struct{}{},
-			name:     name,
-			stage:    stage,
+			watchers:   []chan struct{}{},
+			name:       name,
+			stage:      stage,
+			stageStart: now,
 		}
-		return
+		return "", 0
 	}
 	log.Debugf(ctx, "Setting stage for resource %s from %s to %s", name, r.stage, stage)
+	previous = r.stage
+	if previous != "" {
+		elapsed = now.Sub(r.stageStart)
+	}
 	r.stage = stage
+	r.stageStart = now
+	return previous, elapsed
 }
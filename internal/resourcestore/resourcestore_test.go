@@ -47,6 +47,18 @@ var _ = t.Describe("ResourceStore", func() {
 		AfterEach(func() {
 			sut.Close()
 		})
+		It("Stage should return the last stage of a resource", func() {
+			// Given
+			sut.SetStageForResource(context.Background(), testName, "first")
+			sut.SetStageForResource(context.Background(), testName, "second")
+
+			// When
+			stage := sut.Stage(testName)
+
+			// Then
+			Expect(stage).To(Equal("second"))
+			Expect(sut.Stage("other")).To(Equal(resourcestore.StageUnknown))
+		})
 		It("Put should be able to get resource after adding", func() {
 			// Given
 
@@ -205,5 +217,19 @@ var _ = t.Describe("ResourceStore", func() {
 			// Then
 			Expect(stage).To(Equal(stage2))
 		})
+		It("should return the stage left and its duration", func() {
+			// Given
+			previous, elapsed := sut.SetStageForResource(ctx, testName, "first")
+			Expect(previous).To(BeEmpty())
+			Expect(elapsed).To(BeZero())
+			time.Sleep(time.Millisecond)
+
+			// When
+			previous, elapsed = sut.SetStageForResource(ctx, testName, "second")
+
+			// Then
+			Expect(previous).To(Equal("first"))
+			Expect(elapsed).To(BeNumerically(">=", time.Millisecond))
+		})
 	})
 })
@@ -11,6 +11,10 @@ type ContainerInfo struct {
 	// value which was generated by the library.
 	ID string `json:"id"`
 
+	// BundleContainer is the ID of the container in the bundle manager,
+	// which holds the mounted root file system.
+	BundleContainer string `json:"bundle-container,omitempty"`
+
 	// Names is an optional set of user-defined convenience values.  The
 	// container can be referred to by its ID or any of its names.  Names
 	// are unique among containers.
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrImageQuarantined is returned by PullImage for an image whose previous
+// pull failed within the quarantine duration.
+var ErrImageQuarantined = errors.New("image is quarantined after a failed pull")
+
+// quarantine remembers the images whose pulls failed, so that pulling them
+// again fails fast with the cached error until the quarantine expires.
+type quarantine struct {
+	lock     sync.Mutex
+	duration time.Duration
+	entries  map[string]quarantineEntry
+	now      func() time.Time
+}
+
+type quarantineEntry struct {
+	err   error
+	until time.Time
+}
+
+// newQuarantine creates a quarantine keeping failed images for duration. A
+// zero duration disables the quarantine.
+func newQuarantine(duration time.Duration) *quarantine {
+	return &quarantine{
+		duration: duration,
+		entries:  make(map[string]quarantineEntry),
+		now:      time.Now,
+	}
+}
+
+// check returns the cached error if the image is quarantined.
+func (q *quarantine) check(image string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	entry, ok := q.entries[image]
+	if !ok {
+		return nil
+	}
+	if !q.now().Before(entry.until) {
+		delete(q.entries, image)
+		return nil
+	}
+	return fmt.Errorf("%w: %s until %s: %w", ErrImageQuarantined, image, entry.until.Format(time.RFC3339), entry.err)
+}
+
+// record quarantines the image if the pull failed and lifts the quarantine
+// if it succeeded. Canceled pulls say nothing about the image and are not
+// recorded. Expired entries of images which are not pulled again are
+// dropped along the way.
+func (q *quarantine) record(image string, err error) {
+	if q.duration == 0 || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	now := q.now()
+	for name, entry := range q.entries {
+		if !now.Before(entry.until) {
+			delete(q.entries, name)
+		}
+	}
+	if err == nil {
+		delete(q.entries, image)
+		return
+	}
+	q.entries[image] = quarantineEntry{err: err, until: now.Add(q.duration)}
+}
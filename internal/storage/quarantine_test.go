@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuarantineFastFailsRepeatedFailures(t *testing.T) {
+	sut := newQuarantine(time.Minute)
+	errPull := errors.New("unsatisfiable dependencies")
+
+	if err := sut.check("image"); err != nil {
+		t.Fatalf("expected an unknown image not to be quarantined, got %v", err)
+	}
+	sut.record("image", errPull)
+
+	for range 3 {
+		err := sut.check("image")
+		if !errors.Is(err, ErrImageQuarantined) {
+			t.Fatalf("expected the image to be quarantined, got %v", err)
+		}
+		if !errors.Is(err, errPull) {
+			t.Fatalf("expected the cached pull error, got %v", err)
+		}
+	}
+	if err := sut.check("other"); err != nil {
+		t.Errorf("expected other images not to be quarantined, got %v", err)
+	}
+}
+
+func TestQuarantineExpires(t *testing.T) {
+	now := time.Now()
+	sut := newQuarantine(time.Minute)
+	sut.now = func() time.Time { return now }
+
+	sut.record("image", errors.New("bad manifest"))
+	now = now.Add(59 * time.Second)
+	if err := sut.check("image"); err == nil {
+		t.Fatal("expected the image to be quarantined before the expiry")
+	}
+
+	now = now.Add(time.Second)
+	if err := sut.check("image"); err != nil {
+		t.Fatalf("expected the image to be retried after the expiry, got %v", err)
+	}
+}
+
+func TestQuarantineLiftedBySuccess(t *testing.T) {
+	sut := newQuarantine(time.Minute)
+
+	sut.record("image", errors.New("bad manifest"))
+	sut.record("image", nil)
+
+	if err := sut.check("image"); err != nil {
+		t.Errorf("expected a successful pull to lift the quarantine, got %v", err)
+	}
+}
+
+func TestQuarantineIgnoresCanceledPulls(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		duration time.Duration
+		err      error
+	}{
+		{"canceled", time.Minute, context.Canceled},
+		{"deadline exceeded", time.Minute, context.DeadlineExceeded},
+		{"disabled", 0, errors.New("bad manifest")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := newQuarantine(tc.duration)
+
+			sut.record("image", tc.err)
+
+			if err := sut.check("image"); err != nil {
+				t.Errorf("expected the image not to be quarantined, got %v", err)
+			}
+		})
+	}
+}
+
+func TestQuarantineDropsExpiredEntries(t *testing.T) {
+	now := time.Now()
+	sut := newQuarantine(time.Minute)
+	sut.now = func() time.Time { return now }
+
+	sut.record("image", errors.New("bad manifest"))
+	now = now.Add(time.Minute)
+	sut.record("other", errors.New("bad manifest"))
+
+	if _, ok := sut.entries["image"]; ok {
+		t.Error("expected the expired entry to be dropped")
+	}
+	if _, ok := sut.entries["other"]; !ok {
+		t.Error("expected the new entry to be kept")
+	}
+}
@@ -55,19 +55,12 @@ var (
 // same pod ID in its metadata that the pod's other members do, and
 // with the pod's infrastructure container having the same value for
 // both its pod's ID and its container ID.
+// The pause image is pulled according to pullPolicy.
 // Pointer arguments can be nil.  All other arguments are required.
-func (ss *StorageService) CreatePodSandbox(podName, podID string, pauseImage bundle.BundleName, containerName, metadataName, uid, namespace string, attempt uint32, labelOptions []string, privileged bool) (ContainerInfo, error) {
-	// Check if we have the specified image.
-	var imageID bundle.BundleId
-	status, err := ss.ImageStatusByName(pauseImage)
+func (ss *StorageService) CreatePodSandbox(podName, podID string, pauseImage bundle.BundleName, pullPolicy PullPolicy, containerName, metadataName, uid, namespace string, attempt uint32, labelOptions []string, privileged bool) (ContainerInfo, error) {
+	imageID, err := ss.PullImage(context.Background(), pauseImage, pullPolicy)
 	if err != nil {
-		var err error
-		imageID, err = ss.PullImage(context.Background(), pauseImage)
-		if err != nil {
-			return ContainerInfo{}, err
-		}
-	} else {
-		imageID, _ = bundle.ParseBundleId(status.Id)
+		return ContainerInfo{}, err
 	}
 
 	return ss.createContainerOrPodSandbox(podID, &runtimeContainerMetadataTemplate{
@@ -134,7 +127,7 @@ func (ss *StorageService) createContainerOrPodSandbox(containerID string, templa
 	now := time.Now()
 	metadata.CreatedAt = now.Unix()
 
-	id, rootFs, imgConfig, err := ss.bm.CreateContainerById(template.imageID)
+	bundleContainer, rootFs, imgConfig, err := ss.bm.CreateContainerById(template.imageID)
 	if err != nil {
 		if metadata.Pod {
 			logrus.Debugf("Failed to create pod sandbox %s(%s): %v", metadata.PodName, metadata.PodID, err)
@@ -144,47 +137,47 @@ func (ss *StorageService) createContainerOrPodSandbox(containerID string, templa
 		return ContainerInfo{}, err
 	}
 	if metadata.Pod {
-		logrus.Debugf("Created pod sandbox %q", id)
+		logrus.Debugf("Created pod sandbox %q", containerID)
 	} else {
-		logrus.Debugf("Created container %q", id)
+		logrus.Debugf("Created container %q", containerID)
 	}
 
 	// If anything fails after this point, we need to delete the incomplete
 	// container before returning.
 	defer func() {
 		if retErr != nil {
-			if err2 := ss.bm.DeleteContainer(id); err2 != nil {
+			if err2 := ss.bm.DeleteContainer(bundleContainer); err2 != nil {
 				if metadata.Pod {
-					logrus.Debugf("%v deleting partially-created pod sandbox %q", err2, id)
+					logrus.Debugf("%v deleting partially-created pod sandbox %q", err2, containerID)
 				} else {
-					logrus.Debugf("%v deleting partially-created container %q", err2, id)
+					logrus.Debugf("%v deleting partially-created container %q", err2, containerID)
 				}
 				return
 			}
-			logrus.Debugf("Deleted partially-created container %q", id)
+			logrus.Debugf("Deleted partially-created container %q", containerID)
 		}
 	}()
 
-	containerDir := filepath.Join(ss.work, id)
+	containerDir := filepath.Join(ss.work, containerID)
 	err = os.MkdirAll(containerDir, 0o755)
 	if err != nil {
 		return ContainerInfo{}, err
 	}
 	if metadata.Pod {
-		logrus.Debugf("Pod sandbox %q has work directory %q", id, containerDir)
+		logrus.Debugf("Pod sandbox %q has work directory %q", containerID, containerDir)
 	} else {
-		logrus.Debugf("Container %q has work directory %q", id, containerDir)
+		logrus.Debugf("Container %q has work directory %q", containerID, containerDir)
 	}
 
-	containerRunDir := filepath.Join(ss.run, id)
+	containerRunDir := filepath.Join(ss.run, containerID)
 	err = os.MkdirAll(containerRunDir, 0o755)
 	if err != nil {
 		return ContainerInfo{}, err
 	}
 	if metadata.Pod {
-		logrus.Debugf("Pod sandbox %q has run directory %q", id, containerRunDir)
+		logrus.Debugf("Pod sandbox %q has run directory %q", containerID, containerRunDir)
 	} else {
-		logrus.Debugf("Container %q has run directory %q", id, containerRunDir)
+		logrus.Debugf("Container %q has run directory %q", containerID, containerRunDir)
 	}
 
 	mdata, err := json.Marshal(&metadata)
@@ -193,18 +186,23 @@ func (ss *StorageService) createContainerOrPodSandbox(containerID string, templa
 		return ContainerInfo{}, err
 	}
 
-	return ContainerInfo{
-		ID:           id,
-		Names:        []string{},
-		ImageID:      template.imageID.String(),
-		Dir:          containerDir,
-		RunDir:       containerRunDir,
-		RootFs:       rootFs,
-		Config:       &v1.Image{Created: &now, Config: imgConfig},
-		Metadata:     string(mdata),
-		ProcessLabel: "",
-		MountLabel:   "",
-	}, nil
+	info := ContainerInfo{
+		ID:              containerID,
+		BundleContainer: bundleContainer,
+		Names:           []string{},
+		ImageID:         template.imageID.String(),
+		Dir:             containerDir,
+		RunDir:          containerRunDir,
+		RootFs:          rootFs,
+		Config:          &v1.Image{Created: &now, Config: imgConfig},
+		Metadata:        string(mdata),
+		ProcessLabel:    "",
+		MountLabel:      "",
+	}
+	if err := ss.saveInfo(containerID, info); err != nil {
+		return ContainerInfo{}, err
+	}
+	return info, nil
 }
 
 // DeleteContainer deletes a container, unmounting it first if need be.
@@ -216,11 +214,20 @@ func (ss *StorageService) DeleteContainer(ctx context.Context, idOrName string)
 	if idOrName == "" {
 		return ErrInvalidContainerID
 	}
-	err := ss.bm.DeleteContainer(idOrName)
+	bundleContainer := idOrName
+	if info, err := ss.loadInfo(idOrName); err == nil && info.BundleContainer != "" {
+		bundleContainer = info.BundleContainer
+	}
+	err := ss.bm.DeleteContainer(bundleContainer)
 	if err != nil {
 		log.Debugf(ctx, "Failed to delete container %q: %v", idOrName, err)
 		return err
 	}
+	for _, dir := range []string{filepath.Join(ss.work, idOrName), filepath.Join(ss.run, idOrName)} {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to delete container directory: %w", err)
+		}
+	}
 	infoFile := filepath.Join(ss.info, idOrName)
 	err = os.Remove(infoFile)
 	if err != nil && !os.IsNotExist(err) {
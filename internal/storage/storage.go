@@ -2,28 +2,37 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/L-F-Z/TaskC/pkg/bundle"
 	"golang.org/x/sync/singleflight"
+	"golang.org/x/sys/unix"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/utils"
 )
 
 type StorageService struct {
+	root                 string
 	work                 string
 	run                  string
 	info                 string
 	bm                   *bundle.BundleManager
 	regexForPinnedImages []*regexp.Regexp
 	pullGroup            singleflight.Group
+	quarantine           *quarantine
 }
 
-func NewStorageService(ctx context.Context, root string, runRoot string) (*StorageService, error) {
+// NewStorageService creates a StorageService. Images whose pull failed are
+// quarantined for quarantineDuration, zero disables the quarantine.
+func NewStorageService(ctx context.Context, root string, runRoot string, quarantineDuration time.Duration) (*StorageService, error) {
 	bm, err := bundle.NewBundleManager(root, "https://prefab.cs.ac.cn:10062/")
 	if err != nil {
 		return &StorageService{}, err
@@ -38,11 +47,13 @@ func NewStorageService(ctx context.Context, root string, runRoot string) (*Stora
 		}
 	}
 	return &StorageService{
+		root:                 root,
 		work:                 workDir,
 		run:                  runDir,
 		info:                 infoDir,
 		bm:                   bm,
 		regexForPinnedImages: []*regexp.Regexp{},
+		quarantine:           newQuarantine(quarantineDuration),
 	}, nil
 }
 
@@ -91,6 +102,68 @@ func getUser(user string) (id *int64, username string) {
 	return &uid, ""
 }
 
+// FilesystemUsage describes the usage of a filesystem holding bundles or
+// containers.
+type FilesystemUsage struct {
+	// Mountpoint is the storage directory on the filesystem.
+	Mountpoint string
+	// UsedBytes is the number of bytes used by the stored data.
+	UsedBytes uint64
+	// InodesUsed is the number of inodes used by the stored data.
+	InodesUsed uint64
+	// AvailableBytes is the number of bytes available on the filesystem.
+	AvailableBytes uint64
+	// AvailableInodes is the number of inodes available on the filesystem.
+	AvailableInodes uint64
+}
+
+// ImageFsUsage returns the usage of the filesystem holding the bundles. The
+// used bytes are the sum of the sizes recorded for the stored bundles, so
+// that polling it does not walk the bundle directories. The used inodes are
+// the ones of the whole filesystem.
+func (ss *StorageService) ImageFsUsage() (*FilesystemUsage, error) {
+	usage, err := statfsUsage(ss.root)
+	if err != nil {
+		return nil, err
+	}
+	bundles, err := ss.bm.List()
+	if err != nil {
+		return nil, fmt.Errorf("list bundles: %w", err)
+	}
+	for _, b := range bundles {
+		usage.UsedBytes += b.Size
+	}
+	return usage, nil
+}
+
+// ContainerFsUsage returns the usage of the filesystem holding the container
+// working directories. The used bytes and inodes are the ones below the
+// working directories.
+func (ss *StorageService) ContainerFsUsage() (*FilesystemUsage, error) {
+	usage, err := statfsUsage(ss.work)
+	if err != nil {
+		return nil, err
+	}
+	usage.UsedBytes, usage.InodesUsed, err = utils.GetDiskUsageStats(ss.work)
+	if err != nil {
+		return nil, fmt.Errorf("get disk usage for path %s: %w", ss.work, err)
+	}
+	return usage, nil
+}
+
+func statfsUsage(path string) (*FilesystemUsage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return nil, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	return &FilesystemUsage{
+		Mountpoint:      path,
+		InodesUsed:      uint64(st.Files) - uint64(st.Ffree),
+		AvailableBytes:  uint64(st.Bavail) * uint64(st.Bsize),
+		AvailableInodes: uint64(st.Ffree),
+	}, nil
+}
+
 // ImageStatusByID returns status of a single image
 func (ss *StorageService) ImageStatusByID(id bundle.BundleId) (img *types.Image, err error) {
 	bundle, err := ss.bm.GetById(id)
@@ -124,14 +197,56 @@ func (ss *StorageService) ImageStatusByName(name bundle.BundleName) (img *types.
 		Size_:       0,
 		Uid:         &types.Int64Value{Value: *uid},
 		Username:    username,
+		Spec:        &types.ImageSpec{Image: name.String()},
 		Pinned:      false,
 	}
 	return
 }
 
-// PullImage imports an image from the specified location.
-func (ss *StorageService) PullImage(ctx context.Context, imageName bundle.BundleName) (id bundle.BundleId, err error) {
+// PullPolicy defines whether PullImage fetches an image which is already
+// stored locally.
+type PullPolicy string
+
+const (
+	// PullAlways fabricates or downloads the image even if a local bundle exists.
+	PullAlways PullPolicy = "Always"
+	// PullIfNotPresent only fetches the image if there is no local bundle.
+	PullIfNotPresent PullPolicy = "IfNotPresent"
+	// PullNever never fetches the image and fails if there is no local bundle.
+	PullNever PullPolicy = "Never"
+)
+
+// ErrImageNotPresent is returned by PullImage for an image which is not
+// stored locally with the PullNever policy.
+var ErrImageNotPresent = errors.New("image not present locally")
+
+// ParsePullPolicy parses the name of a pull policy.
+func ParsePullPolicy(policy string) (PullPolicy, error) {
+	switch p := PullPolicy(policy); p {
+	case PullAlways, PullIfNotPresent, PullNever:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid pull policy %q, must be one of %s, %s or %s", policy, PullAlways, PullIfNotPresent, PullNever)
+	}
+}
+
+// PullImage imports an image from the specified location, honoring the pull
+// policy for images which are already stored locally. Images whose previous
+// pull failed are quarantined and fail fast with the cached error.
+func (ss *StorageService) PullImage(ctx context.Context, imageName bundle.BundleName, policy PullPolicy) (id bundle.BundleId, err error) {
+	if policy != PullAlways {
+		if b, err := ss.bm.Get(imageName.Name, imageName.Version); err == nil {
+			return b.Id, nil
+		}
+		if policy == PullNever {
+			return "", fmt.Errorf("%w: %s with pull policy %s", ErrImageNotPresent, imageName, policy)
+		}
+	}
+
 	key := imageName.String()
+	if err := ss.quarantine.check(key); err != nil {
+		return "", err
+	}
 	res, err, _ := ss.pullGroup.Do(key, func() (interface{}, error) {
 		if err := ss.bm.AssembleHandler(bundle.AssembleConfig{
 			ClosureName:    imageName.Name,
@@ -147,6 +262,7 @@ func (ss *StorageService) PullImage(ctx context.Context, imageName bundle.Bundle
 		}
 		return b.Id, nil
 	})
+	ss.quarantine.record(key, err)
 	if err != nil {
 		return "", err
 	}
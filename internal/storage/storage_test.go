@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/L-F-Z/TaskC/pkg/bundle"
+)
+
+func TestImageFsUsageSumsBundleSizes(t *testing.T) {
+	root := t.TempDir()
+	for path, data := range map[string]string{
+		filepath.Join(root, "Bundle", "a", "bundle.json"): `{"Id": "a", "Size": 100}`,
+		filepath.Join(root, "Bundle", "b", "bundle.json"): `{"Id": "b", "Size": 20}`,
+		filepath.Join(root, "Bundle", "Bundles.json"):     `{"a": {"1": "a"}, "b": {"1": "b"}}`,
+		// Files below the bundles are not walked, only the recorded
+		// bundle sizes count.
+		filepath.Join(root, "Bundle", "a", "rootfs", "file"): string(make([]byte, 1<<20)),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bm, err := bundle.NewBundleManager(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sut := &StorageService{root: root, bm: bm}
+
+	usage, err := sut.ImageFsUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Mountpoint != root || usage.UsedBytes != 120 {
+		t.Errorf("expected 120 bytes used by the bundles below %s, got %+v", root, usage)
+	}
+	if usage.InodesUsed == 0 || usage.AvailableBytes == 0 || usage.AvailableInodes == 0 {
+		t.Errorf("expected the filesystem totals, got %+v", usage)
+	}
+}
+
+func TestContainerFsUsage(t *testing.T) {
+	root := t.TempDir()
+	sut := &StorageService{work: filepath.Join(root, "containerWork")}
+	path := filepath.Join(sut.work, "ctr", "rootfs", "file")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Data next to the working directories is not counted.
+	if err := os.WriteFile(filepath.Join(root, "other"), make([]byte, 1<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := sut.ContainerFsUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// containerWork, ctr, rootfs and file
+	if usage.Mountpoint != sut.work || usage.InodesUsed != 4 || usage.UsedBytes < 10 || usage.UsedBytes >= 1<<20 {
+		t.Errorf("expected the container usage to only cover the working directories, got %+v", usage)
+	}
+	if usage.AvailableBytes == 0 || usage.AvailableInodes == 0 {
+		t.Errorf("expected the filesystem totals, got %+v", usage)
+	}
+}
+
+func TestFsUsageMissingDirectory(t *testing.T) {
+	sut := &StorageService{work: filepath.Join(t.TempDir(), "missing")}
+	if _, err := sut.ContainerFsUsage(); err == nil {
+		t.Error("expected an error for a missing storage directory")
+	}
+}
+
+func TestImageStatusByNameHasSpec(t *testing.T) {
+	root := t.TempDir()
+	for path, data := range map[string]string{
+		filepath.Join(root, "Bundle", "a", "bundle.json"): `{"Id": "a", "Blueprint": {"Name": "image", "User": "0"}}`,
+		filepath.Join(root, "Bundle", "Bundles.json"):     `{"image": {"1.0": "a"}}`,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bm, err := bundle.NewBundleManager(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sut := &StorageService{root: root, bm: bm}
+	name := bundle.BundleName{Name: "image", Version: "1.0"}
+
+	img, err := sut.ImageStatusByName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Container creation reads the image annotations from the spec.
+	if img.GetSpec() == nil || img.GetSpec().GetImage() != name.String() {
+		t.Errorf("expected the spec of image %q, got %v", name, img.GetSpec())
+	}
+}
@@ -20,6 +20,15 @@ const (
 	// ShmSizeAnnotation is the K8S annotation used to set custom shm size.
 	ShmSizeAnnotation = "io.kubernetes.cri-o.ShmSize"
 
+	// PrivateShmAnnotation gives a container a dedicated /dev/shm instead of
+	// the one shared by the containers of the pod.
+	PrivateShmAnnotation = "io.kubernetes.cri-o.PrivateShm"
+
+	// DNSConfigAnnotation gives a container a dedicated resolv.conf instead of
+	// the one of the pod. It is set on the container. Its value is a JSON
+	// object with the "servers", "searches" and "options" of the resolv.conf.
+	DNSConfigAnnotation = "io.kubernetes.cri-o.DNSConfig"
+
 	// DevicesAnnotation is a set of devices to give to the container.
 	DevicesAnnotation = "io.kubernetes.cri-o.Devices"
 
@@ -83,6 +92,47 @@ const (
 
 	// DisableFIPSAnnotation is used to disable FIPS mode for a pod within a FIPS-enabled Kubernetes cluster.
 	DisableFIPSAnnotation = "io.kubernetes.cri-o.DisableFIPS"
+
+	// EnableFIPSAnnotation is used to enable FIPS mode for a pod on a node which has not enabled it.
+	// It cannot be combined with DisableFIPSAnnotation.
+	EnableFIPSAnnotation = "io.kubernetes.cri-o.EnableFIPS"
+
+	// DisableMtabSymlinkAnnotation disables the /etc/mtab to /proc/mounts symlink created in
+	// the container rootfs when set to "true". It can be set on a pod or a container.
+	DisableMtabSymlinkAnnotation = "io.kubernetes.cri-o.DisableMtabSymlink"
+
+	// DryRunAnnotation makes a container creation only generate the OCI spec of the container
+	// when set to "true" on the container. The spec is written to a temporary file and
+	// everything reserved for the container is rolled back afterwards. The creation then
+	// fails with an Aborted error naming the spec file.
+	DryRunAnnotation = "io.kubernetes.cri-o.DryRun"
+
+	// SkipNRIAnnotation hides a pod or container from NRI plugins when set to "true".
+	// None of the NRI hooks are invoked for it, so plugins cannot adjust it. Set on a
+	// pod, it applies to all containers of the pod.
+	SkipNRIAnnotation = "io.kubernetes.cri-o.SkipNRI"
+
+	// RestoreFromCheckpointAnnotation restores a container from the checkpoint at the
+	// path it is set to, either a checkpoint archive or a directory holding its content.
+	// The checkpoint has to be taken from a container of the same image, and
+	// enable_criu_support has to be set.
+	RestoreFromCheckpointAnnotation = "io.kubernetes.cri-o.RestoreFromCheckpoint"
+
+	// HostUTSAnnotation makes a pod in the host network namespace use the host UTS
+	// namespace as well when set to "true". Containers of the pod with CAP_SYS_ADMIN
+	// can then change the hostname of the node.
+	HostUTSAnnotation = "io.kubernetes.cri-o.HostUTS"
+
+	// AdditionalGIDsAnnotation is an image config label holding a comma separated list of
+	// additional group IDs for the user of the image. They are only applied with the Merge
+	// supplemental groups policy.
+	AdditionalGIDsAnnotation = "io.kubernetes.cri-o.AdditionalGIDs"
+
+	// ImagePullPolicyAnnotation sets the pull policy for the images of a pod, one of
+	// "Always", "IfNotPresent" or "Never". It applies to the pause image of the pod and
+	// to the images pulled with the pod as sandbox config, if it is allowed for the
+	// runtime handler or the workload of the pod.
+	ImagePullPolicyAnnotation = "io.kubernetes.cri-o.ImagePullPolicy"
 )
 
 var AllAllowedAnnotations = []string{
@@ -90,6 +140,8 @@ var AllAllowedAnnotations = []string{
 	Cgroup2RWAnnotation,
 	UnifiedCgroupAnnotation,
 	ShmSizeAnnotation,
+	PrivateShmAnnotation,
+	DNSConfigAnnotation,
 	DevicesAnnotation,
 	CPULoadBalancingAnnotation,
 	CPUQuotaAnnotation,
@@ -107,6 +159,13 @@ var AllAllowedAnnotations = []string{
 	CPUSharedAnnotation,
 	SeccompProfileAnnotation,
 	DisableFIPSAnnotation,
+	EnableFIPSAnnotation,
+	DisableMtabSymlinkAnnotation,
+	DryRunAnnotation,
+	SkipNRIAnnotation,
+	RestoreFromCheckpointAnnotation,
+	HostUTSAnnotation,
+	ImagePullPolicyAnnotation,
 	// Keep in sync with
 	// https://github.com/opencontainers/runc/blob/3db0871f1cf25c7025861ba0d51d25794cb21623/features.go#L67
 	// Once runc 1.2 is released, we can use the `runc features` command to get this programmatically,
@@ -6,23 +6,28 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/containers/common/pkg/hooks"
+	"github.com/containers/common/pkg/signal"
 	conmonconfig "github.com/containers/conmon/runner/config"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 	"github.com/docker/go-units"
 	"github.com/opencontainers/runtime-spec/specs-go/features"
 	selinux "github.com/opencontainers/selinux/go-selinux"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/cpuset"
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 
@@ -41,11 +46,13 @@ import (
 	"github.com/L-F-Z/cri-t/internal/config/seccomp"
 	"github.com/L-F-Z/cri-t/internal/config/ulimits"
 	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/naming"
 	"github.com/L-F-Z/cri-t/internal/storage"
 	"github.com/L-F-Z/cri-t/pkg/annotations"
 	"github.com/L-F-Z/cri-t/server/metrics/collectors"
 	"github.com/L-F-Z/cri-t/utils"
 	"github.com/L-F-Z/cri-t/utils/cmdrunner"
+	"github.com/L-F-Z/cri-t/utils/errdefs"
 )
 
 // Defaults if none are specified.
@@ -60,8 +67,14 @@ const (
 	RuntimeTypePod                = "pod"
 	defaultCtrStopTimeout         = 30 // seconds
 	defaultNamespacesDir          = "/var/run"
+	defaultShutdownTimeout        = 30 * time.Second
+	defaultAuditWebhookTimeout    = 10 * time.Second
+	defaultCNIPluginWaitTimeout   = time.Minute
+	defaultStreamIdleTimeout      = "4h"
 	RuntimeTypeVMBinaryPattern    = "containerd-shim-([a-zA-Z0-9\\-\\+])+-v2"
 	tasksetBinary                 = "taskset"
+	minOOMScoreAdj                = -1000
+	maxOOMScoreAdj                = 1000
 	MonitorExecCgroupDefault      = ""
 	MonitorExecCgroupContainer    = "container"
 )
@@ -105,6 +118,19 @@ const (
 	// ImageVolumesBind option is for using bind mounted volumes.
 )
 
+// ImageMountLowerdirOrderType describes the precedence of the overlay lower
+// directories used for image mounts.
+type ImageMountLowerdirOrderType string
+
+const (
+	// ImageMountLowerdirImageFirst gives the mounted image precedence over
+	// the image volumes scratch directory.
+	ImageMountLowerdirImageFirst ImageMountLowerdirOrderType = "image-first"
+	// ImageMountLowerdirScratchFirst gives the image volumes scratch
+	// directory precedence over the mounted image.
+	ImageMountLowerdirScratchFirst ImageMountLowerdirOrderType = "scratch-first"
+)
+
 const (
 	// DefaultPidsLimit is the default value for maximum number of processes
 	// allowed inside a container.
@@ -113,6 +139,22 @@ const (
 	// DefaultLogSizeMax is the default value for the maximum log size
 	// allowed for a container. Negative values mean that no limit is imposed.
 	DefaultLogSizeMax = -1
+
+	// DefaultShmSize is the default size of /dev/shm for pods not sharing the
+	// host IPC namespace.
+	DefaultShmSize = "64Mi"
+
+	// DefaultInfraCtrOOMScoreAdj is the default OOM score adjustment of infra
+	// containers. It is very low, so that they do not get killed.
+	DefaultInfraCtrOOMScoreAdj = -998
+
+	// DefaultTerm is the default value of the TERM environment variable for
+	// containers requesting a TTY.
+	DefaultTerm = "xterm"
+
+	// DefaultStopSignal is the default stop signal of containers whose image
+	// does not declare one.
+	DefaultStopSignal = "SIGTERM"
 )
 
 const (
@@ -159,6 +201,11 @@ type RootConfig struct {
 	// that checks whether we've had time to sync before shutting down
 	CleanShutdownFile string `toml:"clean_shutdown_file"`
 
+	// ShutdownTimeout is the maximum time CRI-O waits for in-flight container
+	// and pod sandbox creations to finish on shutdown before canceling them.
+	// A value of 0 cancels them immediately.
+	ShutdownTimeout time.Duration `toml:"shutdown_timeout"`
+
 	// InternalWipe is whether CRI-O should wipe containers and images after a reboot when the server starts.
 	// If set to false, one must use the external command `crio wipe` to wipe the containers and images in these situations.
 	// The option InternalWipe is deprecated, and will be removed in a future release.
@@ -171,6 +218,7 @@ type RootConfig struct {
 // runtimeHandlerFeatures represents the supported features of the runtime.
 type runtimeHandlerFeatures struct {
 	RecursiveReadOnlyMounts bool `json:"-"` // Internal use only.
+	Loaded                  bool `json:"-"` // Internal use only.
 	features.Features
 }
 
@@ -190,6 +238,9 @@ type RuntimeHandler struct {
 	// "io.kubernetes.cri-o.userns-mode" for configuring a user namespace for the pod.
 	// "io.kubernetes.cri-o.Devices" for configuring devices for the pod.
 	// "io.kubernetes.cri-o.ShmSize" for configuring the size of /dev/shm.
+	// "io.kubernetes.cri-o.ShmSize.$CTR_NAME" for configuring the size of a dedicated /dev/shm for a container.
+	// "io.kubernetes.cri-o.PrivateShm.$CTR_NAME" for giving a container a dedicated /dev/shm.
+	// "io.kubernetes.cri-o.DNSConfig" for giving a container a dedicated resolv.conf, set on the container and configured by a JSON object with "servers", "searches" and "options".
 	// "io.kubernetes.cri-o.UnifiedCgroup.$CTR_NAME" for configuring the cgroup v2 unified block for a container.
 	// "io.containers.trace-syscall" for tracing syscalls via the OCI seccomp BPF hook.
 	// "io.kubernetes.cri-o.LinkLogs" for linking logs into the pod.
@@ -200,6 +251,11 @@ type RuntimeHandler struct {
 	//   For images, the plain annotation `seccomp-profile.kubernetes.cri-o.io`
 	//   can be used without the required `/POD` suffix or a container name.
 	// "io.kubernetes.cri-o.DisableFIPS" for disabling FIPS mode for a pod within a FIPS-enabled Kubernetes cluster.
+	// "io.kubernetes.cri-o.EnableFIPS" for enabling FIPS mode for a pod on a node which has not enabled it.
+	// "io.kubernetes.cri-o.DisableMtabSymlink" for disabling the /etc/mtab symlink to /proc/mounts in a container.
+	// "io.kubernetes.cri-o.DryRun" for only generating the OCI spec of a container, without creating it.
+	// "io.kubernetes.cri-o.SkipNRI" for not invoking NRI plugins for a pod or container.
+	// "io.kubernetes.cri-o.RestoreFromCheckpoint" for restoring a container from a checkpoint archive or directory.
 	AllowedAnnotations []string `toml:"allowed_annotations,omitempty"`
 
 	// DisallowedAnnotations is the slice of experimental annotations that are not allowed for this handler.
@@ -241,6 +297,23 @@ type RuntimeHandler struct {
 	// Default annotations specified for runtime handler if they're not overridden by
 	// the pod spec.
 	DefaultAnnotations map[string]string `toml:"default_annotations,omitempty"`
+
+	// DefaultEnv are additional environment variables to set for all the
+	// containers run by this runtime handler. They are applied after the
+	// global default_env and are overridden if set in the container image
+	// spec or in the container runtime configuration.
+	DefaultEnv []string `toml:"default_env,omitempty"`
+
+	// AbsentMountSourcesToReject is a list of paths that, when absent from
+	// the host, cause the creation of containers run by this runtime handler
+	// to fail. It is merged with the global absent_mount_sources_to_reject.
+	AbsentMountSourcesToReject []string `toml:"absent_mount_sources_to_reject,omitempty"`
+
+	// AllowedUnsafeSysctls is a list of sysctls which pods of this runtime
+	// handler may set, even if they are not known to be namespaced. A
+	// pattern ending with "*" allows all sysctls with the preceding prefix.
+	// Sysctls known not to be namespaced, like "vm.*", cannot be allowed.
+	AllowedUnsafeSysctls []string `toml:"allowed_unsafe_sysctls,omitempty"`
 }
 
 // Multiple runtime Handlers in a map.
@@ -289,9 +362,20 @@ type RuntimeConfig struct {
 	// container image spec or in the container runtime configuration.
 	DefaultEnv []string `toml:"default_env"`
 
+	// DefaultTerm is the value of the TERM environment variable set for
+	// containers requesting a TTY, unless the container image spec or the
+	// container runtime configuration already provide one. An empty value
+	// disables setting TERM.
+	DefaultTerm string `toml:"default_term"`
+
 	// Sysctls to add to all containers.
 	DefaultSysctls []string `toml:"default_sysctls"`
 
+	// StrictSysctls fails the creation of pod sandboxes with sysctls which
+	// are not namespaced or conflict with the host namespaces of the pod,
+	// instead of skipping them.
+	StrictSysctls bool `toml:"strict_sysctls"`
+
 	// DefaultUlimits specifies the default ulimits to apply to containers
 	DefaultUlimits []string `toml:"default_ulimits"`
 
@@ -324,6 +408,10 @@ type RuntimeConfig struct {
 	// default for the runtime.
 	SeccompProfile string `toml:"seccomp_profile"`
 
+	// SeccompProfileDir is the directory of the seccomp profiles which can
+	// be requested by name with the seccomp profile annotation.
+	SeccompProfileDir string `toml:"seccomp_profile_dir"`
+
 	// ApparmorProfile is the apparmor profile name which is used as the
 	// default for the runtime.
 	ApparmorProfile string `toml:"apparmor_profile"`
@@ -410,6 +498,24 @@ type RuntimeConfig struct {
 	// by the cgroup process number controller.
 	PidsLimit int64 `toml:"pids_limit"`
 
+	// MaxPods is the maximum number of pod sandboxes on the node. Zero means
+	// that the number is not limited.
+	MaxPods int `toml:"max_pods"`
+
+	// MaxContainers is the maximum number of containers on the node, not
+	// counting infra containers. Zero means that the number is not limited.
+	MaxContainers int `toml:"max_containers"`
+
+	// DefaultShmSize is the size of /dev/shm for pods not sharing the host
+	// IPC namespace, expressed as a byte quantity like "64Mi". It can be
+	// overridden per pod with the ShmSize annotation.
+	DefaultShmSize string `toml:"default_shm_size"`
+
+	// DefaultUmask is the umask of the container init process, expressed as
+	// an octal string like "0022". It can be overridden per pod with the
+	// umask annotation. If empty, the umask of the OCI runtime is used.
+	DefaultUmask string `toml:"default_umask"`
+
 	// LogSizeMax is the maximum number of bytes after which the log file
 	// will be truncated. It can be expressed as a human-friendly string
 	// that is parsed to bytes.
@@ -420,12 +526,19 @@ type RuntimeConfig struct {
 	// error because the container state is still tagged as "running".
 	CtrStopTimeout int64 `toml:"ctr_stop_timeout"`
 
+	// DefaultStopSignal is the signal which stops containers whose image
+	// does not declare a stop signal.
+	DefaultStopSignal string `toml:"default_stop_signal"`
+
 	// SeparatePullCgroup specifies whether an image pull must be performed in a separate cgroup
 	SeparatePullCgroup string `toml:"separate_pull_cgroup"`
 
 	// InfraCtrCPUSet is the CPUs set that will be used to run infra containers
 	InfraCtrCPUSet string `toml:"infra_ctr_cpuset"`
 
+	// InfraCtrOOMScoreAdj is the OOM score adjustment of infra containers.
+	InfraCtrOOMScoreAdj int `toml:"infra_ctr_oom_score_adj"`
+
 	// SharedCPUSet is the CPUs set that will be used for guaranteed containers that
 	// want access to shared cpus.
 	SharedCPUSet string `toml:"shared_cpuset"`
@@ -434,9 +547,32 @@ type RuntimeConfig struct {
 	// will cause a container creation to fail (as opposed to the current behavior of creating a directory).
 	AbsentMountSourcesToReject []string `toml:"absent_mount_sources_to_reject"`
 
+	// DeniedMountSources is a list of host paths which cannot be bind
+	// mounted into containers, including everything below and above them.
+	DeniedMountSources []string `toml:"denied_mount_sources"`
+
+	// AllowedMountSources is a list of host paths below which bind mount
+	// sources have to be. An empty list allows all host paths which are not
+	// denied.
+	AllowedMountSources []string `toml:"allowed_mount_sources"`
+
+	// RelabelWorkers is the number of bind mount sources relabeled in
+	// parallel during container creation. Zero relabels them one after
+	// another.
+	RelabelWorkers int `toml:"relabel_workers"`
+
+	// RROMountsFallbackToReadOnly degrades recursive read-only mounts to
+	// regular read-only mounts if the runtime or the kernel does not support
+	// them, instead of failing the container creation.
+	RROMountsFallbackToReadOnly bool `toml:"rro_mounts_fallback_to_read_only"`
+
 	// EnablePodEvents specifies if the container pod-level events should be generated to optimize the PLEG at Kubelet.
 	EnablePodEvents bool `toml:"enable_pod_events"`
 
+	// EnableCriuSupport enables checkpointing containers and restoring them
+	// from checkpoints with CRIU.
+	EnableCriuSupport bool `toml:"enable_criu_support"`
+
 	// IrqBalanceConfigRestoreFile is the irqbalance service banned CPU list to restore.
 	// If empty, no restoration attempt will be done.
 	IrqBalanceConfigRestoreFile string `toml:"irqbalance_config_restore_file"`
@@ -480,6 +616,24 @@ type RuntimeConfig struct {
 	// Option to set the timezone inside the container.
 	// Use 'Local' to match the timezone of the host machine.
 	Timezone string `toml:"timezone"`
+
+	// NamingStrategy is the strategy generating the names of pod sandboxes
+	// and containers.
+	NamingStrategy string `toml:"naming_strategy"`
+
+	// AuditWebhookURL is the HTTP(S) endpoint container creations and
+	// removals are POSTed to. A non 2xx response to a creation aborts it.
+	// The webhook is disabled if empty.
+	AuditWebhookURL string `toml:"audit_webhook_url"`
+
+	// AuditWebhookTimeout is the maximum time a call of the audit webhook
+	// may take. It must be positive, so that a hanging webhook cannot block
+	// container operations.
+	AuditWebhookTimeout time.Duration `toml:"audit_webhook_timeout"`
+
+	// AuditWebhookFailOpen lets container creations proceed if the audit
+	// webhook cannot be reached or times out, instead of failing them.
+	AuditWebhookFailOpen bool `toml:"audit_webhook_fail_open"`
 }
 
 // ImageConfig represents the "crio.image" TOML config table.
@@ -501,6 +655,20 @@ type ImageConfig struct {
 	PinnedImages []string `toml:"pinned_images"`
 	// ImageVolumes controls how volumes specified in image config are handled
 	ImageVolumes ImageVolumesType `toml:"image_volumes"`
+	// ImageVolumesReadOnly mounts the volumes specified in the image config
+	// read-only. Only applies to the "mkdir" and "bind" image volume types.
+	ImageVolumesReadOnly bool `toml:"image_volumes_read_only"`
+	// ImageMountLowerdirOrder controls the precedence of the overlay lower
+	// directories of image mounts.
+	ImageMountLowerdirOrder ImageMountLowerdirOrderType `toml:"image_mount_lowerdir_order"`
+	// ImageVolumesDir is the empty scratch directory used as overlay lower
+	// directory of image mounts. It must be an absolute path. If empty, it is
+	// derived from ContainerExitsDir during validation.
+	ImageVolumesDir string `toml:"image_volumes_dir"`
+	// ImageMountsCopyOnWrite makes image mounts which are not requested
+	// read-only writable through a per container overlay upper directory.
+	// Without it, all image mounts are read-only.
+	ImageMountsCopyOnWrite bool `toml:"image_mounts_copy_on_write"`
 	// Temporary directory for big files
 	BigFilesTemporaryDir string `toml:"big_files_temporary_dir"`
 	// PullProgressTimeout is the timeout for an image pull to make progress
@@ -508,6 +676,10 @@ type ImageConfig struct {
 	// calculating the pull progress interval to pullProgressTimeout / 10.
 	// Can be set to 0 to disable the timeout as well as the progress output.
 	PullProgressTimeout time.Duration `toml:"pull_progress_timeout"`
+	// ImageQuarantineDuration is how long pulls of an image fail fast with
+	// the error of its last failed pull. The default of 0 disables the
+	// quarantine.
+	ImageQuarantineDuration time.Duration `toml:"image_quarantine_duration"`
 }
 
 // NetworkConfig represents the "crio.network" TOML config table.
@@ -524,6 +696,15 @@ type NetworkConfig struct {
 	// PluginDirs is where CNI plugin binaries are stored.
 	PluginDirs []string `toml:"plugin_dirs"`
 
+	// SetupTimeout is the maximum time the setup of a pod network may take.
+	// The deadline of the request caps it. If zero, the setup may take as
+	// long as the request.
+	SetupTimeout time.Duration `toml:"setup_timeout"`
+
+	// PluginWaitTimeout is the maximum time a pod sandbox creation waits for
+	// the CNI plugin to get ready. If zero, it waits as long as the request.
+	PluginWaitTimeout time.Duration `toml:"plugin_wait_timeout"`
+
 	// cniManager manages the internal ocicni plugin
 	cniManager *cnimgr.CNIManager
 }
@@ -536,6 +717,19 @@ type APIConfig struct {
 	// GRPCMaxRecvMsgSize is the maximum grpc receive message size in bytes.
 	GRPCMaxRecvMsgSize int `toml:"grpc_max_recv_msg_size"`
 
+	// GRPCMaxConcurrentStreams is the maximum number of concurrent streams
+	// of each grpc client connection. If it is 0, the streams are not limited.
+	GRPCMaxConcurrentStreams int `toml:"grpc_max_concurrent_streams"`
+
+	// GRPCKeepaliveTime is the duration after which the grpc server pings an
+	// idle client connection. If it is 0, the grpc default of 2 hours is used.
+	GRPCKeepaliveTime time.Duration `toml:"grpc_keepalive_time"`
+
+	// GRPCKeepaliveTimeout is the duration the grpc server waits for the
+	// answer to a ping before it closes the connection. If it is 0, the grpc
+	// default of 20 seconds is used.
+	GRPCKeepaliveTimeout time.Duration `toml:"grpc_keepalive_timeout"`
+
 	// Listen is the path to the AF_LOCAL socket on which cri-o will listen.
 	// This may support proto://addr formats later, but currently this is just
 	// a path.
@@ -560,7 +754,13 @@ type APIConfig struct {
 	// communication with the tls encrypted stream
 	StreamTLSCA string `toml:"stream_tls_ca"`
 
-	// StreamIdleTimeout is how long to leave idle connections open for
+	// StreamRequireClientCert enforces that clients of the encrypted stream
+	// present a certificate signed by StreamTLSCA, by requiring StreamTLSCA
+	// to be set.
+	StreamRequireClientCert bool `toml:"stream_require_client_cert"`
+
+	// StreamIdleTimeout is how long to leave idle connections open for.
+	// A timeout of 0 keeps idle streams open.
 	StreamIdleTimeout string `toml:"stream_idle_timeout"`
 }
 
@@ -779,6 +979,7 @@ func DefaultConfig() (*Config, error) {
 			LogDir:            "/var/log/crio/pods",
 			VersionFile:       CrioVersionPathTmp,
 			CleanShutdownFile: CrioCleanShutdownFile,
+			ShutdownTimeout:   defaultShutdownTimeout,
 			InternalWipe:      true,
 			InternalRepair:    true,
 		},
@@ -786,6 +987,7 @@ func DefaultConfig() (*Config, error) {
 			Listen:             CrioSocketPath,
 			StreamAddress:      "127.0.0.1",
 			StreamPort:         "0",
+			StreamIdleTimeout:  defaultStreamIdleTimeout,
 			GRPCMaxSendMsgSize: defaultGRPCMaxMsgSize,
 			GRPCMaxRecvMsgSize: defaultGRPCMaxMsgSize,
 		},
@@ -803,12 +1005,16 @@ func DefaultConfig() (*Config, error) {
 			RdtConfigFile:               rdt.DefaultRdtConfigFile,
 			CgroupManagerName:           cgroupManager.Name(),
 			PidsLimit:                   DefaultPidsLimit,
+			DefaultShmSize:              DefaultShmSize,
+			InfraCtrOOMScoreAdj:         DefaultInfraCtrOOMScoreAdj,
+			DefaultTerm:                 DefaultTerm,
 			ContainerExitsDir:           containerExitsDir,
 			ContainerAttachSocketDir:    conmonconfig.ContainerAttachSocketDir,
 			MinimumMappableUID:          -1,
 			MinimumMappableGID:          -1,
 			LogSizeMax:                  DefaultLogSizeMax,
 			CtrStopTimeout:              defaultCtrStopTimeout,
+			DefaultStopSignal:           DefaultStopSignal,
 			DefaultCapabilities:         capabilities.Default(),
 			LogLevel:                    "info",
 			HooksDir:                    []string{hooks.DefaultDir},
@@ -826,17 +1032,21 @@ func DefaultConfig() (*Config, error) {
 			ulimitsConfig:               ulimits.New(),
 			HostNetworkDisableSELinux:   true,
 			DisableHostPortMapping:      false,
+			NamingStrategy:              naming.Kubernetes,
+			AuditWebhookTimeout:         defaultAuditWebhookTimeout,
 		},
 		ImageConfig: ImageConfig{
-			DefaultTransport:    "docker://",
-			PauseImage:          DefaultPauseImage,
-			PauseCommand:        "/pause",
-			ImageVolumes:        ImageVolumesMkdir,
-			PullProgressTimeout: 0,
+			DefaultTransport:        "docker://",
+			PauseImage:              DefaultPauseImage,
+			PauseCommand:            "/pause",
+			ImageVolumes:            ImageVolumesMkdir,
+			ImageMountLowerdirOrder: ImageMountLowerdirImageFirst,
+			PullProgressTimeout:     0,
 		},
 		NetworkConfig: NetworkConfig{
-			NetworkDir: cniConfigDir,
-			PluginDirs: []string{cniBinDir},
+			NetworkDir:        cniConfigDir,
+			PluginDirs:        []string{cniBinDir},
+			PluginWaitTimeout: defaultCNIPluginWaitTimeout,
 		},
 		MetricsConfig: MetricsConfig{
 			MetricsHost:       "127.0.0.1",
@@ -864,6 +1074,28 @@ func (c *Config) Validate(onExecution bool) error {
 	default:
 		return errors.New("unrecognized image volume type specified")
 	}
+	if c.ImageVolumesReadOnly && c.ImageVolumes == ImageVolumesIgnore {
+		return fmt.Errorf("image volumes cannot be read-only when image volume type is %q", ImageVolumesIgnore)
+	}
+
+	switch c.ImageMountLowerdirOrder {
+	case ImageMountLowerdirImageFirst:
+	case ImageMountLowerdirScratchFirst:
+	default:
+		return fmt.Errorf("unrecognized image mount lowerdir order %q", c.ImageMountLowerdirOrder)
+	}
+
+	// If empty, use a directory next to the container exit files.
+	if c.ImageVolumesDir == "" {
+		c.ImageVolumesDir = filepath.Join(filepath.Dir(c.ContainerExitsDir), "image-volumes")
+	}
+	if !filepath.IsAbs(c.ImageVolumesDir) {
+		return fmt.Errorf("image volumes dir %q is not an absolute path", c.ImageVolumesDir)
+	}
+
+	if c.ImageQuarantineDuration < 0 {
+		return errors.New("image_quarantine_duration must not be negative")
+	}
 
 	if onExecution {
 		if err := node.ValidateConfig(); err != nil {
@@ -913,6 +1145,25 @@ func (c *APIConfig) Validate(onExecution bool) error {
 	if c.GRPCMaxRecvMsgSize <= 0 {
 		c.GRPCMaxRecvMsgSize = defaultGRPCMaxMsgSize
 	}
+	if c.GRPCMaxConcurrentStreams < 0 || uint64(c.GRPCMaxConcurrentStreams) > math.MaxUint32 {
+		return fmt.Errorf("grpc_max_concurrent_streams %d is out of range [0, %d]", c.GRPCMaxConcurrentStreams, uint32(math.MaxUint32))
+	}
+	if c.GRPCKeepaliveTime < 0 {
+		return errors.New("grpc_keepalive_time cannot be negative")
+	}
+	if c.GRPCKeepaliveTimeout < 0 {
+		return errors.New("grpc_keepalive_timeout cannot be negative")
+	}
+
+	if c.StreamIdleTimeout != "" {
+		idleTimeout, err := time.ParseDuration(c.StreamIdleTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid stream_idle_timeout %q: %w", c.StreamIdleTimeout, err)
+		}
+		if idleTimeout < 0 {
+			return fmt.Errorf("invalid stream_idle_timeout %q: must not be negative", c.StreamIdleTimeout)
+		}
+	}
 
 	if c.StreamEnableTLS {
 		if c.StreamTLSCert == "" {
@@ -921,6 +1172,11 @@ func (c *APIConfig) Validate(onExecution bool) error {
 		if c.StreamTLSKey == "" {
 			return errors.New("stream TLS key path is empty")
 		}
+		if c.StreamRequireClientCert && c.StreamTLSCA == "" {
+			return errors.New("stream TLS CA path is empty, but client certificates are required")
+		}
+	} else if c.StreamRequireClientCert {
+		return errors.New("stream client certificates are required, but stream TLS is disabled")
 	}
 
 	if onExecution {
@@ -956,11 +1212,12 @@ func RemoveUnusedSocket(path string) error {
 // execution checks. It returns an `error` on validation failure, otherwise
 // `nil`.
 func (c *RootConfig) Validate(onExecution bool) error {
+	if c.ShutdownTimeout < 0 {
+		return errors.New("shutdown_timeout must not be negative")
+	}
+
 	if onExecution {
-		if !filepath.IsAbs(c.LogDir) {
-			return errors.New("log_dir is not an absolute path")
-		}
-		if err := os.MkdirAll(c.LogDir, 0o700); err != nil {
+		if err := ValidateLogDir(c.LogDir); err != nil {
 			return fmt.Errorf("invalid log_dir: %w", err)
 		}
 	}
@@ -968,6 +1225,111 @@ func (c *RootConfig) Validate(onExecution bool) error {
 	return nil
 }
 
+const (
+	// logDirMinAvailableBytes is the space which has to be left on the
+	// filesystem of a log directory to create new log files.
+	logDirMinAvailableBytes = 1024 * 1024
+	// logDirMinAvailableInodes is the number of inodes which have to be left
+	// on the filesystem of a log directory to create new log files.
+	logDirMinAvailableInodes = 16
+)
+
+// logDirFilesystem describes the filesystem holding a log directory.
+type logDirFilesystem struct {
+	writable        bool
+	availableBytes  uint64
+	availableInodes uint64
+}
+
+// statLogDir returns the filesystem holding a log directory. It can be
+// replaced by tests.
+var statLogDir = statLogDirFilesystem
+
+// ValidateLogDir ensures that dir is an absolute path to a writable log
+// directory with enough space and inodes left to create log files in it. The
+// directory is created if it does not exist.
+func ValidateLogDir(dir string) error {
+	if !filepath.IsAbs(dir) {
+		return fmt.Errorf("log directory %s is not an absolute path", dir)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+
+	fs, err := statLogDir(dir)
+	if errors.Is(err, errdefs.ErrNotImplemented) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("stat log directory %s: %w", dir, err)
+	}
+	if !fs.writable {
+		return fmt.Errorf("log directory %s is not writable", dir)
+	}
+	if fs.availableBytes < logDirMinAvailableBytes {
+		return fmt.Errorf("log directory %s has only %d bytes available, need at least %d", dir, fs.availableBytes, logDirMinAvailableBytes)
+	}
+	if fs.availableInodes < logDirMinAvailableInodes {
+		return fmt.Errorf("log directory %s has only %d inodes available, need at least %d", dir, fs.availableInodes, logDirMinAvailableInodes)
+	}
+	return nil
+}
+
+// DefaultShmSizeBytes returns the configured default shm size in bytes, or 0
+// if none is configured.
+func (c *RuntimeConfig) DefaultShmSizeBytes() (int64, error) {
+	if c.DefaultShmSize == "" {
+		return 0, nil
+	}
+	quantity, err := resource.ParseQuantity(c.DefaultShmSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid default_shm_size %q: %w", c.DefaultShmSize, err)
+	}
+	if quantity.Value() <= 0 {
+		return 0, fmt.Errorf("default_shm_size %q must be greater than 0", c.DefaultShmSize)
+	}
+	return quantity.Value(), nil
+}
+
+// umaskRegexp matches the octal umask strings accepted in the configuration
+// and the umask annotation.
+var umaskRegexp = regexp.MustCompile(`^[0-7]{1,4}$`)
+
+// ParseUmask parses an octal umask string like "0022".
+func ParseUmask(umask string) (uint32, error) {
+	if !umaskRegexp.MatchString(umask) {
+		return 0, fmt.Errorf("invalid umask string %s", umask)
+	}
+	value, err := strconv.ParseUint(umask, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(value), nil
+}
+
+// DefaultUmaskValue returns the configured default umask, or nil if none is
+// configured.
+func (c *RuntimeConfig) DefaultUmaskValue() (*uint32, error) {
+	if c.DefaultUmask == "" {
+		return nil, nil
+	}
+	umask, err := ParseUmask(c.DefaultUmask)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default_umask: %w", err)
+	}
+	return &umask, nil
+}
+
+// Naming returns the configured strategy generating the names of pod
+// sandboxes and containers.
+func (c *RuntimeConfig) Naming() (naming.Strategy, error) {
+	strategy, err := naming.Get(c.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming_strategy: %w", err)
+	}
+	return strategy, nil
+}
+
 func (c *RootConfig) CleanShutdownSupportedFileName() string {
 	return c.CleanShutdownFile + ".supported"
 }
@@ -996,10 +1358,64 @@ func (c *RuntimeConfig) Validate(onExecution bool) error {
 		}
 	}
 
+	if c.SeccompProfileDir != "" && !filepath.IsAbs(c.SeccompProfileDir) {
+		return fmt.Errorf("seccomp_profile_dir %q must be an absolute path", c.SeccompProfileDir)
+	}
+
+	if c.MaxPods < 0 {
+		return fmt.Errorf("max_pods %d must not be negative", c.MaxPods)
+	}
+
+	if c.MaxContainers < 0 {
+		return fmt.Errorf("max_containers %d must not be negative", c.MaxContainers)
+	}
+
 	if c.LogSizeMax >= 0 && c.LogSizeMax < OCIBufSize {
 		return fmt.Errorf("log size max should be negative or >= %d", OCIBufSize)
 	}
 
+	if _, err := c.DefaultShmSizeBytes(); err != nil {
+		return err
+	}
+
+	if _, err := c.DefaultUmaskValue(); err != nil {
+		return err
+	}
+
+	if _, err := c.Naming(); err != nil {
+		return err
+	}
+
+	if c.AuditWebhookURL != "" {
+		u, err := url.Parse(c.AuditWebhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid audit_webhook_url: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("audit_webhook_url %q must be an http or https URL", c.AuditWebhookURL)
+		}
+	}
+
+	if c.AuditWebhookTimeout <= 0 {
+		return errors.New("audit_webhook_timeout must be positive")
+	}
+
+	if c.RelabelWorkers < 0 {
+		return errors.New("relabel_workers must not be negative")
+	}
+
+	for _, path := range c.DeniedMountSources {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("denied_mount_sources entry %q is not an absolute path", path)
+		}
+	}
+
+	for _, path := range c.AllowedMountSources {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("allowed_mount_sources entry %q is not an absolute path", path)
+		}
+	}
+
 	// We need to ensure the container termination will be properly waited
 	// for by defining a minimal timeout value. This will prevent timeout
 	// value defined in the configuration file to be too low.
@@ -1008,6 +1424,10 @@ func (c *RuntimeConfig) Validate(onExecution bool) error {
 		logrus.Warnf("Forcing ctr_stop_timeout to lowest possible value of %ds", c.CtrStopTimeout)
 	}
 
+	if _, err := signal.ParseSignal(c.DefaultStopSignal); err != nil {
+		return fmt.Errorf("invalid default_stop_signal %q: %w", c.DefaultStopSignal, err)
+	}
+
 	if _, err := c.Sysctls(); err != nil {
 		return fmt.Errorf("invalid default_sysctls: %w", err)
 	}
@@ -1016,6 +1436,11 @@ func (c *RuntimeConfig) Validate(onExecution bool) error {
 		return fmt.Errorf("invalid capabilities: %w", err)
 	}
 
+	if c.InfraCtrOOMScoreAdj < minOOMScoreAdj || c.InfraCtrOOMScoreAdj > maxOOMScoreAdj {
+		return fmt.Errorf("infra_ctr_oom_score_adj %d must be between %d and %d",
+			c.InfraCtrOOMScoreAdj, minOOMScoreAdj, maxOOMScoreAdj)
+	}
+
 	if c.InfraCtrCPUSet != "" {
 		set, err := cpuset.Parse(c.InfraCtrCPUSet)
 		if err != nil {
@@ -1093,6 +1518,13 @@ func (c *RuntimeConfig) Validate(onExecution bool) error {
 			}
 		}
 
+		if c.SeccompProfileDir != "" {
+			if err := utils.IsDirectory(c.SeccompProfileDir); err != nil {
+				return fmt.Errorf("invalid seccomp_profile_dir: %w", err)
+			}
+		}
+		c.seccompConfig.SetProfileDir(c.SeccompProfileDir)
+
 		if err := c.apparmorConfig.LoadProfile(c.ApparmorProfile); err != nil {
 			return fmt.Errorf("unable to load AppArmor profile: %w", err)
 		}
@@ -1386,6 +1818,14 @@ func (c *ImageConfig) ParsePauseImage() bundle.BundleName {
 // execution checks. It returns an `error` on validation failure, otherwise
 // `nil`.
 func (c *NetworkConfig) Validate(onExecution bool) error {
+	if c.SetupTimeout < 0 {
+		return errors.New("setup_timeout must not be negative")
+	}
+
+	if c.PluginWaitTimeout < 0 {
+		return errors.New("plugin_wait_timeout must not be negative")
+	}
+
 	if onExecution {
 		err := utils.IsDirectory(c.NetworkDir)
 		if err != nil {
@@ -1448,6 +1888,15 @@ func (r *RuntimeHandler) Validate(name string) error {
 	if err := r.ValidateContainerMinMemory(name); err != nil {
 		logrus.Errorf("Unable to set minimum container memory for runtime handler %q: %v", name, err)
 	}
+	if err := r.ValidateDefaultEnv(name); err != nil {
+		return err
+	}
+	if err := r.ValidateAbsentMountSourcesToReject(name); err != nil {
+		return err
+	}
+	if err := r.ValidateAllowedUnsafeSysctls(name); err != nil {
+		return err
+	}
 
 	return r.ValidateNoSyncLog()
 }
@@ -1529,6 +1978,40 @@ func (r *RuntimeHandler) ValidateRuntimeAllowedAnnotations() error {
 	return nil
 }
 
+// ValidateDefaultEnv checks that every `DefaultEnv` entry is of the form
+// KEY=VALUE with a non empty key.
+func (r *RuntimeHandler) ValidateDefaultEnv(name string) error {
+	for _, env := range r.DefaultEnv {
+		if key, _, ok := strings.Cut(env, "="); !ok || key == "" {
+			return fmt.Errorf("invalid default_env entry %q for runtime '%s': expected KEY=VALUE", env, name)
+		}
+	}
+	return nil
+}
+
+// ValidateAbsentMountSourcesToReject checks that every
+// `AbsentMountSourcesToReject` entry is an absolute and clean path, because
+// the entries are compared to cleaned mount sources.
+func (r *RuntimeHandler) ValidateAbsentMountSourcesToReject(name string) error {
+	for _, path := range r.AbsentMountSourcesToReject {
+		if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+			return fmt.Errorf("invalid absent_mount_sources_to_reject entry %q for runtime '%s': expected an absolute and clean path", path, name)
+		}
+	}
+	return nil
+}
+
+// ValidateAllowedUnsafeSysctls checks that every `AllowedUnsafeSysctls` entry
+// is a sysctl name, optionally ending with a "*" wildcard.
+func (r *RuntimeHandler) ValidateAllowedUnsafeSysctls(name string) error {
+	for _, pattern := range r.AllowedUnsafeSysctls {
+		if err := validateSysctlPattern(pattern); err != nil {
+			return fmt.Errorf("invalid allowed_unsafe_sysctls entry %q for runtime '%s': %w", pattern, name, err)
+		}
+	}
+	return nil
+}
+
 // ValidateNoSyncLog checks if the `NoSyncLog` is used with the correct `RuntimeType` ('oci').
 func (r *RuntimeHandler) ValidateNoSyncLog() error {
 	if !r.NoSyncLog {
@@ -1565,6 +2048,7 @@ func (r *RuntimeHandler) ValidateContainerMinMemory(name string) error {
 // sub-command output, where said output contains a JSON document called "Features
 // Structure" that describes the runtime handler's supported features.
 func (r *RuntimeHandler) LoadRuntimeFeatures(input []byte) error {
+	r.features.Loaded = false
 	if err := json.Unmarshal(input, &r.features); err != nil {
 		return fmt.Errorf("unable to unmarshal features structure: %w", err)
 	}
@@ -1580,10 +2064,17 @@ func (r *RuntimeHandler) LoadRuntimeFeatures(input []byte) error {
 	if r.features.OCIVersionMin == "" || r.features.OCIVersionMax == "" {
 		return errors.New("runtime features structure is not valid")
 	}
+	r.features.Loaded = true
 
 	return nil
 }
 
+// RuntimeFeaturesLoaded returns whether the output of the "runtime features"
+// command of this runtime has been loaded successfully.
+func (r *RuntimeHandler) RuntimeFeaturesLoaded() bool {
+	return r.features.Loaded
+}
+
 // RuntimeSupportsIDMap returns whether this runtime supports the "runtime features"
 // command, and that the output of that command advertises IDMap mounts as an option.
 func (r *RuntimeHandler) RuntimeSupportsIDMap() bool {
@@ -1646,6 +2137,11 @@ func (c *NetworkConfig) CNIPluginAddWatcher() chan bool {
 	return c.cniManager.AddWatcher()
 }
 
+// CNIPluginRemoveWatcher removes a watcher added by CNIPluginAddWatcher.
+func (c *NetworkConfig) CNIPluginRemoveWatcher(watcher chan bool) {
+	c.cniManager.RemoveWatcher(watcher)
+}
+
 // CNIPluginGC calls the plugin's GC to clean up any resources concerned with
 // stale pods (pod other than the ones provided by validPodList). The call to
 // the plugin will be deferred until it is ready logging any errors then and
@@ -51,3 +51,7 @@ func checkKernelRROMountSupport() error {
 func (c *RuntimeConfig) ValidatePinnsPath(executable string) error {
 	return nil
 }
+
+func statLogDirFilesystem(string) (*logDirFilesystem, error) {
+	return nil, errdefs.ErrNotImplemented
+}
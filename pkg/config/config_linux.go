@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"sync"
 
@@ -141,3 +142,21 @@ func validateKernelRROMount() error {
 
 	return nil
 }
+
+// statLogDirFilesystem returns the filesystem holding a log directory.
+func statLogDirFilesystem(dir string) (*logDirFilesystem, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return nil, err
+	}
+	fs := &logDirFilesystem{
+		writable:        st.Flags&unix.ST_RDONLY == 0 && unix.Access(dir, unix.W_OK) == nil,
+		availableBytes:  st.Bavail * uint64(st.Bsize),
+		availableInodes: st.Ffree,
+	}
+	// Filesystems allocating inodes dynamically report no inodes at all.
+	if st.Files == 0 {
+		fs.availableInodes = math.MaxUint64
+	}
+	return fs, nil
+}
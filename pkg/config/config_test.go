@@ -6,10 +6,12 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/L-F-Z/cri-t/internal/config/nri"
 	crioann "github.com/L-F-Z/cri-t/pkg/annotations"
 	"github.com/L-F-Z/cri-t/pkg/config"
 	"github.com/L-F-Z/cri-t/utils/cmdrunner"
@@ -128,6 +130,77 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should derive the image volumes dir from the container exits dir", func() {
+			// Given
+			sut.ImageVolumesDir = ""
+			sut.ContainerExitsDir = "/run/custom/exits"
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.ImageVolumesDir).To(Equal("/run/custom/image-volumes"))
+		})
+
+		It("should keep a configured image volumes dir", func() {
+			// Given
+			sut.ImageVolumesDir = "/data/image-volumes"
+			sut.ContainerExitsDir = "/run/custom/exits"
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.ImageVolumesDir).To(Equal("/data/image-volumes"))
+		})
+
+		It("should fail on relative image volumes dir", func() {
+			// Given
+			sut.ImageVolumesDir = "image-volumes"
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on negative image quarantine duration", func() {
+			// Given
+			sut.ImageQuarantineDuration = -time.Second
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on unrecognized image mount lowerdir order", func() {
+			// Given
+			sut.ImageMountLowerdirOrder = "invalid"
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on read-only ignored image volumes", func() {
+			// Given
+			sut.ImageVolumes = config.ImageVolumesIgnore
+			sut.ImageVolumesReadOnly = true
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should fail on wrong default ulimits", func() {
 			// Given
 			sut.DefaultUlimits = []string{"invalid=-1:-1"}
@@ -138,114 +211,516 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
-	})
 
-	t.Describe("ValidateAPIConfig", func() {
-		It("should succeed with negative GRPCMaxSendMsgSize", func() {
+		It("should fail on negative NRI call timeout", func() {
+			// Given
+			sut.NRI.CallTimeout = -time.Second
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on unknown NRI failure policy", func() {
+			// Given
+			sut.NRI.CreateContainerFailurePolicy = "retry"
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed with ignore NRI failure policies", func() {
+			// Given
+			sut.NRI.RunPodSandboxFailurePolicy = nri.FailurePolicyIgnore
+			sut.NRI.CreateContainerFailurePolicy = nri.FailurePolicyIgnore
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	t.Describe("ValidateAPIConfig", func() {
+		It("should succeed with negative GRPCMaxSendMsgSize", func() {
+			// Given
+			sut.GRPCMaxSendMsgSize = -100
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed with negative GRPCMaxRecvMsgSize", func() {
+			// Given
+			sut.GRPCMaxRecvMsgSize = -100
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed with grpc max concurrent streams and keepalive", func() {
+			// Given
+			sut.GRPCMaxConcurrentStreams = 100
+			sut.GRPCKeepaliveTime = time.Minute
+			sut.GRPCKeepaliveTimeout = 10 * time.Second
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with negative GRPCMaxConcurrentStreams", func() {
+			// Given
+			sut.GRPCMaxConcurrentStreams = -1
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with negative GRPCKeepaliveTime", func() {
+			// Given
+			sut.GRPCKeepaliveTime = -time.Second
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with negative GRPCKeepaliveTimeout", func() {
+			// Given
+			sut.GRPCKeepaliveTimeout = -time.Second
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on invalid Listen directory", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.Listen = "/proc/dir/crio.sock"
+
+			// When
+			err := sut.APIConfig.Validate(true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if socket removal fails", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.Listen = "/proc"
+
+			// When
+			err := sut.APIConfig.Validate(true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed with default stream idle timeout", func() {
+			// Given
+			sut = runtimeValidConfig()
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.StreamIdleTimeout).To(Equal("4h"))
+		})
+
+		It("should succeed with disabled stream idle timeout", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamIdleTimeout = "0"
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail with invalid stream idle timeout", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamIdleTimeout = "forever"
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with negative stream idle timeout", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamIdleTimeout = "-1m"
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed if stream server TLS enabled", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamEnableTLS = true
+			sut.StreamTLSCert = "cert"
+			sut.StreamTLSKey = "key"
+			sut.StreamTLSCA = "ca"
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if stream server TLS enabled and cert is empty", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamEnableTLS = true
+			sut.StreamTLSCert = ""
+			sut.StreamTLSKey = "key"
+			sut.StreamTLSCA = "ca"
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed if stream server TLS enabled and client certs are required", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamEnableTLS = true
+			sut.StreamTLSCert = "cert"
+			sut.StreamTLSKey = "key"
+			sut.StreamTLSCA = "ca"
+			sut.StreamRequireClientCert = true
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if stream server TLS enabled, client certs are required and CA is empty", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamEnableTLS = true
+			sut.StreamTLSCert = "cert"
+			sut.StreamTLSKey = "key"
+			sut.StreamTLSCA = ""
+			sut.StreamRequireClientCert = true
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if client certs are required and stream server TLS disabled", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamEnableTLS = false
+			sut.StreamTLSCA = "ca"
+			sut.StreamRequireClientCert = true
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if stream server TLS enabled and key is empty", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.StreamEnableTLS = true
+			sut.StreamTLSCert = "cert"
+			sut.StreamTLSKey = ""
+			sut.StreamTLSCA = "ca"
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("ValidateRuntimeConfig", func() {
+		It("should succeed with default config", func() {
+			// Given
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed during runtime", func() {
+			// Given
+			sut = runtimeValidConfig()
+
+			// When
+			err := sut.RuntimeConfig.Validate(true)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail on unknown naming strategy", func() {
+			// Given
+			sut.NamingStrategy = "invalid"
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on audit webhook URL without http scheme", func() {
+			// Given
+			sut.AuditWebhookURL = "ftp://localhost/audit"
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on negative audit webhook timeout", func() {
+			// Given
+			sut.AuditWebhookTimeout = -time.Second
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on zero audit webhook timeout", func() {
+			// Given
+			sut.AuditWebhookTimeout = 0
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed with a custom default shm size", func() {
+			// Given
+			sut.DefaultShmSize = "1Gi"
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.DefaultShmSizeBytes()).To(BeEquivalentTo(1024 * 1024 * 1024))
+		})
+
+		It("should succeed with an infra container OOM score adjustment", func() {
+			// Given
+			sut.InfraCtrOOMScoreAdj = -1000
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with an out of range infra container OOM score adjustment", func() {
+			// Given
+			sut.InfraCtrOOMScoreAdj = 1001
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with negative max pods", func() {
+			// Given
+			sut.MaxPods = -1
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with negative max containers", func() {
+			// Given
+			sut.MaxContainers = -1
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed with a numeric default stop signal", func() {
 			// Given
-			sut.GRPCMaxSendMsgSize = -100
+			sut.DefaultStopSignal = "9"
 
 			// When
-			err := sut.APIConfig.Validate(false)
+			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
 			Expect(err).ToNot(HaveOccurred())
 		})
 
-		It("should succeed with negative GRPCMaxRecvMsgSize", func() {
+		It("should fail with invalid default stop signal", func() {
 			// Given
-			sut.GRPCMaxRecvMsgSize = -100
+			sut.DefaultStopSignal = "SIGINVALID"
 
 			// When
-			err := sut.APIConfig.Validate(false)
+			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
-			Expect(err).ToNot(HaveOccurred())
+			Expect(err).To(HaveOccurred())
 		})
 
-		It("should fail on invalid Listen directory", func() {
+		It("should fail with empty default stop signal", func() {
 			// Given
-			sut = runtimeValidConfig()
-			sut.Listen = "/proc/dir/crio.sock"
+			sut.DefaultStopSignal = ""
 
 			// When
-			err := sut.APIConfig.Validate(true)
+			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
 
-		It("should fail if socket removal fails", func() {
+		It("should succeed with absolute mount source lists", func() {
 			// Given
-			sut = runtimeValidConfig()
-			sut.Listen = "/proc"
+			sut.DeniedMountSources = []string{"/etc/kubernetes", "/var/run/secrets"}
+			sut.AllowedMountSources = []string{"/var/lib/kubelet"}
 
 			// When
-			err := sut.APIConfig.Validate(true)
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with relative denied mount sources", func() {
+			// Given
+			sut.DeniedMountSources = []string{"etc/kubernetes"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
 
-		It("should succeed if stream server TLS enabled", func() {
+		It("should fail with relative allowed mount sources", func() {
 			// Given
-			sut = runtimeValidConfig()
-			sut.StreamEnableTLS = true
-			sut.StreamTLSCert = "cert"
-			sut.StreamTLSKey = "key"
-			sut.StreamTLSCA = "ca"
+			sut.AllowedMountSources = []string{"var/lib/kubelet"}
 
 			// When
-			err := sut.APIConfig.Validate(false)
+			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
-			Expect(err).NotTo(HaveOccurred())
+			Expect(err).To(HaveOccurred())
 		})
 
-		It("should fail if stream server TLS enabled and cert is empty", func() {
+		It("should fail with invalid default shm size", func() {
 			// Given
-			sut = runtimeValidConfig()
-			sut.StreamEnableTLS = true
-			sut.StreamTLSCert = ""
-			sut.StreamTLSKey = "key"
-			sut.StreamTLSCA = "ca"
+			sut.DefaultShmSize = "invalid"
 
 			// When
-			err := sut.APIConfig.Validate(false)
+			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
 
-		It("should fail if stream server TLS enabled and key is empty", func() {
+		It("should fail with non positive default shm size", func() {
 			// Given
-			sut = runtimeValidConfig()
-			sut.StreamEnableTLS = true
-			sut.StreamTLSCert = "cert"
-			sut.StreamTLSKey = ""
-			sut.StreamTLSCA = "ca"
+			sut.DefaultShmSize = "0"
 
 			// When
-			err := sut.APIConfig.Validate(false)
+			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
-	})
 
-	t.Describe("ValidateRuntimeConfig", func() {
-		It("should succeed with default config", func() {
+		It("should succeed with a default umask", func() {
 			// Given
+			sut.DefaultUmask = "0027"
+
 			// When
 			err := sut.RuntimeConfig.Validate(false)
 
 			// Then
 			Expect(err).ToNot(HaveOccurred())
+			umask, err := sut.DefaultUmaskValue()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*umask).To(BeEquivalentTo(0o027))
 		})
 
-		It("should succeed during runtime", func() {
+		It("should fail with invalid default umask", func() {
+			for _, umask := range []string{"0o22", "0089", "12345", "abc"} {
+				// Given
+				sut.DefaultUmask = umask
+
+				// When
+				err := sut.RuntimeConfig.Validate(false)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should succeed with additional devices", func() {
 			// Given
 			sut = runtimeValidConfig()
+			sut.AdditionalDevices = []string{"/dev/null:/dev/null:rw"}
 
 			// When
 			err := sut.RuntimeConfig.Validate(true)
@@ -254,10 +729,10 @@ var _ = t.Describe("Config", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
-		It("should succeed with additional devices", func() {
+		It("should succeed with a seccomp profile directory", func() {
 			// Given
 			sut = runtimeValidConfig()
-			sut.AdditionalDevices = []string{"/dev/null:/dev/null:rw"}
+			sut.SeccompProfileDir = validDirPath
 
 			// When
 			err := sut.RuntimeConfig.Validate(true)
@@ -266,6 +741,29 @@ var _ = t.Describe("Config", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should fail with a seccomp profile directory which is a file", func() {
+			// Given
+			sut = runtimeValidConfig()
+			sut.SeccompProfileDir = validFilePath
+
+			// When
+			err := sut.RuntimeConfig.Validate(true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with a relative seccomp profile directory", func() {
+			// Given
+			sut.SeccompProfileDir = "profiles"
+
+			// When
+			err := sut.RuntimeConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should succeed with hooks directories", func() {
 			// Given
 			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
@@ -762,6 +1260,99 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError("no_sync_log is only allowed with runtime type 'oci', runtime type is 'vm'"))
 		})
+
+		It("should succeed with valid default_env", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+				DefaultEnv:  []string{"NVIDIA_VISIBLE_DEVICES=all", "EMPTY="},
+			}
+
+			// When
+			err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with default_env entries without a key", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+			}
+
+			for _, env := range []string{"NO_VALUE", "=value", ""} {
+				sut.Runtimes[config.DefaultRuntime].DefaultEnv = []string{env}
+
+				// When
+				err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should succeed with valid absent_mount_sources_to_reject", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath:                validFilePath,
+				AbsentMountSourcesToReject: []string{"/etc/hostname", "/var/lib/kubelet"},
+			}
+
+			// When
+			err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed with valid allowed_unsafe_sysctls", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath:          validFilePath,
+				AllowedUnsafeSysctls: []string{"net.core.somaxconn", "kernel.msg*", "net.*", "fs.mqueue.*"},
+			}
+
+			// When
+			err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with invalid allowed_unsafe_sysctls", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+			}
+
+			for _, pattern := range []string{"", "*", "net.*.somaxconn", "net.core.somaxconn=1", "net core", "vm.*", "fs.file-max"} {
+				sut.Runtimes[config.DefaultRuntime].AllowedUnsafeSysctls = []string{pattern}
+
+				// When
+				err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should fail with relative or unclean absent_mount_sources_to_reject", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+			}
+
+			for _, path := range []string{"etc/hostname", "/etc/hostname/", "/etc/../etc/hostname", ""} {
+				sut.Runtimes[config.DefaultRuntime].AbsentMountSourcesToReject = []string{path}
+
+				// When
+				err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			}
+		})
 	})
 
 	t.Describe("ValidateConmonPath", func() {
@@ -822,6 +1413,28 @@ var _ = t.Describe("Config", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should fail with negative setup timeout", func() {
+			// Given
+			sut.NetworkConfig.SetupTimeout = -time.Second
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with negative plugin wait timeout", func() {
+			// Given
+			sut.NetworkConfig.PluginWaitTimeout = -time.Second
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should succeed during runtime", func() {
 			// Given
 			sut = runtimeValidConfig()
@@ -954,6 +1567,45 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should fail on read-only LogDir", func() {
+			// Given
+			sut.RootConfig.LogDir = t.MustTempDir("log")
+			defer config.SetLogDirFilesystem(false, 1<<30, 1<<20)()
+
+			// When
+			err := sut.RootConfig.Validate(true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not writable"))
+		})
+
+		It("should fail on LogDir without space left", func() {
+			// Given
+			sut.RootConfig.LogDir = t.MustTempDir("log")
+			defer config.SetLogDirFilesystem(true, 4096, 1<<20)()
+
+			// When
+			err := sut.RootConfig.Validate(true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("bytes available"))
+		})
+
+		It("should fail on LogDir without inodes left", func() {
+			// Given
+			sut.RootConfig.LogDir = t.MustTempDir("log")
+			defer config.SetLogDirFilesystem(true, 1<<30, 0)()
+
+			// When
+			err := sut.RootConfig.Validate(true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("inodes available"))
+		})
+
 		It("should fail with non absolute log_dir", func() {
 			// Given
 			sut.RootConfig.LogDir = "test"
@@ -1089,6 +1741,63 @@ var _ = t.Describe("Config", func() {
 			Expect(sut.Runtimes).To(HaveKey("foo"))
 		})
 
+		It("should succeed with runtime default_env", func() {
+			// Given
+			f := t.MustTempFile("config")
+			Expect(os.WriteFile(f,
+				[]byte(`
+					[crio.runtime.runtimes.foo]
+					default_env = ["FOO=bar", "BAZ=qux"]
+				`), 0),
+			).To(Succeed())
+
+			// When
+			err := sut.UpdateFromFile(context.Background(), f)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.Runtimes).To(HaveKey("foo"))
+			Expect(sut.Runtimes["foo"].DefaultEnv).To(Equal([]string{"FOO=bar", "BAZ=qux"}))
+		})
+
+		It("should succeed with runtime absent_mount_sources_to_reject", func() {
+			// Given
+			f := t.MustTempFile("config")
+			Expect(os.WriteFile(f,
+				[]byte(`
+					[crio.runtime.runtimes.foo]
+					absent_mount_sources_to_reject = ["/etc/hostname"]
+				`), 0),
+			).To(Succeed())
+
+			// When
+			err := sut.UpdateFromFile(context.Background(), f)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.Runtimes).To(HaveKey("foo"))
+			Expect(sut.Runtimes["foo"].AbsentMountSourcesToReject).To(Equal([]string{"/etc/hostname"}))
+		})
+
+		It("should succeed with runtime allowed_unsafe_sysctls", func() {
+			// Given
+			f := t.MustTempFile("config")
+			Expect(os.WriteFile(f,
+				[]byte(`
+					[crio.runtime.runtimes.foo]
+					allowed_unsafe_sysctls = ["net.*"]
+				`), 0),
+			).To(Succeed())
+
+			// When
+			err := sut.UpdateFromFile(context.Background(), f)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.Runtimes).To(HaveKey("foo"))
+			Expect(sut.Runtimes["foo"].AllowedUnsafeSysctls).To(Equal([]string{"net.*"}))
+		})
+
 		It("should succeed with additional runtime", func() {
 			// Given
 			f := t.MustTempFile("config")
@@ -1370,6 +2079,7 @@ var _ = t.Describe("Config", func() {
 
 			// Then
 			Expect(err).To(HaveOccurred())
+			Expect(handler.RuntimeFeaturesLoaded()).To(BeFalse())
 		})
 
 		It("should fail to load OCI runtime features when malformed document is used", func() {
@@ -1436,6 +2146,7 @@ var _ = t.Describe("Config", func() {
 
 			// Then
 			Expect(err).ToNot(HaveOccurred())
+			Expect(handler.RuntimeFeaturesLoaded()).To(BeTrue())
 
 			// When
 			ok := handler.RuntimeSupportsIDMap()
@@ -25,3 +25,18 @@ func (c *Config) SetCNIPlugin(plugin ocicni.CNIPlugin) error {
 func (c *Config) SetNamespaceManager(nsMgr *nsmgr.NamespaceManager) {
 	c.namespaceManager = nsMgr
 }
+
+// SetLogDirFilesystem replaces the filesystem statistics of log directories
+// and returns a function restoring them.
+func SetLogDirFilesystem(writable bool, availableBytes, availableInodes uint64) (restore func()) {
+	statLogDir = func(string) (*logDirFilesystem, error) {
+		return &logDirFilesystem{
+			writable:        writable,
+			availableBytes:  availableBytes,
+			availableInodes: availableInodes,
+		}, nil
+	}
+	return func() {
+		statLogDir = statLogDirFilesystem
+	}
+}
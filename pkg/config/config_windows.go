@@ -26,3 +26,7 @@ const (
 func checkKernelRROMountSupport() error {
 	return errdefs.ErrNotImplemented
 }
+
+func statLogDirFilesystem(string) (*logDirFilesystem, error) {
+	return nil, errdefs.ErrNotImplemented
+}
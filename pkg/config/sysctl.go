@@ -1,10 +1,21 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+var (
+	// ErrSysctlNotWhitelisted is returned by Validate for sysctls which are
+	// not known to be namespaced.
+	ErrSysctlNotWhitelisted = errors.New("not whitelisted")
+
+	// ErrSysctlNotNamespaced is returned by Validate for sysctls which are
+	// known not to be namespaced. They cannot be allowed.
+	ErrSysctlNotNamespaced = errors.New("not namespaced")
+)
+
 func NewSysctl(key, value string) *Sysctl {
 	return &Sysctl{key, value}
 }
@@ -58,6 +69,9 @@ const (
 
 	// NetNamespace is the network namespace.
 	NetNamespace = Namespace("net")
+
+	// UtsNamespace is the UTS namespace.
+	UtsNamespace = Namespace("uts")
 )
 
 var namespaces = map[string]Namespace{
@@ -71,6 +85,39 @@ var prefixNamespaces = map[string]Namespace{
 	"net.":       NetNamespace,
 }
 
+// utsSysctls are the sysctls of the UTS namespace. They are not whitelisted,
+// but can be allowed per runtime handler.
+var utsSysctls = map[string]bool{
+	"kernel.hostname":   true,
+	"kernel.domainname": true,
+}
+
+// nonNamespacedPrefixes are the prefixes of sysctls which are known to affect
+// the whole host, unless they match one of prefixNamespaces.
+var nonNamespacedPrefixes = []string{
+	"abi.",
+	"crypto.",
+	"debug.",
+	"dev.",
+	"fs.",
+	"vm.",
+}
+
+// knownNotNamespaced returns true if key is known to affect the whole host.
+func knownNotNamespaced(key string) bool {
+	for p := range prefixNamespaces {
+		if strings.HasPrefix(key, p) {
+			return false
+		}
+	}
+	for _, p := range nonNamespacedPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate checks that a sysctl is whitelisted because it is known to be
 // namespaced by the Linux kernel. The parameters hostNet and hostIPC are used
 // to forbid sysctls for pod sharing the respective namespaces with the host.
@@ -95,5 +142,55 @@ func (s *Sysctl) Validate(hostNet, hostIPC bool) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("%s not whitelisted", s.Key())
+	if knownNotNamespaced(s.Key()) {
+		return fmt.Errorf("%s %w", s.Key(), ErrSysctlNotNamespaced)
+	}
+	return fmt.Errorf("%s %w", s.Key(), ErrSysctlNotWhitelisted)
+}
+
+// ValidateUnsafe checks a sysctl for which Validate returned
+// ErrSysctlNotWhitelisted against the patterns of allowed_unsafe_sysctls. The
+// parameter hostUTS is used to forbid the sysctls of the UTS namespace for
+// pods sharing it with the host.
+func (s *Sysctl) ValidateUnsafe(patterns []string, hostUTS bool) error {
+	if !s.Allowed(patterns) {
+		return fmt.Errorf("%s %w", s.Key(), ErrSysctlNotWhitelisted)
+	}
+	if utsSysctls[s.Key()] && hostUTS {
+		return fmt.Errorf("%q not allowed with host %s enabled", s.Key(), UtsNamespace)
+	}
+	return nil
+}
+
+// Allowed returns true if the key of the sysctl matches one of the patterns.
+// A pattern ending with "*" matches all keys starting with the rest of the
+// pattern, other patterns have to match the key exactly.
+func (s *Sysctl) Allowed(patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(s.Key(), prefix) {
+				return true
+			}
+		} else if s.Key() == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSysctlPattern checks that pattern is a sysctl name, which may end
+// with a "*" wildcard. Patterns of sysctls known not to be namespaced are
+// rejected, as they can never be allowed.
+func validateSysctlPattern(pattern string) error {
+	name, _ := strings.CutSuffix(pattern, "*")
+	if name == "" {
+		return errors.New("empty sysctl name")
+	}
+	if strings.ContainsAny(name, "*= \t") {
+		return errors.New("a sysctl name must not contain whitespace or '=' and may only end with '*'")
+	}
+	if knownNotNamespaced(name) {
+		return ErrSysctlNotNamespaced
+	}
+	return nil
 }
@@ -3,6 +3,8 @@ package config_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/L-F-Z/cri-t/pkg/config"
 )
 
 // The actual test suite.
@@ -156,4 +158,86 @@ var _ = t.Describe("Sysctl", func() {
 		// Then
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("should fail to validate not whitelisted sysctl with ErrSysctlNotWhitelisted", func() {
+		// Given
+		sut.DefaultSysctls = []string{"kernel.domainname=example"}
+		sysctls, err := sut.Sysctls()
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		err = sysctls[0].Validate(false, false)
+
+		// Then
+		Expect(err).To(MatchError(config.ErrSysctlNotWhitelisted))
+	})
+
+	It("should allow sysctl matching a pattern", func() {
+		// Given
+		sysctl := config.NewSysctl("net.core.somaxconn", "1024")
+
+		// When
+		// Then
+		Expect(sysctl.Allowed([]string{"net.core.somaxconn"})).To(BeTrue())
+		Expect(sysctl.Allowed([]string{"kernel.msg*", "net.*"})).To(BeTrue())
+		Expect(sysctl.Allowed([]string{"net.core.*"})).To(BeTrue())
+	})
+
+	It("should not allow sysctl not matching a pattern", func() {
+		// Given
+		sysctl := config.NewSysctl("net.core.somaxconn", "1024")
+
+		// When
+		// Then
+		Expect(sysctl.Allowed(nil)).To(BeFalse())
+		Expect(sysctl.Allowed([]string{"net.core"})).To(BeFalse())
+		Expect(sysctl.Allowed([]string{"kernel.*", "net.ipv4.*"})).To(BeFalse())
+	})
+	It("should fail to validate not namespaced sysctl with ErrSysctlNotNamespaced", func() {
+		// Given
+		for _, key := range []string{"vm.swappiness", "fs.file-max", "dev.raid.speed_limit_max"} {
+			sysctl := config.NewSysctl(key, "1")
+
+			// When
+			err := sysctl.Validate(false, false)
+
+			// Then
+			Expect(err).To(MatchError(config.ErrSysctlNotNamespaced))
+		}
+	})
+
+	It("should validate unsafe sysctl matching a pattern", func() {
+		// Given
+		sysctl := config.NewSysctl("kernel.domainname", "example")
+
+		// When
+		err := sysctl.ValidateUnsafe([]string{"kernel.domainname"}, false)
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail to validate unsafe sysctl not matching a pattern", func() {
+		// Given
+		sysctl := config.NewSysctl("kernel.domainname", "example")
+
+		// When
+		err := sysctl.ValidateUnsafe([]string{"kernel.hostname"}, false)
+
+		// Then
+		Expect(err).To(MatchError(config.ErrSysctlNotWhitelisted))
+	})
+
+	It("should fail to validate unsafe uts sysctl with host uts", func() {
+		// Given
+		for _, key := range []string{"kernel.domainname", "kernel.hostname"} {
+			sysctl := config.NewSysctl(key, "example")
+
+			// When
+			err := sysctl.ValidateUnsafe([]string{"kernel.*"}, true)
+
+			// Then
+			Expect(err).To(MatchError(ContainSubstring("not allowed with host uts enabled")))
+		}
+	})
 })
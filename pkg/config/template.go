@@ -146,6 +146,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRootConfig,
 			isDefaultValue: simpleEqual(dc.CleanShutdownFile, c.CleanShutdownFile),
 		},
+		{
+			templateString: templateStringCrioShutdownTimeout,
+			group:          crioRootConfig,
+			isDefaultValue: simpleEqual(dc.ShutdownTimeout, c.ShutdownTimeout),
+		},
 		{
 			templateString: templateStringCrioAPIListen,
 			group:          crioAPIConfig,
@@ -186,6 +191,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioAPIConfig,
 			isDefaultValue: simpleEqual(dc.StreamTLSCA, c.StreamTLSCA),
 		},
+		{
+			templateString: templateStringCrioAPIStreamRequireClientCert,
+			group:          crioAPIConfig,
+			isDefaultValue: simpleEqual(dc.StreamRequireClientCert, c.StreamRequireClientCert),
+		},
 		{
 			templateString: templateStringCrioAPIGrpcMaxSendMsgSize,
 			group:          crioAPIConfig,
@@ -196,6 +206,21 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioAPIConfig,
 			isDefaultValue: simpleEqual(dc.GRPCMaxRecvMsgSize, c.GRPCMaxRecvMsgSize),
 		},
+		{
+			templateString: templateStringCrioAPIGrpcMaxConcurrentStreams,
+			group:          crioAPIConfig,
+			isDefaultValue: simpleEqual(dc.GRPCMaxConcurrentStreams, c.GRPCMaxConcurrentStreams),
+		},
+		{
+			templateString: templateStringCrioAPIGrpcKeepaliveTime,
+			group:          crioAPIConfig,
+			isDefaultValue: simpleEqual(dc.GRPCKeepaliveTime, c.GRPCKeepaliveTime),
+		},
+		{
+			templateString: templateStringCrioAPIGrpcKeepaliveTimeout,
+			group:          crioAPIConfig,
+			isDefaultValue: simpleEqual(dc.GRPCKeepaliveTimeout, c.GRPCKeepaliveTimeout),
+		},
 		{
 			templateString: templateStringCrioRuntimeDefaultUlimits,
 			group:          crioRuntimeConfig,
@@ -226,6 +251,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: slices.Equal(dc.DefaultEnv, c.DefaultEnv),
 		},
+		{
+			templateString: templateStringCrioRuntimeDefaultTerm,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.DefaultTerm, c.DefaultTerm),
+		},
 		{
 			templateString: templateStringCrioRuntimeSelinux,
 			group:          crioRuntimeConfig,
@@ -236,6 +266,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SeccompProfile, c.SeccompProfile),
 		},
+		{
+			templateString: templateStringCrioRuntimeSeccompProfileDir,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.SeccompProfileDir, c.SeccompProfileDir),
+		},
 		{
 			templateString: templateStringCrioRuntimeApparmorProfile,
 			group:          crioRuntimeConfig,
@@ -291,6 +326,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: slices.Equal(dc.DefaultSysctls, c.DefaultSysctls),
 		},
+		{
+			templateString: templateStringCrioRuntimeStrictSysctls,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.StrictSysctls, c.StrictSysctls),
+		},
 		{
 			templateString: templateStringCrioRuntimeAllowedDevices,
 			group:          crioRuntimeConfig,
@@ -326,6 +366,26 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.PidsLimit, c.PidsLimit),
 		},
+		{
+			templateString: templateStringCrioRuntimeMaxPods,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.MaxPods, c.MaxPods),
+		},
+		{
+			templateString: templateStringCrioRuntimeMaxContainers,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.MaxContainers, c.MaxContainers),
+		},
+		{
+			templateString: templateStringCrioRuntimeDefaultShmSize,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.DefaultShmSize, c.DefaultShmSize),
+		},
+		{
+			templateString: templateStringCrioRuntimeDefaultUmask,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.DefaultUmask, c.DefaultUmask),
+		},
 		{
 			templateString: templateStringCrioRuntimeLogSizeMax,
 			group:          crioRuntimeConfig,
@@ -391,6 +451,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.CtrStopTimeout, c.CtrStopTimeout),
 		},
+		{
+			templateString: templateStringCrioRuntimeDefaultStopSignal,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.DefaultStopSignal, c.DefaultStopSignal),
+		},
 		{
 			templateString: templateStringCrioRuntimeDropInfraCtr,
 			group:          crioRuntimeConfig,
@@ -401,6 +466,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.InfraCtrCPUSet, c.InfraCtrCPUSet),
 		},
+		{
+			templateString: templateStringCrioRuntimeInfraCtrOOMScoreAdj,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.InfraCtrOOMScoreAdj, c.InfraCtrOOMScoreAdj),
+		},
 		{
 			templateString: templateStringCrioRuntimeSharedCpuset,
 			group:          crioRuntimeConfig,
@@ -421,6 +491,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.EnablePodEvents, c.EnablePodEvents),
 		},
+		{
+			templateString: templateStringCrioRuntimeEnableCriuSupport,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.EnableCriuSupport, c.EnableCriuSupport),
+		},
 		{
 			templateString: templateStringCrioRuntimeDefaultRuntime,
 			group:          crioRuntimeConfig,
@@ -431,6 +506,26 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: slices.Equal(dc.AbsentMountSourcesToReject, c.AbsentMountSourcesToReject),
 		},
+		{
+			templateString: templateStringCrioRuntimeDeniedMountSources,
+			group:          crioRuntimeConfig,
+			isDefaultValue: slices.Equal(dc.DeniedMountSources, c.DeniedMountSources),
+		},
+		{
+			templateString: templateStringCrioRuntimeAllowedMountSources,
+			group:          crioRuntimeConfig,
+			isDefaultValue: slices.Equal(dc.AllowedMountSources, c.AllowedMountSources),
+		},
+		{
+			templateString: templateStringCrioRuntimeRelabelWorkers,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.RelabelWorkers, c.RelabelWorkers),
+		},
+		{
+			templateString: templateStringCrioRuntimeRROMountsFallbackToReadOnly,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.RROMountsFallbackToReadOnly, c.RROMountsFallbackToReadOnly),
+		},
 		{
 			templateString: templateStringCrioRuntimeRuntimesRuntimeHandler,
 			group:          crioRuntimeConfig,
@@ -456,6 +551,26 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.Timezone, c.Timezone),
 		},
+		{
+			templateString: templateStringCrioRuntimeNamingStrategy,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.NamingStrategy, c.NamingStrategy),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditWebhookURL,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditWebhookURL, c.AuditWebhookURL),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditWebhookTimeout,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditWebhookTimeout, c.AuditWebhookTimeout),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditWebhookFailOpen,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditWebhookFailOpen, c.AuditWebhookFailOpen),
+		},
 		{
 			templateString: templateStringCrioImageDefaultTransport,
 			group:          crioImageConfig,
@@ -481,6 +596,26 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioImageConfig,
 			isDefaultValue: simpleEqual(dc.ImageVolumes, c.ImageVolumes),
 		},
+		{
+			templateString: templateStringCrioImageImageVolumesReadOnly,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageVolumesReadOnly, c.ImageVolumesReadOnly),
+		},
+		{
+			templateString: templateStringCrioImageImageMountLowerdirOrder,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageMountLowerdirOrder, c.ImageMountLowerdirOrder),
+		},
+		{
+			templateString: templateStringCrioImageImageVolumesDir,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageVolumesDir, c.ImageVolumesDir),
+		},
+		{
+			templateString: templateStringCrioImageImageMountsCopyOnWrite,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageMountsCopyOnWrite, c.ImageMountsCopyOnWrite),
+		},
 		{
 			templateString: templateStringCrioImageBigFilesTemporaryDir,
 			group:          crioImageConfig,
@@ -491,6 +626,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioImageConfig,
 			isDefaultValue: simpleEqual(dc.PullProgressTimeout, c.PullProgressTimeout),
 		},
+		{
+			templateString: templateStringCrioImageImageQuarantineDuration,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageQuarantineDuration, c.ImageQuarantineDuration),
+		},
 		{
 			templateString: templateStringCrioNetworkCniDefaultNetwork,
 			group:          crioNetworkConfig,
@@ -506,6 +646,16 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioNetworkConfig,
 			isDefaultValue: slices.Equal(dc.PluginDirs, c.PluginDirs),
 		},
+		{
+			templateString: templateStringCrioNetworkSetupTimeout,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.SetupTimeout, c.SetupTimeout),
+		},
+		{
+			templateString: templateStringCrioNetworkPluginWaitTimeout,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.PluginWaitTimeout, c.PluginWaitTimeout),
+		},
 		{
 			templateString: templateStringCrioMetricsEnableMetrics,
 			group:          crioMetricsConfig,
@@ -606,6 +756,21 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioNRIConfig,
 			isDefaultValue: simpleEqual(dc.NRI.PluginRequestTimeout, c.NRI.PluginRequestTimeout),
 		},
+		{
+			templateString: templateStringCrioNRICallTimeout,
+			group:          crioNRIConfig,
+			isDefaultValue: simpleEqual(dc.NRI.CallTimeout, c.NRI.CallTimeout),
+		},
+		{
+			templateString: templateStringCrioNRIRunPodSandboxFailurePolicy,
+			group:          crioNRIConfig,
+			isDefaultValue: simpleEqual(dc.NRI.RunPodSandboxFailurePolicy, c.NRI.RunPodSandboxFailurePolicy),
+		},
+		{
+			templateString: templateStringCrioNRICreateContainerFailurePolicy,
+			group:          crioNRIConfig,
+			isDefaultValue: simpleEqual(dc.NRI.CreateContainerFailurePolicy, c.NRI.CreateContainerFailurePolicy),
+		},
 	}
 
 	return crioTemplateConfig, nil
@@ -710,6 +875,13 @@ const templateStringCrioCleanShutdownFile = `# Location for CRI-O to lay down th
 
 `
 
+const templateStringCrioShutdownTimeout = `# Maximum time to wait for in-flight container and pod sandbox creations to
+# finish on shutdown. Creations still running afterwards get canceled, a value
+# of 0 cancels them immediately without waiting.
+{{ $.Comment }}shutdown_timeout = "{{ .ShutdownTimeout }}"
+
+`
+
 const templateStringCrioInternalWipe = `# InternalWipe is whether CRI-O should wipe containers and images after a reboot when the server starts.
 # If set to false, one must use the external command 'crio wipe' to wipe the containers and images in these situations.
 {{ $.Comment }}internal_wipe = {{ .InternalWipe }}
@@ -748,7 +920,8 @@ const templateStringCrioAPIStreamEnableTLS = `# Enable encrypted TLS transport o
 
 `
 
-const templateStringCrioAPIStreamIdleTimeout = `# Length of time until open streams terminate due to lack of activity
+const templateStringCrioAPIStreamIdleTimeout = `# Length of time until open streams terminate due to lack of activity.
+# Setting it to "0" keeps idle streams open.
 {{ $.Comment }}stream_idle_timeout = "{{.StreamIdleTimeout}}"
 
 `
@@ -772,6 +945,13 @@ const templateStringCrioAPIStreamTLSCa = `# Path to the x509 CA(s) file used to
 
 `
 
+const templateStringCrioAPIStreamRequireClientCert = `# If true, enforce that clients of the encrypted stream present a certificate
+# signed by the stream_tls_ca, by refusing to start without stream_tls_ca.
+# Requires stream_enable_tls.
+{{ $.Comment }}stream_require_client_cert = {{ .StreamRequireClientCert }}
+
+`
+
 const templateStringCrioAPIGrpcMaxSendMsgSize = `# Maximum grpc send message size in bytes. If not set or <=0, then CRI-O will default to 80 * 1024 * 1024.
 {{ $.Comment }}grpc_max_send_msg_size = {{ .GRPCMaxSendMsgSize }}
 
@@ -782,6 +962,24 @@ const templateStringCrioAPIGrpcMaxRecvMsgSize = `# Maximum grpc receive message
 
 `
 
+const templateStringCrioAPIGrpcMaxConcurrentStreams = `# Maximum number of concurrent grpc streams of each client connection. If set
+# to 0, the streams are not limited.
+{{ $.Comment }}grpc_max_concurrent_streams = {{ .GRPCMaxConcurrentStreams }}
+
+`
+
+const templateStringCrioAPIGrpcKeepaliveTime = `# Duration after which the grpc server pings an idle client connection to check
+# whether it is still alive. If set to 0, the grpc default of 2 hours is used.
+{{ $.Comment }}grpc_keepalive_time = "{{ .GRPCKeepaliveTime }}"
+
+`
+
+const templateStringCrioAPIGrpcKeepaliveTimeout = `# Duration the grpc server waits for the answer to a keepalive ping before it
+# closes the connection. If set to 0, the grpc default of 20 seconds is used.
+{{ $.Comment }}grpc_keepalive_timeout = "{{ .GRPCKeepaliveTimeout }}"
+
+`
+
 const templateStringCrioRuntime = `# The crio.runtime table contains settings pertaining to the OCI runtime used
 # and options for how to set up and manage the OCI runtime.
 [crio.runtime]
@@ -831,6 +1029,13 @@ const templateStringCrioRuntimeDefaultEnv = `# Additional environment variables
 
 `
 
+const templateStringCrioRuntimeDefaultTerm = `# Value of the TERM environment variable for containers requesting a TTY. It
+# is only set if neither the container image spec nor the container runtime
+# configuration provide TERM. An empty value disables setting TERM.
+{{ $.Comment }}default_term = "{{ .DefaultTerm }}"
+
+`
+
 const templateStringCrioRuntimeSelinux = `# If true, SELinux will be used for pod separation on the host.
 # This option is deprecated, and be interpreted from whether SELinux is enabled on the host in the future.
 {{ $.Comment }}selinux = {{ .SELinux }}
@@ -844,6 +1049,14 @@ const templateStringCrioRuntimeSeccompProfile = `# Path to the seccomp.json prof
 
 `
 
+const templateStringCrioRuntimeSeccompProfileDir = `# Directory of seccomp profiles which pods can request by name with the
+# "seccomp-profile.kubernetes.cri-o.io" annotation. The name "foo" refers to
+# the profile "foo.json" in the directory. Named profiles replace the default
+# profile of containers running with the RuntimeDefault seccomp profile.
+{{ $.Comment }}seccomp_profile_dir = "{{ .SeccompProfileDir }}"
+
+`
+
 const templateStringCrioRuntimeApparmorProfile = `# Used to change the name of the default AppArmor profile of CRI-O. The default
 # profile name is "crio-default". This profile only takes effect if the user
 # does not specify a profile via the Kubernetes Pod's metadata annotation. If
@@ -908,6 +1121,13 @@ const templateStringCrioRuntimeDefaultSysctls = `# List of default sysctls. If i
 
 `
 
+const templateStringCrioRuntimeStrictSysctls = `# If true, the creation of a pod sandbox fails if one of its sysctls, or one of
+# the default_sysctls, is not namespaced or conflicts with a host namespace of
+# the pod. Otherwise, such sysctls are skipped with a warning.
+{{ $.Comment }}strict_sysctls = {{ .StrictSysctls }}
+
+`
+
 const templateStringCrioRuntimeAllowedDevices = `# List of devices on the host that a
 # user can specify with the "io.kubernetes.cri-o.Devices" allowed annotation.
 {{ $.Comment }}allowed_devices = [
@@ -967,6 +1187,33 @@ const templateStringCrioRuntimePidsLimit = `# Maximum number of processes allowe
 
 `
 
+const templateStringCrioRuntimeMaxPods = `# Maximum number of pod sandboxes on the node. Pod sandbox creations beyond it
+# are rejected. 0 means that the number is not limited.
+{{ $.Comment }}max_pods = {{ .MaxPods }}
+
+`
+
+const templateStringCrioRuntimeMaxContainers = `# Maximum number of containers on the node, not counting infra containers.
+# Container creations beyond it are rejected. 0 means that the number is not
+# limited.
+{{ $.Comment }}max_containers = {{ .MaxContainers }}
+
+`
+
+const templateStringCrioRuntimeDefaultShmSize = `# Size of /dev/shm for pods not sharing the host IPC namespace, expressed as a
+# byte quantity like "64Mi". It can be overridden per pod by the
+# "io.kubernetes.cri-o.ShmSize" annotation.
+{{ $.Comment }}default_shm_size = "{{ .DefaultShmSize }}"
+
+`
+
+const templateStringCrioRuntimeDefaultUmask = `# Umask of the container init process, expressed as an octal string like
+# "0022". It can be overridden per pod by the "io.kubernetes.cri-o.umask"
+# annotation. If empty, the umask of the OCI runtime is used.
+{{ $.Comment }}default_umask = "{{ .DefaultUmask }}"
+
+`
+
 const templateStringCrioRuntimeLogSizeMax = `# Maximum sized allowed for the container log file. Negative numbers indicate
 # that no size limit is imposed. If it is positive, it must be >= 8192 to
 # match/exceed conmon's read buffer. The file is truncated and re-opened so the
@@ -1052,6 +1299,12 @@ const templateStringCrioRuntimeCtrStopTimeout = `# The minimal amount of time in
 
 `
 
+const templateStringCrioRuntimeDefaultStopSignal = `# The signal which stops containers whose image does not declare a stop
+# signal, as name like "SIGTERM" or number.
+{{ $.Comment }}default_stop_signal = "{{ .DefaultStopSignal }}"
+
+`
+
 const templateStringCrioRuntimeDropInfraCtr = `# drop_infra_ctr determines whether CRI-O drops the infra container
 # when a pod does not have a private PID namespace, and does not use
 # a kernel separating runtime (like kata).
@@ -1074,6 +1327,12 @@ const templateStringCrioRuntimeInfraCtrCpuset = `# infra_ctr_cpuset determines w
 
 `
 
+const templateStringCrioRuntimeInfraCtrOOMScoreAdj = `# infra_ctr_oom_score_adj is the OOM score adjustment of infra containers, between
+# -1000 and 1000. It is very low by default, so that they do not get killed.
+{{ $.Comment }}infra_ctr_oom_score_adj = {{ .InfraCtrOOMScoreAdj }}
+
+`
+
 const templateStringCrioRuntimeSharedCpuset = `# shared_cpuset  determines the CPU set which is allowed to be shared between guaranteed containers,
 # regardless of, and in addition to, the exclusiveness of their CPUs.
 # This field is optional and would not be used if not specified.
@@ -1100,6 +1359,12 @@ const templateStringCrioRuntimeEnablePodEvents = `# Enable/disable the generatio
 
 `
 
+const templateStringCrioRuntimeEnableCriuSupport = `# Enable checkpointing containers and restoring them from checkpoints with
+# CRIU, which requires the criu binary in $PATH.
+{{ $.Comment }}enable_criu_support = {{ .EnableCriuSupport }}
+
+`
+
 const templateStringCrioRuntimeDefaultRuntime = `# default_runtime is the _name_ of the OCI runtime to be used as the default.
 # The name is matched against the runtimes map below.
 {{ $.Comment }}default_runtime = "{{ .DefaultRuntime }}"
@@ -1117,6 +1382,40 @@ const templateStringCrioRuntimeAbsentMountSourcesToReject = `# A list of paths t
 
 `
 
+const templateStringCrioRuntimeDeniedMountSources = `# A list of absolute host paths which cannot be bind mounted into containers,
+# including everything below them, for example "/etc/kubernetes". Their
+# parent directories, up to "/", cannot be mounted either, since that would
+# expose the denied paths as well. Sources are checked as requested and after
+# resolving symlinks, against the entries as given and with their symlinks
+# resolved.
+{{ $.Comment }}denied_mount_sources = [
+{{ range $mount := .DeniedMountSources}}{{ $.Comment }}{{ printf "\t%q,\n" $mount}}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioRuntimeAllowedMountSources = `# A list of absolute host paths below which the sources of bind mounts into
+# containers have to be. An empty list allows all host paths which are not
+# denied by denied_mount_sources.
+{{ $.Comment }}allowed_mount_sources = [
+{{ range $mount := .AllowedMountSources}}{{ $.Comment }}{{ printf "\t%q,\n" $mount}}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioRuntimeRelabelWorkers = `# Number of bind mount sources to SELinux relabel in parallel during container
+# creation. The container creation still waits for all relabels to finish,
+# and fails with the errors of all failed relabels. Set to 0 to relabel the
+# mount sources one after another.
+{{ $.Comment }}relabel_workers = {{ .RelabelWorkers }}
+
+`
+
+const templateStringCrioRuntimeRROMountsFallbackToReadOnly = `# Degrade recursive read-only mounts to regular read-only mounts if the OCI
+# runtime or the kernel does not support them, instead of failing the container
+# creation. Submounts of such a mount stay writable.
+{{ $.Comment }}rro_mounts_fallback_to_read_only = {{ .RROMountsFallbackToReadOnly }}
+
+`
+
 const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.runtimes" table defines a list of OCI compatible runtimes.
 # The runtime to use is picked based on the runtime handler provided by the CRI.
 # If no runtime handler is provided, the "default_runtime" will be used.
@@ -1136,6 +1435,9 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # platform_runtime_paths = { "os/arch" = "/path/to/binary" }
 # no_sync_log = false
 # default_annotations = {}
+# default_env = []
+# absent_mount_sources_to_reject = []
+# allowed_unsafe_sysctls = []
 # Where:
 # - runtime-handler: Name used to identify the runtime.
 # - runtime_path (optional, string): Absolute path to the runtime executable in
@@ -1160,6 +1462,9 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   "io.kubernetes.cri-o.cgroup2-mount-hierarchy-rw" for mounting cgroups writably when set to "true".
 #   "io.kubernetes.cri-o.Devices" for configuring devices for the pod.
 #   "io.kubernetes.cri-o.ShmSize" for configuring the size of /dev/shm.
+#   "io.kubernetes.cri-o.ShmSize.$CTR_NAME" for configuring the size of a dedicated /dev/shm for a container.
+#   "io.kubernetes.cri-o.PrivateShm.$CTR_NAME" for giving a container a dedicated /dev/shm.
+#   "io.kubernetes.cri-o.DNSConfig" for giving a container a dedicated resolv.conf, set on the container and configured by a JSON object with "servers", "searches" and "options".
 #   "io.kubernetes.cri-o.UnifiedCgroup.$CTR_NAME" for configuring the cgroup v2 unified block for a container.
 #   "io.containers.trace-syscall" for tracing syscalls via the OCI seccomp BPF hook.
 #   "io.kubernetes.cri-o.seccompNotifierAction" for enabling the seccomp notifier feature.
@@ -1172,6 +1477,16 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #     For images, the plain annotation "seccomp-profile.kubernetes.cri-o.io"
 #     can be used without the required "/POD" suffix or a container name.
 #   "io.kubernetes.cri-o.DisableFIPS" for disabling FIPS mode in a Kubernetes pod within a FIPS-enabled cluster.
+#   "io.kubernetes.cri-o.EnableFIPS" for enabling FIPS mode in a Kubernetes pod on a node which has not enabled it.
+#   "io.kubernetes.cri-o.DisableMtabSymlink" for disabling the /etc/mtab symlink to /proc/mounts in a container.
+#   "io.kubernetes.cri-o.DryRun" for only generating the OCI spec of a container, without creating it.
+#   "io.kubernetes.cri-o.SkipNRI" for not invoking NRI plugins for a pod or container.
+#   "io.kubernetes.cri-o.RestoreFromCheckpoint" for restoring a container from a checkpoint archive or directory,
+#     if enable_criu_support is set.
+#   "io.kubernetes.cri-o.HostUTS" for using the host UTS namespace in a pod in the host network namespace.
+#     Containers with CAP_SYS_ADMIN can then change the hostname of the node.
+#   "io.kubernetes.cri-o.ImagePullPolicy" for setting the pull policy of the pause image and the images
+#     pulled for a pod, one of "Always", "IfNotPresent" or "Never".
 # - monitor_path (optional, string): The path of the monitor binary. Replaces
 #   deprecated option "conmon".
 # - monitor_cgroup (optional, string): The cgroup the container monitor process will be put in.
@@ -1189,6 +1504,17 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   This option is only valid for the 'oci' runtime type. Setting this option to true can cause data loss, e.g.
 #   when a machine crash happens.
 # - default_annotations (optional, map): Default annotations if not overridden by the pod spec.
+# - default_env (optional, array of strings): Environment variables to set for the containers
+#   of this runtime handler. They are applied after the global default_env and are overridden
+#   if set in the container image spec or in the container runtime configuration.
+# - absent_mount_sources_to_reject (optional, array of strings): Paths that, when absent from the host,
+#   cause the creation of the containers of this runtime handler to fail. They are merged with the
+#   global absent_mount_sources_to_reject. Each path has to be absolute and clean.
+# - allowed_unsafe_sysctls (optional, array of strings): Sysctls which the pods of this runtime handler
+#   may set, even if they are not known to be namespaced. A pattern ending with "*", like "kernel.*",
+#   allows all sysctls with the preceding prefix. Sysctls conflicting with a host namespace of the pod,
+#   including "kernel.hostname" and "kernel.domainname" with the host UTS namespace, are still rejected.
+#   Sysctls known to affect the whole host, like "vm.*", cannot be allowed.
 #
 # Using the seccomp notifier feature:
 #
@@ -1241,6 +1567,15 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 {{- $first := true }}{{- range $key, $value := $runtime_handler.DefaultAnnotations }}
 {{- if not $first }},{{ end }}{{- printf "%q = %q" $key $value }}{{- $first = false }}{{- end }}}
 {{ end }}
+{{ if $runtime_handler.DefaultEnv }}{{ $.Comment }}default_env = [
+{{ range $env := $runtime_handler.DefaultEnv }}{{ $.Comment }}{{ printf "\t%q,\n" $env }}{{ end }}{{ $.Comment }}]
+{{ end }}
+{{ if $runtime_handler.AbsentMountSourcesToReject }}{{ $.Comment }}absent_mount_sources_to_reject = [
+{{ range $path := $runtime_handler.AbsentMountSourcesToReject }}{{ $.Comment }}{{ printf "\t%q,\n" $path }}{{ end }}{{ $.Comment }}]
+{{ end }}
+{{ if $runtime_handler.AllowedUnsafeSysctls }}{{ $.Comment }}allowed_unsafe_sysctls = [
+{{ range $sysctl := $runtime_handler.AllowedUnsafeSysctls }}{{ $.Comment }}{{ printf "\t%q,\n" $sysctl }}{{ end }}{{ $.Comment }}]
+{{ end }}
 {{ end }}
 `
 
@@ -1306,6 +1641,36 @@ const templateStringCrioRuntimeTimezone = `# timezone To set the timezone for a
 
 `
 
+const templateStringCrioRuntimeNamingStrategy = `# Strategy generating the names of pod sandboxes and containers, as shown by ps
+# and in the logs. The "kubernetes" strategy names them k8s_<pod>_<namespace>_<uid>_<attempt>
+# and k8s_<container>_<pod>_<namespace>_<uid>_<attempt>. The "descriptive" strategy
+# leads with the namespace and pod, <namespace>_<pod>_<container>_<attempt>_<uid>,
+# truncating names longer than 253 characters.
+{{ $.Comment }}naming_strategy = "{{ .NamingStrategy }}"
+
+`
+
+const templateStringCrioRuntimeAuditWebhookURL = `# HTTP or HTTPS endpoint container creations and removals are POSTed to as JSON,
+# with the identity of the container and its pod and the security attributes of
+# the container. Creations are sent before the container gets created, and a
+# non 2xx response aborts the creation with the message of the response.
+# The webhook is disabled if empty.
+{{ $.Comment }}audit_webhook_url = "{{ .AuditWebhookURL }}"
+
+`
+
+const templateStringCrioRuntimeAuditWebhookTimeout = `# Maximum time a call of the audit webhook may take. It must be positive, so that
+# a hanging webhook cannot block container operations.
+{{ $.Comment }}audit_webhook_timeout = "{{ .AuditWebhookTimeout }}"
+
+`
+
+const templateStringCrioRuntimeAuditWebhookFailOpen = `# Let container creations proceed if the audit webhook cannot be reached or times
+# out, instead of failing them. Denials of the webhook always abort the creation.
+{{ $.Comment }}audit_webhook_fail_open = {{ .AuditWebhookFailOpen }}
+
+`
+
 const templateStringCrioImage = `# The crio.image table contains settings pertaining to the management of OCI images.
 #
 # CRI-O reads its configured registries defaults from the system wide
@@ -1350,6 +1715,33 @@ const templateStringCrioImageImageVolumes = `# Controls how image volumes are ha
 
 `
 
+const templateStringCrioImageImageVolumesReadOnly = `# Mount the volumes specified in the image config read-only. Only applies to
+# the mkdir and bind image volume types.
+{{ $.Comment }}image_volumes_read_only = {{ .ImageVolumesReadOnly }}
+
+`
+
+const templateStringCrioImageImageMountLowerdirOrder = `# Controls the precedence of the overlay lower directories of image mounts.
+# The valid values are image-first, where files of the mounted image shadow the
+# image volumes scratch directory, and scratch-first for the reverse order.
+{{ $.Comment }}image_mount_lowerdir_order = "{{ .ImageMountLowerdirOrder }}"
+
+`
+
+const templateStringCrioImageImageVolumesDir = `# Path to the empty scratch directory used as overlay lower directory of image
+# mounts. CRI-O refuses to create image mounts if the directory is not empty.
+# If empty, the directory "image-volumes" next to container_exits_dir is used.
+{{ $.Comment }}image_volumes_dir = "{{ .ImageVolumesDir }}"
+
+`
+
+const templateStringCrioImageImageMountsCopyOnWrite = `# Make image mounts which are not requested read-only writable. Writes go to a
+# per container overlay upper directory next to image_volumes_dir and never
+# reach the shared image. Without it, all image mounts are read-only.
+{{ $.Comment }}image_mounts_copy_on_write = {{ .ImageMountsCopyOnWrite }}
+
+`
+
 const templateStringCrioImageBigFilesTemporaryDir = `# Temporary directory to use for storing big files
 {{ $.Comment }}big_files_temporary_dir = "{{ .BigFilesTemporaryDir }}"
 
@@ -1362,6 +1754,13 @@ const templateStringCrioImagePullProgressTimeout = `# The timeout for an image p
 
 `
 
+const templateStringCrioImageImageQuarantineDuration = `# How long pulls of an image fail fast with the error of its last failed pull,
+# instead of retrying it from scratch for every pod using the image.
+# The default of 0 disables the quarantine.
+{{ $.Comment }}image_quarantine_duration = "{{ .ImageQuarantineDuration }}"
+
+`
+
 const templateStringCrioNetwork = `# The crio.network table containers settings pertaining to the management of
 # CNI plugins.
 [crio.network]
@@ -1385,6 +1784,20 @@ const templateStringCrioNetworkPluginDirs = `# Paths to directories where CNI pl
 
 `
 
+const templateStringCrioNetworkSetupTimeout = `# Maximum time the setup of a pod network may take. If it expires, the pod
+# sandbox creation fails with an error naming the network stage. The deadline
+# of the request caps it. If zero, the setup may take as long as the request.
+{{ $.Comment }}setup_timeout = "{{ .SetupTimeout }}"
+
+`
+
+const templateStringCrioNetworkPluginWaitTimeout = `# Maximum time a pod sandbox creation waits for the CNI plugin to get ready. If
+# it expires, the creation fails and gets retried by the kubelet. If zero, the
+# creation waits as long as the request.
+{{ $.Comment }}plugin_wait_timeout = "{{ .PluginWaitTimeout }}"
+
+`
+
 const templateStringCrioMetrics = `# A necessary configuration for Prometheus based metrics retrieval
 [crio.metrics]
 
@@ -1515,3 +1928,23 @@ const templateStringCrioNRIPluginRequestTimeout = `# Timeout for a plugin to han
 {{ $.Comment }}nri_plugin_request_timeout = "{{ .NRI.PluginRequestTimeout }}"
 
 `
+
+const templateStringCrioNRICallTimeout = `# Timeout for all plugins together to handle the NRI request of a pod sandbox
+# or container creation. Zero means no timeout on top of the plugin request
+# timeout.
+{{ $.Comment }}nri_call_timeout = "{{ .NRI.CallTimeout }}"
+
+`
+
+const templateStringCrioNRIRunPodSandboxFailurePolicy = `# What to do if the NRI RunPodSandbox request fails or times out: "fail" fails
+# the pod sandbox creation, "ignore" logs the failure and continues.
+{{ $.Comment }}nri_run_pod_sandbox_failure_policy = "{{ .NRI.RunPodSandboxFailurePolicy }}"
+
+`
+
+const templateStringCrioNRICreateContainerFailurePolicy = `# What to do if the NRI CreateContainer request fails or times out: "fail"
+# fails the container creation, "ignore" logs the failure and creates the
+# container without the NRI adjustments.
+{{ $.Comment }}nri_create_container_failure_policy = "{{ .NRI.CreateContainerFailurePolicy }}"
+
+`
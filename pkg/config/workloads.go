@@ -3,6 +3,8 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/opencontainers/runtime-tools/generate"
@@ -108,6 +110,14 @@ func (w Workloads) AllowedAnnotations(toFind map[string]string) []string {
 	return workload.AllowedAnnotations
 }
 
+// WorkloadName returns the name of the workload activated by one of the
+// annotations in toFind, or an empty string if no workload is activated. If
+// several workloads are activated, the first one by name is used.
+func (w Workloads) WorkloadName(toFind map[string]string) string {
+	name, _ := w.activatedWorkload(toFind)
+	return name
+}
+
 // FilterDisallowedAnnotations filters annotations that are not specified in the allowed_annotations map
 // for a given handler.
 // This function returns an error if the runtime handler can't be found.
@@ -144,14 +154,20 @@ func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.
 }
 
 func (w Workloads) workloadGivenActivationAnnotation(sboxAnnotations map[string]string) *WorkloadConfig {
-	for _, wc := range w {
-		for annotation := range sboxAnnotations {
-			if wc.ActivationAnnotation == annotation {
-				return wc
-			}
+	_, wc := w.activatedWorkload(sboxAnnotations)
+	return wc
+}
+
+// activatedWorkload returns the workload activated by one of the annotations
+// in toFind. The workloads are checked in the order of their names, so that
+// the same workload is picked every time if several are activated.
+func (w Workloads) activatedWorkload(toFind map[string]string) (string, *WorkloadConfig) {
+	for _, name := range slices.Sorted(maps.Keys(w)) {
+		if _, ok := toFind[w[name].ActivationAnnotation]; ok {
+			return name, w[name]
 		}
 	}
-	return nil
+	return "", nil
 }
 
 func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]string, defaultResources *Resources) (*Resources, error) {
@@ -18,6 +18,49 @@ func pointer[A any](m A) *A {
 var _ = t.Describe("Workloads config", func() {
 	BeforeEach(beforeEach)
 
+	It("should get the name of the activated workload", func() {
+		// Given
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				ActivationAnnotation: "target.workload.openshift.io/management",
+			},
+		}
+		// When
+		name := workloads.WorkloadName(map[string]string{"target.workload.openshift.io/management": ""})
+		// Then
+		Expect(name).To(Equal("management"))
+	})
+
+	It("should get the first activated workload by name", func() {
+		// Given
+		workloads := config.Workloads{}
+		toFind := map[string]string{}
+		for _, name := range []string{"d", "b", "c", "e"} {
+			workloads[name] = &config.WorkloadConfig{ActivationAnnotation: "workload/" + name}
+			toFind["workload/"+name] = ""
+		}
+		workloads["a"] = &config.WorkloadConfig{ActivationAnnotation: "workload/a"}
+		// When
+		for range 10 {
+			name := workloads.WorkloadName(toFind)
+			// Then
+			Expect(name).To(Equal("b"))
+		}
+	})
+
+	It("should get no workload name without activation annotation", func() {
+		// Given
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				ActivationAnnotation: "target.workload.openshift.io/management",
+			},
+		}
+		// When
+		name := workloads.WorkloadName(map[string]string{"other": ""})
+		// Then
+		Expect(name).To(BeEmpty())
+	})
+
 	It("should fail on invalid cpuset", func() {
 		// Given
 		workloads := config.Workloads{
@@ -16,6 +16,13 @@ type ContainerInfo struct {
 	IPs             []string          `json:"ip_addresses"`
 }
 
+// NamespaceInfo stores information about namespaces managed by sandboxes.
+type NamespaceInfo struct {
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Pinned bool   `json:"pinned"` // If set, the namespace is still bind mounted at Path.
+}
+
 // CrioInfo stores information about the crio daemon.
 type CrioInfo struct {
 	StorageRoot  string `json:"storage_root"`
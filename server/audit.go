@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/audit"
+	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
+	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+// newAuditRequest describes an operation on a container of a pod sandbox for
+// the audit webhook.
+func newAuditRequest(operation audit.Operation, sb *sandbox.Sandbox, ctr *oci.Container) *audit.Request {
+	nsOpts := sb.NamespaceOptions()
+	return &audit.Request{
+		Operation: operation,
+		Pod: audit.Pod{
+			ID:          sb.ID(),
+			Name:        sb.Metadata().GetName(),
+			Namespace:   sb.Namespace(),
+			UID:         sb.Metadata().GetUid(),
+			HostNetwork: sb.HostNetwork(),
+			HostPID:     nsOpts.GetPid() == types.NamespaceMode_NODE,
+			HostIPC:     nsOpts.GetIpc() == types.NamespaceMode_NODE,
+		},
+		Container: audit.Container{
+			ID:    ctr.ID(),
+			Name:  ctr.Name(),
+			Image: ctr.UserRequestedImage(),
+		},
+	}
+}
+
+// reviewContainerCreation asks the audit webhook whether the container may be
+// created.
+func (s *Server) reviewContainerCreation(ctx context.Context, sb *sandbox.Sandbox, ctr *oci.Container) error {
+	req := newAuditRequest(audit.OperationCreate, sb, ctr)
+	req.Container.Security = ctr.SecurityPosture()
+	return s.auditWebhook.Review(ctx, req)
+}
+
+// auditContainerRemoval reports the removal of a container to the audit
+// webhook. The container is gone already, so failures are only logged.
+func (s *Server) auditContainerRemoval(ctx context.Context, sb *sandbox.Sandbox, ctr *oci.Container) {
+	if err := s.auditWebhook.Review(ctx, newAuditRequest(audit.OperationRemove, sb, ctr)); err != nil {
+		log.Warnf(ctx, "Audit webhook failed for removal of container %s: %v", ctr.ID(), err)
+	}
+}
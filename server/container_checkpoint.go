@@ -3,11 +3,48 @@ package server
 import (
 	"context"
 	"errors"
+	"path/filepath"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/lib"
+	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/oci"
 )
 
-// CheckpointContainer checkpoints a container.
+// CheckpointContainer checkpoints a container if enable_criu_support is set.
+// The container keeps running, and the checkpoint is exported to a new archive
+// at the requested absolute location. Without a location, the checkpoint is
+// only kept in the checkpoint directory of the container.
 func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointContainerRequest) (*types.CheckpointContainerResponse, error) {
-	return nil, errors.New("checkpoint/restore support not available")
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+	if !s.config.EnableCriuSupport {
+		return nil, status.Error(codes.Unimplemented, "checkpoint/restore support not enabled")
+	}
+	if req.Location != "" && (!filepath.IsAbs(req.Location) || filepath.Clean(req.Location) != req.Location) {
+		return nil, status.Errorf(codes.InvalidArgument, "checkpoint location %q is not a clean absolute path", req.Location)
+	}
+	ctr, err := s.GetContainerFromShortID(ctx, req.ContainerId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "could not find container %q: %v", req.ContainerId, err)
+	}
+
+	opts := &lib.ContainerCheckpointOptions{
+		TargetFile:  req.Location,
+		KeepRunning: true,
+	}
+
+	log.Infof(ctx, "Checkpointing container: %s", req.ContainerId)
+	if _, err := s.ContainerServer.ContainerCheckpoint(ctx, ctr, opts); err != nil {
+		if errors.Is(err, oci.ErrCheckpointRestoreNotSupported) {
+			return nil, status.Errorf(codes.Unimplemented, "%v", err)
+		}
+		return nil, err
+	}
+	log.Infof(ctx, "Checkpointed container: %s", req.ContainerId)
+
+	return &types.CheckpointContainerResponse{}, nil
 }
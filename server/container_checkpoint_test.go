@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestCheckpointContainerRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		enabled  bool
+		location string
+		want     codes.Code
+	}{
+		{"disabled", false, "/var/lib/checkpoints/ctr.tar", codes.Unimplemented},
+		{"relative location", true, "ctr.tar", codes.InvalidArgument},
+		{"unclean location", true, "/var/lib/checkpoints/../ctr.tar", codes.InvalidArgument},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := &Server{}
+			sut.config.EnableCriuSupport = tc.enabled
+
+			_, err := sut.CheckpointContainer(context.Background(), &types.CheckpointContainerRequest{
+				ContainerId: "ctr",
+				Location:    tc.location,
+			})
+
+			if code := status.Code(err); code != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ import (
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/L-F-Z/cri-t/internal/factory/container"
@@ -24,10 +26,66 @@ import (
 	"github.com/L-F-Z/cri-t/internal/oci"
 	"github.com/L-F-Z/cri-t/internal/resourcestore"
 	"github.com/L-F-Z/cri-t/internal/storage"
+	"github.com/L-F-Z/cri-t/pkg/annotations"
 	"github.com/L-F-Z/cri-t/pkg/config"
+	"github.com/L-F-Z/cri-t/server/metrics"
 	"github.com/L-F-Z/cri-t/utils"
 )
 
+// ErrUnsupportedSupplementalGroupsPolicy is returned when a container requests
+// a SupplementalGroupsPolicy which is not implemented.
+var ErrUnsupportedSupplementalGroupsPolicy = errors.New("unsupported SupplementalGroupsPolicy")
+
+// ErrTooManySymlinks is returned when resolving a bind mount source follows
+// more than maxSymlinkDepth symlinks, for example because of a symlink cycle.
+var ErrTooManySymlinks = errors.New("too many levels of symbolic links")
+
+// maxSymlinkDepth is the maximum number of symlinks followed when resolving a
+// bind mount source. It is the MAXSYMLINKS limit of Linux.
+const maxSymlinkDepth = 40
+
+// ErrDryRun is returned by a container creation using the DryRunAnnotation
+// once the OCI spec of the container got generated. The error names the path
+// of the spec.
+var ErrDryRun = errors.New("dry run of container creation")
+
+// dryRunError aborts a dry run container creation once the OCI spec of the
+// container got written to specPath.
+type dryRunError struct {
+	specPath string
+}
+
+func (e *dryRunError) Error() string {
+	return fmt.Sprintf("%v, spec written to %s", ErrDryRun, e.specPath)
+}
+
+func (e *dryRunError) Unwrap() error {
+	return ErrDryRun
+}
+
+// GRPCStatus lets clients tell a dry run apart from a failed creation.
+func (e *dryRunError) GRPCStatus() *status.Status {
+	return status.New(codes.Aborted, e.Error())
+}
+
+// writeDryRunSpec writes the generated OCI spec of a container to a temporary
+// file and returns a dryRunError referring to it.
+func writeDryRunSpec(specgen *generate.Generator, ctrID string) error {
+	f, err := os.CreateTemp("", "crio-dry-run-"+ctrID+"-*.json")
+	if err != nil {
+		return fmt.Errorf("create dry run spec file: %w", err)
+	}
+	specPath := f.Name()
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close dry run spec file: %w", err)
+	}
+	if err := specgen.SaveToFile(specPath, generate.ExportOptions{}); err != nil {
+		os.Remove(specPath)
+		return fmt.Errorf("write dry run spec: %w", err)
+	}
+	return &dryRunError{specPath: specPath}
+}
+
 // sync with https://github.com/containers/storage/blob/7fe03f6c765f2adbc75a5691a1fb4f19e56e7071/pkg/truncindex/truncindex.go#L92
 const noSuchID = "no such id"
 
@@ -119,12 +177,17 @@ func ensureSharedOrSlave(path string, mountInfos []*mount.Info) error {
 	return fmt.Errorf("path %q is mounted on %q but it is not a shared or slave mount", path, sourceMount)
 }
 
-func addImageVolumes(ctx context.Context, rootfs string, s *Server, containerInfo *storage.ContainerInfo, mountLabel string, specgen *generate.Generator) ([]rspec.Mount, error) {
+func addImageVolumes(ctx context.Context, rootfs string, s *Server, containerInfo *storage.ContainerInfo, mountLabel string, specgen *generate.Generator, criMounts []rspec.Mount) ([]rspec.Mount, error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 
 	mounts := []rspec.Mount{}
 	for dest := range containerInfo.Config.Config.Volumes {
+		// Mounts requested through the CRI take precedence over image volumes.
+		if mountExists(criMounts, dest) || mountExists(specgen.Mounts(), dest) {
+			log.Debugf(ctx, "Skipping image volume %s because the destination is already mounted", dest)
+			continue
+		}
 		fp, err := securejoin.SecureJoin(rootfs, dest)
 		if err != nil {
 			return nil, err
@@ -140,6 +203,17 @@ func addImageVolumes(ctx context.Context, rootfs string, s *Server, containerInf
 					return nil, err1
 				}
 			}
+			if s.config.ImageVolumesReadOnly {
+				// The directory is part of the container rootfs, so bind
+				// mount it onto itself to make it read-only.
+				log.Debugf(ctx, "Adding read-only volume: %s", dest)
+				mounts = append(mounts, rspec.Mount{
+					Source:      fp,
+					Destination: dest,
+					Type:        "bind",
+					Options:     []string{"private", "bind", "ro"},
+				})
+			}
 		case config.ImageVolumesBind:
 			volumeDirName := stringid.GenerateNonCryptoID()
 			src := filepath.Join(containerInfo.RunDir, "mounts", volumeDirName)
@@ -153,12 +227,16 @@ func addImageVolumes(ctx context.Context, rootfs string, s *Server, containerInf
 				}
 			}
 
-			log.Debugf(ctx, "Adding bind mounted volume: %s to %s", src, dest)
+			mode := "rw"
+			if s.config.ImageVolumesReadOnly {
+				mode = "ro"
+			}
+			log.Debugf(ctx, "Adding bind mounted volume: %s to %s (%s)", src, dest, mode)
 			mounts = append(mounts, rspec.Mount{
 				Source:      src,
 				Destination: dest,
 				Type:        "bind",
-				Options:     []string{"private", "bind", "rw"},
+				Options:     []string{"private", "bind", mode},
 			})
 
 		case config.ImageVolumesIgnore:
@@ -170,6 +248,16 @@ func addImageVolumes(ctx context.Context, rootfs string, s *Server, containerInf
 	return mounts, nil
 }
 
+// mountExists returns true if dest exists in the list of mounts.
+func mountExists(specMounts []rspec.Mount, dest string) bool {
+	for _, m := range specMounts {
+		if m.Destination == dest {
+			return true
+		}
+	}
+	return false
+}
+
 // resolveSymbolicLink resolves a possible symlink path. If the path is a symlink, returns resolved
 // path; if not, returns the original path.
 // note: strictly SecureJoin is not sufficient, as it does not error when a part of the path doesn't exist
@@ -185,7 +273,41 @@ func resolveSymbolicLink(scope, path string) (string, error) {
 	if scope == "" {
 		scope = "/"
 	}
-	return securejoin.SecureJoin(scope, path)
+	resolved, err := securejoin.SecureJoinVFS(scope, path, &symlinkLimitVFS{})
+	if errors.Is(err, ErrTooManySymlinks) {
+		return "", fmt.Errorf("%w: resolving %s followed more than %d symlinks", ErrTooManySymlinks, path, maxSymlinkDepth)
+	}
+	return resolved, err
+}
+
+// symlinkLimitVFS is the file system of securejoin, which fails to read more
+// than maxSymlinkDepth symlinks.
+type symlinkLimitVFS struct {
+	links int
+}
+
+func (v *symlinkLimitVFS) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+func (v *symlinkLimitVFS) Readlink(name string) (string, error) {
+	v.links++
+	if v.links > maxSymlinkDepth {
+		return "", ErrTooManySymlinks
+	}
+	return os.Readlink(name)
+}
+
+// finishDryRun rolls back the remaining resources reserved for a dry run
+// container creation and returns dryRun, which names the generated spec.
+func (s *Server) finishDryRun(ctx context.Context, ctrName string, dryRun *dryRunError, resourceCleaner *resourcestore.ResourceCleaner) error {
+	if err := resourceCleaner.Cleanup(); err != nil {
+		return fmt.Errorf("roll back dry run of container %s: %w", ctrName, err)
+	}
+	s.resourceStore.Delete(ctrName)
+
+	log.Infof(ctx, "Dry run of container %s, wrote spec to %s", ctrName, dryRun.specPath)
+	return dryRun
 }
 
 // setupContainerUser sets the UID, GID and supplemental groups in OCI runtime config.
@@ -301,11 +423,19 @@ func setupContainerUser(ctx context.Context, specgen *generate.Generator, rootfs
 
 	switch supplementalGroupsPolicy {
 	case types.SupplementalGroupsPolicy_Merge:
-		// Add groups from /etc/passwd and SupplementalGroups defined
-		// in security context.
+		// Add groups from /etc/passwd, the groups declared in the image
+		// config and SupplementalGroups defined in security context, in
+		// that order. Duplicates are only added once.
+		imageGroups, err := imageAdditionalGids(imageConfig)
+		if err != nil {
+			return err
+		}
 		for _, group := range addGroups {
 			specgen.AddProcessAdditionalGid(group)
 		}
+		for _, group := range imageGroups {
+			specgen.AddProcessAdditionalGid(group)
+		}
 		for _, group := range sc.SupplementalGroups {
 			specgen.AddProcessAdditionalGid(uint32(group))
 		}
@@ -316,12 +446,33 @@ func setupContainerUser(ctx context.Context, specgen *generate.Generator, rootfs
 		}
 
 	default:
-		return fmt.Errorf("not implemented in this CRI-O release: SupplementalGroupsPolicy=%v", supplementalGroupsPolicy)
+		return fmt.Errorf("%w: not implemented in this CRI-O release: SupplementalGroupsPolicy=%v", ErrUnsupportedSupplementalGroupsPolicy, supplementalGroupsPolicy)
 	}
 
 	return nil
 }
 
+// imageAdditionalGids returns the additional group IDs declared in the image
+// config through the AdditionalGIDsAnnotation label.
+func imageAdditionalGids(imageConfig *v1.Image) ([]uint32, error) {
+	if imageConfig == nil {
+		return nil, nil
+	}
+	value, ok := imageConfig.Config.Labels[annotations.AdditionalGIDsAnnotation]
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var gids []uint32
+	for _, field := range strings.Split(value, ",") {
+		gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid additional GID %q in image label %s: %w", field, annotations.AdditionalGIDsAnnotation, err)
+		}
+		gids = append(gids, uint32(gid))
+	}
+	return gids, nil
+}
+
 // generateUserString generates valid user string based on OCI Image Spec v1.0.0.
 func generateUserString(username, imageUser string, uid *types.Int64Value) string {
 	var userstr string
@@ -356,6 +507,18 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 		return nil, errors.New("sandbox config metadata is nil")
 	}
 
+	releaseSlot, err := s.containerSlots.reserve("containers", s.ContainerServer.ContainerCount, s.config.MaxContainers)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseSlot()
+
+	ctx, done, err := s.inFlightCreates.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
 	log.Infof(ctx, "Creating container: %s", oci.LabelsToDescription(req.GetConfig().GetLabels()))
 
 	sb, err := s.getPodSandboxFromRequest(ctx, req.PodSandboxId)
@@ -382,7 +545,11 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 		return nil, fmt.Errorf("setting container config: %w", err)
 	}
 
-	if err := ctr.SetNameAndID(""); err != nil {
+	namingStrategy, err := s.config.Naming()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctr.SetNameAndID(namingStrategy, ""); err != nil {
 		return nil, fmt.Errorf("setting container name and ID: %w", err)
 	}
 
@@ -395,7 +562,7 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 			return
 		}
 		if err := resourceCleaner.Cleanup(); err != nil {
-			log.Errorf(ctx, "Unable to cleanup: %v", err)
+			logCleanupError(ctx, "Unable to cleanup", err)
 		}
 	}()
 
@@ -416,18 +583,26 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 		return nil, fmt.Errorf("%w: %w", resourceErr, err)
 	}
 
-	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container creating")
+	s.setResourceStage(ctx, ctr.Name(), "container creating")
 
-	resourceCleaner.Add(ctx, "createCtr: releasing container name "+ctr.Name(), func() error {
+	resourceCleaner.AddResource(ctx, "container name", ctr.Name(), "createCtr: releasing container name "+ctr.Name(), func() error {
 		s.ReleaseContainerName(ctx, ctr.Name())
+		metrics.Instance().MetricResourceStageDurationDelete(ctr.Name())
 		return nil
 	})
 
-	newContainer, err := s.createSandboxContainer(ctx, ctr, sb)
+	newContainer, err := s.createSandboxContainer(ctx, ctr, sb, resourceCleaner)
 	if err != nil {
+		var dryRun *dryRunError
+		if errors.As(err, &dryRun) {
+			return nil, s.finishDryRun(ctx, ctr.Name(), dryRun, resourceCleaner)
+		}
+		if errors.Is(err, ErrUnsupportedSupplementalGroupsPolicy) {
+			return nil, status.Error(codes.Unimplemented, err.Error())
+		}
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "createCtr: deleting container "+ctr.ID()+" from storage", func() error {
+	resourceCleaner.AddResource(ctx, "container storage", ctr.ID(), "createCtr: deleting container "+ctr.ID()+" from storage", func() error {
 		if err := s.StorageService().DeleteContainer(ctx, ctr.ID()); err != nil {
 			return fmt.Errorf("failed to cleanup container storage: %w", err)
 		}
@@ -435,7 +610,7 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 	})
 
 	s.addContainer(ctx, newContainer)
-	resourceCleaner.Add(ctx, "createCtr: removing container "+newContainer.ID(), func() error {
+	resourceCleaner.AddResource(ctx, "container", newContainer.ID(), "createCtr: removing container "+newContainer.ID(), func() error {
 		s.removeContainer(ctx, newContainer)
 		return nil
 	})
@@ -443,18 +618,30 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 	if err := s.CtrIDIndex().Add(ctr.ID()); err != nil {
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "createCtr: deleting container ID "+ctr.ID()+" from idIndex", func() error {
+	resourceCleaner.AddResource(ctx, "container ID index entry", ctr.ID(), "createCtr: deleting container ID "+ctr.ID()+" from idIndex", func() error {
 		if err := s.CtrIDIndex().Delete(ctr.ID()); err != nil && !strings.Contains(err.Error(), noSuchID) {
 			return err
 		}
 		return nil
 	})
 
-	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container runtime creation")
-	if err := s.createContainerPlatform(ctx, newContainer, sb.CgroupParent()); err != nil {
-		return nil, err
+	if newContainer.Restore() {
+		// The restored process keeps running from the checkpoint, so
+		// StartContainer does not start it again.
+		s.setResourceStage(ctx, ctr.Name(), "container restore")
+		if err := s.Runtime().RestoreContainer(ctx, newContainer, sb.CgroupParent(), newContainer.Spec().Linux.MountLabel); err != nil {
+			if errors.Is(err, oci.ErrCheckpointRestoreNotSupported) {
+				return nil, status.Errorf(codes.Unimplemented, "restore container %s from checkpoint: %v", ctr.ID(), err)
+			}
+			return nil, fmt.Errorf("restore container %s from checkpoint: %w", ctr.ID(), err)
+		}
+	} else {
+		s.setResourceStage(ctx, ctr.Name(), "container runtime creation")
+		if err := s.createContainerPlatform(ctx, newContainer, sb.CgroupParent()); err != nil {
+			return nil, err
+		}
 	}
-	resourceCleaner.Add(ctx, "createCtr: removing container ID "+ctr.ID()+" from runtime", func() error {
+	resourceCleaner.AddResource(ctx, "runtime container", ctr.ID(), "createCtr: removing container ID "+ctr.ID()+" from runtime", func() error {
 		if err := s.Runtime().DeleteContainer(ctx, newContainer); err != nil {
 			return fmt.Errorf("failed to delete container in runtime %s: %w", ctr.ID(), err)
 		}
@@ -466,7 +653,7 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 	}
 
 	if isContextError(ctx.Err()) {
-		if err := s.resourceStore.Put(ctr.Name(), newContainer, resourceCleaner); err != nil {
+		if err := s.parkResource(ctx, "container", ctr.Name(), newContainer, resourceCleaner); err != nil {
 			log.Errorf(ctx, "CreateCtr: failed to save progress of container %s: %v", newContainer.ID(), err)
 		}
 		log.Infof(ctx, "CreateCtr: context was either canceled or the deadline was exceeded: %v", ctx.Err())
@@ -476,7 +663,7 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 	}
 
 	// Since it's not a context error, we can delete the resource from the store, it will be tracked in the server from now on.
-	s.resourceStore.Delete(ctr.Name())
+	s.finishResourceCreation(ctx, ctr.Name())
 
 	newContainer.SetCreated()
 
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	json "github.com/json-iterator/go"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/L-F-Z/cri-t/internal/factory/container"
+	"github.com/L-F-Z/cri-t/internal/resourcestore"
+)
+
+func TestWriteDryRunSpec(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specgen := ctr.Spec()
+	specgen.SetHostname("dry-run")
+
+	err = writeDryRunSpec(specgen, "ctrid")
+	var dryRun *dryRunError
+	if !errors.As(err, &dryRun) {
+		t.Fatalf("expected a dry run error, got %v", err)
+	}
+	defer os.Remove(dryRun.specPath)
+
+	data, err := os.ReadFile(dryRun.specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec rspec.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Hostname != "dry-run" {
+		t.Errorf("expected the generated spec, got hostname %q", spec.Hostname)
+	}
+}
+
+func TestFinishDryRunRollsBack(t *testing.T) {
+	sut := &Server{resourceStore: resourcestore.New()}
+	defer sut.resourceStore.Close()
+	ctx := context.Background()
+
+	sut.resourceStore.SetStageForResource(ctx, "ctr", "container creating")
+	cleaned := false
+	resourceCleaner := resourcestore.NewResourceCleaner()
+	resourceCleaner.Add(ctx, "release name", func() error {
+		cleaned = true
+		return nil
+	})
+
+	err := sut.finishDryRun(ctx, "ctr", &dryRunError{specPath: "/tmp/spec.json"}, resourceCleaner)
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected a dry run error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "/tmp/spec.json") {
+		t.Errorf("expected the error to name the spec, got %v", err)
+	}
+	if code := status.Code(err); code != codes.Aborted {
+		t.Errorf("expected code %s, got %s", codes.Aborted, code)
+	}
+	if !cleaned {
+		t.Error("expected the reserved resources to be rolled back")
+	}
+	if _, stage := sut.resourceStore.WatcherForResource("ctr"); stage != resourcestore.StageUnknown {
+		t.Errorf("expected the resource stage to be removed, got %q", stage)
+	}
+}
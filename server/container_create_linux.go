@@ -7,7 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,10 +17,18 @@ import (
 	"github.com/containers/common/pkg/timezone"
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/containers/storage/pkg/mount"
+	"github.com/containers/storage/pkg/stringid"
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/intel/goresctrl/pkg/blockio"
+	json "github.com/json-iterator/go"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 	kubeletTypes "k8s.io/kubelet/pkg/types"
 
@@ -29,17 +37,24 @@ import (
 	"github.com/L-F-Z/cri-t/internal/config/node"
 	"github.com/L-F-Z/cri-t/internal/config/rdt"
 	ctrfactory "github.com/L-F-Z/cri-t/internal/factory/container"
+	"github.com/L-F-Z/cri-t/internal/lib"
 	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
 	"github.com/L-F-Z/cri-t/internal/linklogs"
 	"github.com/L-F-Z/cri-t/internal/log"
 	oci "github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/internal/resourcestore"
 	"github.com/L-F-Z/cri-t/internal/runtimehandlerhooks"
 	crioann "github.com/L-F-Z/cri-t/pkg/annotations"
+	libconfig "github.com/L-F-Z/cri-t/pkg/config"
 )
 
 const (
 	cgroupSysFsPath        = "/sys/fs/cgroup"
 	cgroupSysFsSystemdPath = "/sys/fs/cgroup/systemd"
+
+	// tmpfsMountPrefix is the CRI mount host path prefix requesting a tmpfs
+	// instead of a bind mount.
+	tmpfsMountPrefix = "tmpfs:"
 )
 
 // createContainerPlatform performs platform dependent intermediate steps before calling the container's oci.Runtime().CreateContainer().
@@ -49,7 +64,7 @@ func (s *Server) createContainerPlatform(ctx context.Context, container *oci.Con
 	return s.Runtime().CreateContainer(ctx, container, cgroupParent, false)
 }
 
-func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Container, sb *sandbox.Sandbox) (cntr *oci.Container, retErr error) {
+func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Container, sb *sandbox.Sandbox, resourceCleaner *resourcestore.ResourceCleaner) (cntr *oci.Container, retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	// TODO: simplify this function (cyclomatic complexity here is high)
@@ -111,7 +126,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 
 	metadata := containerConfig.Metadata
 
-	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container storage creation")
+	s.setResourceStage(ctx, ctr.Name(), "container storage creation")
 	containerInfo, err := s.StorageService().CreateContainer(
 		sb.Name(), sb.ID(),
 		userRequestedImage, imageID,
@@ -133,23 +148,23 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		}
 	}()
 
-	mountLabel := containerInfo.MountLabel
-	var processLabel string
-	if !ctr.Privileged() {
-		processLabel = containerInfo.ProcessLabel
+	if source := containerConfig.Annotations[crioann.RestoreFromCheckpointAnnotation]; source != "" {
+		if !s.config.EnableCriuSupport {
+			return nil, status.Errorf(codes.Unimplemented, "restore container %s from checkpoint: checkpoint/restore support not enabled", containerName)
+		}
+		s.setResourceStage(ctx, ctr.Name(), "container checkpoint import")
+		if err := lib.ImportCheckpoint(source, containerInfo.Dir, imageID); err != nil {
+			return nil, err
+		}
+		ctr.SetRestore(true)
 	}
+
 	hostIPC := securityContext.NamespaceOptions.Ipc == types.NamespaceMode_NODE
 	hostPID := securityContext.NamespaceOptions.Pid == types.NamespaceMode_NODE
 	hostNet := securityContext.NamespaceOptions.Network == types.NamespaceMode_NODE
 
-	// Don't use SELinux separation with Host Pid or IPC Namespace or privileged.
-	if hostPID || hostIPC {
-		processLabel, mountLabel = "", ""
-	}
-
-	if hostNet && s.config.RuntimeConfig.HostNetworkDisableSELinux {
-		processLabel = ""
-	}
+	processLabel, mountLabel := effectiveLabels(containerInfo.ProcessLabel, containerInfo.MountLabel,
+		hostPID, hostIPC, hostNet, ctr.Privileged(), s.config.RuntimeConfig.HostNetworkDisableSELinux)
 
 	maybeRelabel := false
 	if val, present := sb.Annotations()[crioann.TrySkipVolumeSELinuxLabelAnnotation]; present && val == "true" {
@@ -169,21 +184,31 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 
 	cgroup2RW := node.CgroupIsV2() && sb.Annotations()[crioann.Cgroup2RWAnnotation] == "true"
 
-	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container volume configuration")
+	s.setResourceStage(ctx, ctr.Name(), "container volume configuration")
 	idMapSupport := s.Runtime().RuntimeSupportsIDMap(sb.RuntimeHandler())
+	if s.Runtime().RuntimeFeaturesLoaded(sb.RuntimeHandler()) {
+		log.Debugf(ctx, "Using OCI runtime features of runtime handler %q for idmap mount support: %v", sb.RuntimeHandler(), idMapSupport)
+	} else {
+		log.Debugf(ctx, "OCI runtime features of runtime handler %q are not available, assuming no idmap mount support", sb.RuntimeHandler())
+	}
 	rroSupport := s.Runtime().RuntimeSupportsRROMounts(sb.RuntimeHandler())
-	containerVolumes, ociMounts, err := s.addOCIBindMounts(ctx, ctr, mountLabel, s.config.RuntimeConfig.BindMountPrefix, s.config.AbsentMountSourcesToReject, maybeRelabel, skipRelabel, cgroup2RW, idMapSupport, rroSupport, s.Config().Root)
+	handlerAbsentMountSourcesToReject, err := s.Runtime().RuntimeAbsentMountSourcesToReject(sb.RuntimeHandler())
+	if err != nil {
+		return nil, err
+	}
+	absentMountSourcesToReject := slices.Concat(s.config.AbsentMountSourcesToReject, handlerAbsentMountSourcesToReject)
+	containerVolumes, ociMounts, err := s.addOCIBindMounts(ctx, ctr, mountLabel, s.config.RuntimeConfig.BindMountPrefix, absentMountSourcesToReject, maybeRelabel, skipRelabel, cgroup2RW, idMapSupport, rroSupport, s.Config().Root, resourceCleaner)
 	if err != nil {
 		return nil, err
 	}
 
-	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container device creation")
+	s.setResourceStage(ctx, ctr.Name(), "container device creation")
 	err = s.specSetDevices(ctr, sb)
 	if err != nil {
 		return nil, err
 	}
 
-	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container spec configuration")
+	s.setResourceStage(ctx, ctr.Name(), "container spec configuration")
 
 	labels := containerConfig.Labels
 
@@ -207,9 +232,6 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	}
 
 	specgen.SetProcessTerminal(containerConfig.Tty)
-	if containerConfig.Tty {
-		specgen.AddProcessEnv("TERM", "xterm")
-	}
 
 	linux := containerConfig.Linux
 	if linux != nil {
@@ -240,6 +262,9 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	var nsTargetCtr *oci.Container
 	if target := securityContext.NamespaceOptions.TargetId; target != "" {
 		nsTargetCtr = s.GetContainer(ctx, target)
+		if err := validateNamespaceTarget(target, nsTargetCtr); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := ctr.SpecAddNamespaces(sb, nsTargetCtr, &s.config); err != nil {
@@ -305,10 +330,41 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		}
 	}
 
+	shmPath := sb.ShmPath()
+	shmSize, err := containerShmSize(sb.Annotations(), metadata.Name)
+	if err != nil {
+		return nil, err
+	}
+	privateShm := containerPrivateShm(sb.Annotations(), containerConfig.Annotations, metadata.Name)
+	if privateShm && shmSize == 0 {
+		shmSize, err = s.sandboxShmSize(sb.Annotations())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if shmSize > 0 {
+		if shmPath == sandbox.DevShmPath {
+			log.Warnf(ctx, "Ignoring dedicated shm for container %s because the sandbox uses the host IPC namespace", containerID)
+		} else {
+			shmPath, err = sandbox.SetupShm(containerInfo.RunDir, mountLabel, shmSize)
+			if err != nil {
+				return nil, fmt.Errorf("setup shm for container %s: %w", containerID, err)
+			}
+			defer func() {
+				if retErr != nil {
+					log.Infof(ctx, "CreateCtrLinux: unmounting shm for container %s", containerID)
+					if err := unix.Unmount(shmPath, unix.MNT_DETACH); err != nil {
+						log.Warnf(ctx, "Failed to unmount shm for container: %v", err)
+					}
+				}
+			}()
+			specgen.AddAnnotation(crioann.ShmPath, shmPath)
+		}
+	}
 	ctr.SpecAddMount(rspec.Mount{
 		Destination: "/dev/shm",
 		Type:        "bind",
-		Source:      sb.ShmPath(),
+		Source:      shmPath,
 		Options:     []string{"rw", "bind"},
 	})
 
@@ -316,14 +372,25 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	if ctr.ReadOnly(s.config.ReadOnly) {
 		options = []string{"ro"}
 	}
-	if sb.ResolvPath() != "" {
-		if err := securityLabel(sb.ResolvPath(), mountLabel, false, false); err != nil {
+	resolvPath := sb.ResolvPath()
+	dnsConfig, err := containerDNSConfig(ctr.Config().Annotations)
+	if err != nil {
+		return nil, err
+	}
+	if dnsConfig != nil {
+		resolvPath = filepath.Join(containerInfo.RunDir, "resolv.conf")
+		if err := sandbox.ParseDNSOptions(dnsConfig.Servers, dnsConfig.Searches, dnsConfig.Options, resolvPath); err != nil {
+			return nil, fmt.Errorf("failed to write resolv.conf of container %s: %w", containerID, err)
+		}
+	}
+	if resolvPath != "" {
+		if err := securityLabel(resolvPath, mountLabel, false, false); err != nil {
 			return nil, err
 		}
 		ctr.SpecAddMount(rspec.Mount{
 			Destination: "/etc/resolv.conf",
 			Type:        "bind",
-			Source:      sb.ResolvPath(),
+			Source:      resolvPath,
 			Options:     append(options, []string{"bind", "nodev", "nosuid", "noexec"}...),
 		})
 	}
@@ -366,9 +433,11 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		setOCIBindMountsPrivileged(specgen)
 	}
 
-	// Set hostname and add env for hostname
-	specgen.SetHostname(sb.Hostname())
-	specgen.AddProcessEnv("HOSTNAME", sb.Hostname())
+	// Set hostname, the env for the hostname is added once the image and CRI envs are known.
+	// The hostname of the host UTS namespace must not be changed.
+	if !sb.HostUTS() {
+		specgen.SetHostname(sb.Hostname())
+	}
 
 	created := time.Now()
 	seccompRef := types.SecurityProfile_Unconfined.String()
@@ -397,6 +466,14 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		}
 		if notifier != nil {
 			s.seccompNotifiers.Store(containerID, notifier)
+			defer func() {
+				if retErr != nil {
+					s.seccompNotifiers.Delete(containerID)
+					if err := notifier.Close(); err != nil {
+						log.Errorf(ctx, "Unable to close seccomp notifier: %v", err)
+					}
+				}
+			}()
 		}
 		seccompRef = ref
 	}
@@ -408,8 +485,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	}
 	if rdtClass != "" {
 		log.Debugf(ctx, "Setting RDT ClosID of container %s to %q", containerID, rdt.ResctrlPrefix+rdtClass)
-		// TODO: patch runtime-tools to support setting ClosID via a helper func similar to SetLinuxIntelRdtL3CacheSchema()
-		specgen.Config.Linux.IntelRdt = &rspec.LinuxIntelRdt{ClosID: rdt.ResctrlPrefix + rdtClass}
+		specgen.SetLinuxIntelRdtClosID(rdt.ResctrlPrefix + rdtClass)
 	}
 	// compute the runtime path for a given container
 	platform := containerInfo.Config.Platform.OS + "/" + containerInfo.Config.Platform.Architecture
@@ -417,7 +493,10 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	if err != nil {
 		return nil, err
 	}
-	err = ctr.SpecAddAnnotations(ctx, sb, containerVolumes, containerInfo.RootFs, containerImageConfig.Config.StopSignal, imgResult, s.config.CgroupManager().IsSystemd(), seccompRef, runtimePath)
+	// The stop signal annotation is all a restored container has to go on,
+	// so it carries the default stop signal as well.
+	stopSignal := containerStopSignal(containerImageConfig.Config.StopSignal, &s.config.RuntimeConfig)
+	err = ctr.SpecAddAnnotations(ctx, sb, containerVolumes, containerInfo.RootFs, stopSignal, imgResult, s.config.CgroupManager().IsSystemd(), seccompRef, runtimePath)
 	if err != nil {
 		return nil, err
 	}
@@ -426,15 +505,14 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		return nil, err
 	}
 
-	// First add any configured environment variables from crio config.
-	// They will get overridden if specified in the image or container config.
-	specgen.AddMultipleProcessEnv(s.Config().DefaultEnv)
-
-	// Add environment variables from image the CRI configuration
-	envs := mergeEnvs(containerImageConfig, containerConfig.Envs)
-	for _, e := range envs {
-		parts := strings.SplitN(e, "=", 2)
-		specgen.AddProcessEnv(parts[0], parts[1])
+	handlerEnv, err := s.Runtime().RuntimeDefaultEnv(sb.RuntimeHandler())
+	if err != nil {
+		return nil, err
+	}
+	addProcessEnvs(specgen, s.Config().DefaultEnv, handlerEnv, mergeEnvs(containerImageConfig, containerConfig.Envs))
+	addHostnameEnv(specgen, sb.Hostname())
+	if containerConfig.Tty {
+		addTermEnv(specgen, s.config.DefaultTerm)
 	}
 
 	// Setup user and groups
@@ -445,7 +523,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	}
 
 	// Add image volumes
-	volumeMounts, err := addImageVolumes(ctx, containerInfo.RootFs, s, &containerInfo, mountLabel, specgen)
+	volumeMounts, err := addImageVolumes(ctx, containerInfo.RootFs, s, &containerInfo, mountLabel, specgen, ociMounts)
 	if err != nil {
 		return nil, err
 	}
@@ -480,11 +558,19 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		ctr.DisableFips(),
 	)
 
+	if err := validateFipsAnnotations(sb.Annotations()); err != nil {
+		return nil, err
+	}
 	if ctr.DisableFips() && sb.Annotations()[crioann.DisableFIPSAnnotation] == "true" {
 		if err := disableFipsForContainer(ctr, containerInfo.RunDir); err != nil {
 			return nil, fmt.Errorf("failed to disable FIPS for container %s: %w", containerID, err)
 		}
 	}
+	if sb.Annotations()[crioann.EnableFIPSAnnotation] == "true" {
+		if err := enableFipsForContainer(ctr, containerInfo.RunDir); err != nil {
+			return nil, fmt.Errorf("failed to enable FIPS for container %s: %w", containerID, err)
+		}
+	}
 
 	mounts := []rspec.Mount{}
 	mounts = append(mounts, ociMounts...)
@@ -494,6 +580,10 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	sort.Sort(orderedMounts(mounts))
 
 	for _, m := range mounts {
+		if m.Type == "tmpfs" {
+			ctr.SpecAddMount(m)
+			continue
+		}
 		rspecMount := rspec.Mount{
 			Type:        "bind",
 			Options:     append(m.Options, "bind"),
@@ -541,7 +631,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		Name:    metadata.Name,
 		Attempt: metadata.Attempt,
 	}
-	ociContainer, err := oci.NewContainer(containerID, containerName, containerInfo.RunDir, logPath, labels, crioAnnotations, ctr.Config().Annotations, userRequestedImage, &bundleName, &imageID, someRepoDigest, criMetadata, sb.ID(), containerConfig.Tty, containerConfig.Stdin, containerConfig.StdinOnce, sb.RuntimeHandler(), containerInfo.Dir, created, containerImageConfig.Config.StopSignal)
+	ociContainer, err := oci.NewContainer(containerID, containerName, containerInfo.RunDir, logPath, labels, crioAnnotations, ctr.Config().Annotations, userRequestedImage, &bundleName, &imageID, someRepoDigest, criMetadata, sb.ID(), containerConfig.Tty, containerConfig.Stdin, containerConfig.StdinOnce, sb.RuntimeHandler(), containerInfo.Dir, created, stopSignal)
 	if err != nil {
 		return nil, err
 	}
@@ -554,17 +644,12 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	if err := specgen.RemoveLinuxNamespace(string(rspec.UserNamespace)); err != nil {
 		return nil, err
 	}
-	if v := sb.Annotations()[crioann.UmaskAnnotation]; v != "" {
-		umaskRegexp := regexp.MustCompile(`^[0-7]{1,4}$`)
-		if !umaskRegexp.MatchString(v) {
-			return nil, fmt.Errorf("invalid umask string %s", v)
-		}
-		decVal, err := strconv.ParseUint(sb.Annotations()[crioann.UmaskAnnotation], 8, 32)
-		if err != nil {
-			return nil, err
-		}
-		umask := uint32(decVal)
-		specgen.Config.Process.User.Umask = &umask
+	umask, err := containerUmask(sb.Annotations(), &s.config.RuntimeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if umask != nil {
+		specgen.Config.Process.User.Umask = umask
 	}
 
 	etcPath := filepath.Join(containerInfo.RootFs, "/etc")
@@ -603,7 +688,12 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	// else in some cases, and doing so would resolve an existing mtab path to the symbolic
 	// link target location, for example, the /etc/proc/self/mounts, which breaks container
 	// creation.
-	if err := os.Symlink("/proc/mounts", filepath.Join(etcPath, "mtab")); err != nil && !os.IsExist(err) {
+	//
+	// The symlink can be disabled by the DisableMtabSymlinkAnnotation for images shipping
+	// their own /etc/mtab, which is left untouched in that case.
+	if containerConfig.Annotations[crioann.DisableMtabSymlinkAnnotation] == "true" || sb.Annotations()[crioann.DisableMtabSymlinkAnnotation] == "true" {
+		log.Debugf(ctx, "Skipping /etc/mtab symlink for container %s", containerID)
+	} else if err := os.Symlink("/proc/mounts", filepath.Join(etcPath, "mtab")); err != nil && !os.IsExist(err) {
 		return nil, err
 	}
 
@@ -621,7 +711,9 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		return nil, fmt.Errorf("failed to get runtime handler %q hooks", sb.RuntimeHandler())
 	}
 
-	if err := s.nri.createContainer(ctx, specgen, sb, ociContainer); err != nil {
+	if err := s.runNRIHook(ctx, "CreateContainer", s.config.NRI.CreateContainerFailurePolicy, func(ctx context.Context) error {
+		return s.nri.createContainer(ctx, specgen, sb, ociContainer)
+	}); err != nil {
 		return nil, err
 	}
 
@@ -637,6 +729,11 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		}
 	}
 
+	if containerConfig.Annotations[crioann.DryRunAnnotation] == "true" {
+		// Returning an error rolls back everything done for the container.
+		return nil, writeDryRunSpec(specgen, containerID)
+	}
+
 	if emptyDirVolName, ok := sb.Annotations()[crioann.LinkLogsAnnotation]; ok {
 		if err := linklogs.LinkContainerLogs(ctx, sb.Labels()[kubeletTypes.KubernetesPodUIDLabel], emptyDirVolName, ctr.ID(), containerConfig.Metadata); err != nil {
 			log.Warnf(ctx, "Failed to link container logs: %v", err)
@@ -653,8 +750,10 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	}
 
 	ociContainer.SetSpec(specgen.Config)
+	ociContainer.SetRestore(ctr.Restore())
 	ociContainer.SetMountPoint(containerInfo.RootFs)
 	ociContainer.SetSeccompProfilePath(seccompRef)
+	ociContainer.SetSecurityPosture(newSecurityPosture(specgen.Config, ctr.Privileged(), seccompRef, s.config.DefaultCapabilities))
 	if runtimePath != "" {
 		ociContainer.SetRuntimePathForPlatform(runtimePath)
 	}
@@ -663,6 +762,10 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		ociContainer.AddVolume(cv)
 	}
 
+	if err := s.reviewContainerCreation(ctx, sb, ociContainer); err != nil {
+		return nil, err
+	}
+
 	return ociContainer, nil
 }
 
@@ -683,12 +786,169 @@ func setContainerConfigSecurityContext(containerConfig *types.ContainerConfig) {
 	}
 }
 
+// addHostnameEnv sets the HOSTNAME environment variable to the sandbox
+// hostname, unless it has already been set explicitly, for example by the
+// image or the CRI config.
+func addHostnameEnv(specgen *generate.Generator, hostname string) {
+	if processEnvSet(specgen, "HOSTNAME") {
+		return
+	}
+	specgen.AddProcessEnv("HOSTNAME", hostname)
+}
+
+// addProcessEnvs sets the environment of the container process. The
+// configured global defaults get overridden by the defaults of the runtime
+// handler, which get overridden by the environment variables from the image
+// and the CRI configuration.
+func addProcessEnvs(specgen *generate.Generator, defaultEnv, handlerEnv, envs []string) {
+	specgen.AddMultipleProcessEnv(defaultEnv)
+	specgen.AddMultipleProcessEnv(handlerEnv)
+	for _, e := range envs {
+		parts := strings.SplitN(e, "=", 2)
+		specgen.AddProcessEnv(parts[0], parts[1])
+	}
+}
+
+// addTermEnv sets the TERM environment variable of a container requesting a
+// TTY to the configured default term, unless the image or the CRI already
+// provide one. An empty term disables it.
+func addTermEnv(specgen *generate.Generator, term string) {
+	if term == "" || processEnvSet(specgen, "TERM") {
+		return
+	}
+	specgen.AddProcessEnv("TERM", term)
+}
+
+// processEnvSet returns true if the process environment contains key.
+func processEnvSet(specgen *generate.Generator, key string) bool {
+	if specgen.Config.Process == nil {
+		return false
+	}
+	for _, e := range specgen.Config.Process.Env {
+		if k, _, _ := strings.Cut(e, "="); k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// containerUmask returns the umask of the container init process. The umask
+// annotation of the sandbox takes precedence over the configured default
+// umask. It returns nil if neither is set.
+func containerUmask(sandboxAnnotations map[string]string, runtimeConfig *libconfig.RuntimeConfig) (*uint32, error) {
+	if v := sandboxAnnotations[crioann.UmaskAnnotation]; v != "" {
+		umask, err := libconfig.ParseUmask(v)
+		if err != nil {
+			return nil, err
+		}
+		return &umask, nil
+	}
+	return runtimeConfig.DefaultUmaskValue()
+}
+
+// containerStopSignal returns the stop signal of a container, which is the
+// stop signal declared by its image, or the configured default stop signal if
+// the image declares none.
+func containerStopSignal(imageStopSignal string, runtimeConfig *libconfig.RuntimeConfig) string {
+	if imageStopSignal != "" {
+		return imageStopSignal
+	}
+	return runtimeConfig.DefaultStopSignal
+}
+
+// containerDNSConfig returns the DNS config of the DNSConfigAnnotation of a
+// container, or nil if the container uses the resolv.conf of its pod.
+func containerDNSConfig(containerAnnotations map[string]string) (*types.DNSConfig, error) {
+	const annotation = crioann.DNSConfigAnnotation
+	value, ok := containerAnnotations[annotation]
+	if !ok {
+		return nil, nil
+	}
+	dnsConfig := &types.DNSConfig{}
+	if err := json.Unmarshal([]byte(value), dnsConfig); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation %q: %w", annotation, value, err)
+	}
+	if len(dnsConfig.Servers) == 0 && len(dnsConfig.Searches) == 0 && len(dnsConfig.Options) == 0 {
+		return nil, fmt.Errorf("invalid %s annotation %q: no servers, searches or options", annotation, value)
+	}
+	// Every entry ends up on a line of the resolv.conf, so a line break
+	// would smuggle in other directives.
+	for _, entry := range slices.Concat(dnsConfig.Servers, dnsConfig.Searches, dnsConfig.Options) {
+		if strings.ContainsAny(entry, "\r\n") {
+			return nil, fmt.Errorf("invalid %s annotation %q: entry %q contains a line break", annotation, value, entry)
+		}
+	}
+	return dnsConfig, nil
+}
+
+// validateNamespaceTarget checks that the container targetCtr, looked up for
+// the namespace target ID target, exists and is running, so that its
+// namespaces can be joined.
+func validateNamespaceTarget(target string, targetCtr *oci.Container) error {
+	if targetCtr == nil {
+		return fmt.Errorf("namespace target container %s not found", target)
+	}
+	if status := targetCtr.State().Status; status != oci.ContainerStateRunning {
+		return fmt.Errorf("namespace target container %s is not running: %s", target, status)
+	}
+	return nil
+}
+
+// containerPrivateShm returns whether a container requested a dedicated shm
+// through the PrivateShmAnnotation, either on the container or on the sandbox
+// suffixed with the container name, for example
+// "io.kubernetes.cri-o.PrivateShm.$CTR_NAME".
+func containerPrivateShm(sandboxAnnotations, containerAnnotations map[string]string, containerName string) bool {
+	return containerAnnotations[crioann.PrivateShmAnnotation] == "true" ||
+		sandboxAnnotations[crioann.PrivateShmAnnotation+"."+containerName] == "true"
+}
+
+// containerShmSize returns the size of the dedicated shm requested for a
+// container through the ShmSizeAnnotation suffixed with the container name, for
+// example "io.kubernetes.cri-o.ShmSize.$CTR_NAME". A size of zero means that
+// the container uses the shm of the sandbox.
+func containerShmSize(sandboxAnnotations map[string]string, containerName string) (int64, error) {
+	shmSizeStr, ok := sandboxAnnotations[crioann.ShmSizeAnnotation+"."+containerName]
+	if !ok {
+		return 0, nil
+	}
+	quantity, err := resource.ParseQuantity(shmSizeStr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse shm size '%s' of container %s: %w", shmSizeStr, containerName, err)
+	}
+	if quantity.Value() <= 0 {
+		return 0, fmt.Errorf("shm size '%s' of container %s must be greater than 0", shmSizeStr, containerName)
+	}
+	return quantity.Value(), nil
+}
+
+// validateFipsAnnotations returns an error if the sandbox annotations request
+// to both enable and disable FIPS mode.
+func validateFipsAnnotations(sandboxAnnotations map[string]string) error {
+	if sandboxAnnotations[crioann.EnableFIPSAnnotation] == "true" && sandboxAnnotations[crioann.DisableFIPSAnnotation] == "true" {
+		return fmt.Errorf("annotations %s and %s are mutually exclusive", crioann.EnableFIPSAnnotation, crioann.DisableFIPSAnnotation)
+	}
+	return nil
+}
+
 func disableFipsForContainer(ctr ctrfactory.Container, containerDir string) error {
+	// Write the value '0' to disable FIPS.
+	return setFipsForContainer(ctr, containerDir, "0")
+}
+
+func enableFipsForContainer(ctr ctrfactory.Container, containerDir string) error {
+	// Write the value '1' to enable FIPS.
+	return setFipsForContainer(ctr, containerDir, "1")
+}
+
+// setFipsForContainer bind mounts a file holding value over the fips_enabled
+// sysctl of the container.
+func setFipsForContainer(ctr ctrfactory.Container, containerDir, value string) error {
 	// Create a unique filename for the FIPS setting file.
 	fileName := filepath.Join(containerDir, "sysctl-fips")
-	content := []byte("0\n")
+	content := []byte(value + "\n")
 
-	// Write the value '0' to disable FIPS directly to the file.
+	// Write the value directly to the file.
 	if err := os.WriteFile(fileName, content, 0o444); err != nil {
 		return fmt.Errorf("failed to write to file: %w", err)
 	}
@@ -760,7 +1020,7 @@ func clearReadOnly(m *rspec.Mount) {
 	m.Options = append(m.Options, "rw")
 }
 
-func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container, mountLabel, bindMountPrefix string, absentMountSourcesToReject []string, maybeRelabel, skipRelabel, cgroup2RW, idMapSupport, rroSupport bool, storageRoot string) ([]oci.ContainerVolume, []rspec.Mount, error) {
+func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container, mountLabel, bindMountPrefix string, absentMountSourcesToReject []string, maybeRelabel, skipRelabel, cgroup2RW, idMapSupport, rroSupport bool, storageRoot string, resourceCleaner *resourcestore.ResourceCleaner) (_ []oci.ContainerVolume, _ []rspec.Mount, retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 
@@ -810,22 +1070,64 @@ func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container,
 		return nil, nil, fmt.Errorf("ensure image volumes path: %w", err)
 	}
 
-	for _, m := range mounts {
+	if imageVolumesPath != "" {
+		// The copy-on-write directories are created by the image mounts
+		// below, possibly concurrently, so all of them get removed at once.
+		resourceCleaner.AddResource(ctx, "image mounts copy-on-write directory", ctr.ID(), "createCtr: removing copy-on-write image mounts of container "+ctr.ID(), func() error {
+			s.removeImageMountsCOW(ctx, ctr.ID())
+			return nil
+		})
+	}
+	imageMounts, err := mountImages(mounts, func(m *types.Mount) (*imageMount, error) {
+		return s.mountImage(ctx, imageVolumesPath, ctr.ID(), m)
+	}, func(im *imageMount) {
+		s.unmountImage(ctx, im)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("mount image: %w", err)
+	}
+	for _, im := range imageMounts {
+		resourceCleaner.AddResource(ctx, "image mount", im.volume.Image.Image, "createCtr: unmounting image "+im.volume.Image.Image, func() error {
+			s.unmountImage(ctx, im)
+			return nil
+		})
+	}
+
+	// The resolved sources are cached for this container only, so identical
+	// host paths are resolved once.
+	resolvedSources := make(map[string]string)
+
+	// The mount sources to relabel are collected in the mount loop and
+	// relabeled once all mounts are known.
+	relabelSources := []string{}
+
+	for i, m := range mounts {
 		dest := m.ContainerPath
 		if dest == "" {
 			return nil, nil, errors.New("mount.ContainerPath is empty")
 		}
-		if m.Image != nil && m.Image.Image != "" {
-			volume, err := s.mountImage(ctx, specgen, imageVolumesPath, m)
-			if err != nil {
-				return nil, nil, fmt.Errorf("mount image: %w", err)
-			}
-			volumes = append(volumes, *volume)
+		if im, ok := imageMounts[i]; ok {
+			specgen.AddMount(im.mount)
+			log.Debugf(ctx, "Added overlay mount from %s to %s", im.volume.HostPath, dest)
+			volumes = append(volumes, im.volume)
 			continue
 		}
 		if m.HostPath == "" {
 			return nil, nil, errors.New("mount.HostPath is empty")
 		}
+		if strings.HasPrefix(m.HostPath, tmpfsMountPrefix) {
+			tmpfsMount, err := tmpfsMountFromCRI(m)
+			if err != nil {
+				return nil, nil, err
+			}
+			volumes = append(volumes, oci.ContainerVolume{
+				ContainerPath: dest,
+				HostPath:      m.HostPath,
+				Readonly:      m.Readonly,
+			})
+			ociMounts = append(ociMounts, *tmpfsMount)
+			continue
+		}
 		if m.HostPath == "/" && dest == "/" {
 			log.Warnf(ctx, "Configuration specifies mounting host root to the container root.  This is dangerous (especially with privileged containers) and should be avoided.")
 		}
@@ -835,34 +1137,28 @@ func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container,
 			m.Propagation = types.MountPropagation_PROPAGATION_HOST_TO_CONTAINER
 		}
 
-		src := filepath.Join(bindMountPrefix, m.HostPath)
+		// Check the requested and the fully resolved source before anything
+		// gets created, so that symlinks in any component of the path cannot
+		// point to denied host paths.
+		if err := checkMountSource(filepath.Clean(m.HostPath), s.config.AllowedMountSources, s.config.DeniedMountSources); err != nil {
+			return nil, nil, err
+		}
+		resolvedHostPath, err := resolveMountSourcePath(bindMountPrefix, m.HostPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := checkMountSource(resolvedHostPath, s.config.AllowedMountSources, s.config.DeniedMountSources); err != nil {
+			return nil, nil, err
+		}
 
-		resolvedSrc, err := resolveSymbolicLink(bindMountPrefix, src)
-		if err == nil {
-			src = resolvedSrc
-		} else {
-			if !os.IsNotExist(err) {
-				return nil, nil, fmt.Errorf("failed to resolve symlink %q: %w", src, err)
-			}
-			for _, toReject := range absentMountSourcesToReject {
-				if filepath.Clean(src) == toReject {
-					// special-case /etc/hostname, as we don't want it to be created as a directory
-					// This can cause issues with node reboot.
-					return nil, nil, fmt.Errorf("cannot mount %s: path does not exist and will cause issues as a directory", toReject)
-				}
-			}
-			if !ctr.Restore() {
-				// Although this would also be really helpful for restoring containers
-				// it is problematic as during restore external bind mounts need to be
-				// a file if the destination is a file. Unfortunately it is not easy
-				// to tell if the destination is a file or a directory. Especially if
-				// the destination is a nested bind mount. For now we will just not
-				// create the missing bind mount source for restore and return an
-				// error to the user.
-				if err = os.MkdirAll(src, 0o755); err != nil {
-					return nil, nil, fmt.Errorf("failed to mkdir %s: %w", src, err)
-				}
+		hostSrc := filepath.Join(bindMountPrefix, m.HostPath)
+		src, ok := resolvedSources[hostSrc]
+		if !ok {
+			src, err = resolveBindMountSource(bindMountPrefix, hostSrc, absentMountSourcesToReject, ctr.Restore())
+			if err != nil {
+				return nil, nil, err
 			}
+			resolvedSources[hostSrc] = src
 		}
 
 		options := []string{"rbind"}
@@ -900,6 +1196,9 @@ func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container,
 		// Recursive Read-only (RRO) support requires the mount to be
 		// read-only and the mount propagation set to private.
 		switch {
+		case m.RecursiveReadOnly && m.Readonly && !rroSupport && s.config.RROMountsFallbackToReadOnly:
+			log.Warnf(ctx, "Recursive read-only mount support is not available, mounting hostPath %q read-only instead", m.HostPath)
+			options = append(options, "ro")
 		case m.RecursiveReadOnly && m.Readonly:
 			if !rroSupport {
 				return nil, nil, fmt.Errorf(
@@ -928,8 +1227,8 @@ func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container,
 		if m.SelinuxRelabel {
 			if skipRelabel {
 				log.Debugf(ctx, "Skipping relabel for %s because of super privileged container (type: spc_t)", src)
-			} else if err := securityLabel(src, mountLabel, false, maybeRelabel); err != nil {
-				return nil, nil, err
+			} else if !slices.Contains(relabelSources, src) {
+				relabelSources = append(relabelSources, src)
 			}
 		} else {
 			log.Debugf(ctx, "Skipping relabel for %s because kubelet did not request it", src)
@@ -959,6 +1258,12 @@ func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container,
 		})
 	}
 
+	if err := relabelMountSources(relabelSources, s.config.RelabelWorkers, func(src string) error {
+		return securityLabel(src, mountLabel, false, maybeRelabel)
+	}); err != nil {
+		return nil, nil, err
+	}
+
 	if _, mountSys := mountSet["/sys"]; !mountSys {
 		m := rspec.Mount{
 			Destination: cgroupSysFsPath,
@@ -978,8 +1283,235 @@ func (s *Server) addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container,
 	return volumes, ociMounts, nil
 }
 
-// mountImage adds required image mounts to the provided spec generator and returns a corresponding ContainerVolume.
-func (s *Server) mountImage(ctx context.Context, specgen *generate.Generator, imageVolumesPath string, m *types.Mount) (*oci.ContainerVolume, error) {
+// relabelMountSources relabels the mount sources with at most workers relabels
+// running in parallel, or one after another if workers is zero. All sources
+// get relabeled even if some relabels fail, and the returned error aggregates
+// the errors of all failed relabels.
+func relabelMountSources(sources []string, workers int, relabel func(src string) error) error {
+	relabels := &errgroup.Group{}
+	relabels.SetLimit(max(workers, 1))
+	errs := make([]error, len(sources))
+	for i, src := range sources {
+		relabels.Go(func() error {
+			errs[i] = relabel(src)
+			return nil
+		})
+	}
+	relabels.Wait() //nolint:errcheck // the errors are collected in errs
+	return utilerrors.NewAggregate(errs)
+}
+
+// ErrMountSourceNotAllowed is returned for bind mounts whose host path is
+// denied by denied_mount_sources or not allowed by allowed_mount_sources.
+var ErrMountSourceNotAllowed = errors.New("mount source not allowed")
+
+// checkMountSource returns an error wrapping ErrMountSourceNotAllowed if the
+// host path path is one of the denied paths, below or above one of them, or
+// not below any of the allowed paths if there are any. Mounting an ancestor
+// of a denied path would expose the denied path as well.
+func checkMountSource(path string, allowed, denied []string) error {
+	for _, entry := range denied {
+		for _, prefix := range mountSourceEntryPaths(entry) {
+			if isSubDirectoryOf(path, prefix) || isSubDirectoryOf(prefix, path) {
+				return fmt.Errorf("%w: %s is denied by denied_mount_sources entry %s", ErrMountSourceNotAllowed, path, entry)
+			}
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, entry := range allowed {
+		for _, prefix := range mountSourceEntryPaths(entry) {
+			if isSubDirectoryOf(path, prefix) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s is not below any allowed_mount_sources entry (%s)", ErrMountSourceNotAllowed, path, strings.Join(allowed, ", "))
+}
+
+// mountSourceEntryPaths returns a denied_mount_sources or
+// allowed_mount_sources entry together with its symlink resolved path, because
+// mount sources get checked after resolving their symlinks as well. For
+// example, an entry "/var/run/secrets" also covers "/run/secrets" if /var/run
+// links to /run.
+func mountSourceEntryPaths(entry string) []string {
+	resolved, err := filepath.EvalSymlinks(entry)
+	if err != nil || resolved == entry {
+		return []string{entry}
+	}
+	return []string{entry, resolved}
+}
+
+// resolveMountSourcePath resolves all symlinks of the host path path within
+// bindMountPrefix, including the ones in parent directories, and returns the
+// result relative to bindMountPrefix. Missing trailing components are kept
+// as they are, since they may still be created.
+func resolveMountSourcePath(bindMountPrefix, path string) (string, error) {
+	scope := bindMountPrefix
+	if scope == "" {
+		scope = "/"
+	}
+	resolved, err := securejoin.SecureJoinVFS(scope, path, &symlinkLimitVFS{})
+	if errors.Is(err, ErrTooManySymlinks) {
+		return "", fmt.Errorf("%w: resolving %s followed more than %d symlinks", ErrTooManySymlinks, path, maxSymlinkDepth)
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve mount source %s: %w", path, err)
+	}
+	return filepath.Join("/", strings.TrimPrefix(resolved, bindMountPrefix)), nil
+}
+
+// resolveBindMountSource resolves a possible symlink of a bind mount source
+// and creates the source directory if it does not exist yet.
+func resolveBindMountSource(bindMountPrefix, src string, absentMountSourcesToReject []string, restore bool) (string, error) {
+	resolvedSrc, err := resolveSymbolicLink(bindMountPrefix, src)
+	if err == nil {
+		return resolvedSrc, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to resolve symlink %q: %w", src, err)
+	}
+	for _, toReject := range absentMountSourcesToReject {
+		if filepath.Clean(src) == toReject {
+			// special-case /etc/hostname, as we don't want it to be created as a directory
+			// This can cause issues with node reboot.
+			return "", fmt.Errorf("cannot mount %s: path does not exist and will cause issues as a directory", toReject)
+		}
+	}
+	if !restore {
+		// Although this would also be really helpful for restoring containers
+		// it is problematic as during restore external bind mounts need to be
+		// a file if the destination is a file. Unfortunately it is not easy
+		// to tell if the destination is a file or a directory. Especially if
+		// the destination is a nested bind mount. For now we will just not
+		// create the missing bind mount source for restore and return an
+		// error to the user.
+		if err := os.MkdirAll(src, 0o755); err != nil {
+			return "", fmt.Errorf("failed to mkdir %s: %w", src, err)
+		}
+	}
+	return src, nil
+}
+
+// tmpfsMountFromCRI converts a CRI mount requesting a tmpfs into an OCI mount.
+// A tmpfs is requested by setting the host path of the mount to
+// tmpfsMountPrefix, optionally followed by a comma separated list of
+// "size=<quantity>" and "mode=<octal>" options, for example
+// "tmpfs:size=64Mi,mode=1777". Host paths are always absolute, so the prefix
+// cannot clash with a real host path. Propagation and SELinux relabeling do
+// not apply to a tmpfs, the OCI runtime applies the mount label on its own.
+func tmpfsMountFromCRI(m *types.Mount) (*rspec.Mount, error) {
+	if m.RecursiveReadOnly {
+		return nil, fmt.Errorf("recursive read-only mount is not supported for tmpfs mount %q", m.ContainerPath)
+	}
+	if len(m.UidMappings) > 0 || len(m.GidMappings) > 0 {
+		return nil, fmt.Errorf("idmap mounts are not supported for tmpfs mount %q", m.ContainerPath)
+	}
+
+	options := []string{"nosuid", "nodev"}
+	if opts := strings.TrimPrefix(m.HostPath, tmpfsMountPrefix); opts != "" {
+		for _, opt := range strings.Split(opts, ",") {
+			key, value, _ := strings.Cut(opt, "=")
+			switch key {
+			case "size":
+				quantity, err := resource.ParseQuantity(value)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse tmpfs size %q for mount %q: %w", value, m.ContainerPath, err)
+				}
+				if quantity.Value() <= 0 {
+					return nil, fmt.Errorf("tmpfs size %q for mount %q must be greater than 0", value, m.ContainerPath)
+				}
+				options = append(options, "size="+strconv.FormatInt(quantity.Value(), 10))
+			case "mode":
+				if _, err := strconv.ParseUint(value, 8, 32); err != nil {
+					return nil, fmt.Errorf("invalid tmpfs mode %q for mount %q: %w", value, m.ContainerPath, err)
+				}
+				options = append(options, "mode="+value)
+			default:
+				return nil, fmt.Errorf("unsupported tmpfs option %q for mount %q", opt, m.ContainerPath)
+			}
+		}
+	}
+
+	if m.Readonly {
+		options = append(options, "ro")
+	} else {
+		options = append(options, "rw")
+	}
+
+	return &rspec.Mount{
+		Destination: m.ContainerPath,
+		Type:        "tmpfs",
+		Source:      "tmpfs",
+		Options:     options,
+	}, nil
+}
+
+// maxParallelImageMounts limits the number of images mounted concurrently for
+// a single container.
+const maxParallelImageMounts = 4
+
+// imageMount is a mounted image, with the overlay mount to add to the spec
+// and the corresponding ContainerVolume.
+type imageMount struct {
+	mount  rspec.Mount
+	volume oci.ContainerVolume
+}
+
+// mountImages mounts the image mounts among mounts concurrently, using at
+// most maxParallelImageMounts workers. The mounted images are returned by
+// their index in mounts, so that the caller can add them in mount order. If
+// an image fails to mount, the already mounted images are unmounted again.
+func mountImages(mounts []*types.Mount, mountFn func(*types.Mount) (*imageMount, error), unmountFn func(*imageMount)) (map[int]*imageMount, error) {
+	results := make([]*imageMount, len(mounts))
+	group := &errgroup.Group{}
+	group.SetLimit(maxParallelImageMounts)
+	for i, m := range mounts {
+		if m.Image == nil || m.Image.Image == "" {
+			continue
+		}
+		group.Go(func() error {
+			im, err := mountFn(m)
+			if err != nil {
+				return err
+			}
+			results[i] = im
+			return nil
+		})
+	}
+	err := group.Wait()
+
+	imageMounts := make(map[int]*imageMount)
+	for i, im := range results {
+		if im == nil {
+			continue
+		}
+		if err != nil {
+			unmountFn(im)
+			continue
+		}
+		imageMounts[i] = im
+	}
+	if err != nil {
+		return nil, err
+	}
+	return imageMounts, nil
+}
+
+// unmountImage unmounts an image mounted by mountImage.
+func (s *Server) unmountImage(ctx context.Context, im *imageMount) {
+	if _, err := s.StorageService().UnmountImage(im.volume.Image.Image, false); err != nil {
+		log.Warnf(ctx, "Unable to unmount image %s: %v", im.volume.Image.Image, err)
+	}
+}
+
+// mountImage mounts the image of an image mount and returns the overlay mount
+// for the spec with the corresponding ContainerVolume.
+// If image_mounts_copy_on_write is set, read-write image mounts get a per
+// container upper and work directory, so that the container can modify the
+// image contents without affecting the shared image.
+func (s *Server) mountImage(ctx context.Context, imageVolumesPath, ctrID string, m *types.Mount) (*imageMount, error) {
 	if m == nil || m.Image == nil || m.Image.Image == "" || m.ContainerPath == "" {
 		return nil, fmt.Errorf("invalid mount specified: %+v", m)
 	}
@@ -1005,30 +1537,82 @@ func (s *Server) mountImage(ctx context.Context, specgen *generate.Generator, im
 	}
 	log.Infof(ctx, "Image mounted to: %s", mountPoint)
 
+	overlayOptions := []string{
+		imageMountLowerdir(s.config.ImageMountLowerdirOrder, mountPoint, imageVolumesPath),
+	}
+	copyOnWrite := s.imageMountCopyOnWrite(m)
+	if copyOnWrite {
+		cowOptions, err := imageMountCOWOptions(s.imageMountsCOWPath(ctrID))
+		if err != nil {
+			if _, unmountErr := s.StorageService().UnmountImage(imageID, false); unmountErr != nil {
+				log.Warnf(ctx, "Unable to unmount image %s: %v", imageID, unmountErr)
+			}
+			return nil, fmt.Errorf("setup copy-on-write image mount: %w", err)
+		}
+		overlayOptions = append(overlayOptions, cowOptions...)
+	}
+
 	const overlay = "overlay"
-	specgen.AddMount(rspec.Mount{
-		Type:        overlay,
-		Source:      overlay,
-		Destination: m.ContainerPath,
-		Options: []string{
-			"lowerdir=" + mountPoint + ":" + imageVolumesPath,
+	return &imageMount{
+		mount: rspec.Mount{
+			Type:        overlay,
+			Source:      overlay,
+			Destination: m.ContainerPath,
+			Options:     overlayOptions,
+			UIDMappings: getOCIMappings(m.UidMappings),
+			GIDMappings: getOCIMappings(m.GidMappings),
+		},
+		volume: oci.ContainerVolume{
+			ContainerPath:     m.ContainerPath,
+			HostPath:          mountPoint,
+			Readonly:          m.Readonly,
+			RecursiveReadOnly: m.RecursiveReadOnly,
+			Propagation:       m.Propagation,
+			SelinuxRelabel:    m.SelinuxRelabel,
+			Image:             &types.ImageSpec{Image: imageID},
+			CopyOnWrite:       copyOnWrite,
 		},
-		UIDMappings: getOCIMappings(m.UidMappings),
-		GIDMappings: getOCIMappings(m.GidMappings),
-	})
-	log.Debugf(ctx, "Added overlay mount from %s to %s", mountPoint, imageVolumesPath)
-
-	return &oci.ContainerVolume{
-		ContainerPath:     m.ContainerPath,
-		HostPath:          mountPoint,
-		Readonly:          m.Readonly,
-		RecursiveReadOnly: m.RecursiveReadOnly,
-		Propagation:       m.Propagation,
-		SelinuxRelabel:    m.SelinuxRelabel,
-		Image:             &types.ImageSpec{Image: imageID},
 	}, nil
 }
 
+// imageMountCopyOnWrite returns whether an image mount is writable through a
+// copy-on-write overlay. Otherwise the image is mounted read-only.
+func (s *Server) imageMountCopyOnWrite(m *types.Mount) bool {
+	return s.config.ImageMountsCopyOnWrite && !m.Readonly
+}
+
+// imageMountsCOWPath returns the directory holding the upper and work
+// directories of the read-write image mounts of a container. It is a sibling
+// of the image volumes directory, which has to stay empty.
+func (s *Server) imageMountsCOWPath(ctrID string) string {
+	imageVolumesDir := filepath.Clean(s.config.ImageVolumesDir)
+	return filepath.Join(filepath.Dir(imageVolumesDir), filepath.Base(imageVolumesDir)+"-cow", ctrID)
+}
+
+// imageMountCOWOptions creates a new upper and work directory below cowPath
+// and returns the corresponding overlay options.
+func imageMountCOWOptions(cowPath string) ([]string, error) {
+	dir := filepath.Join(cowPath, stringid.GenerateNonCryptoID())
+	upperDir := filepath.Join(dir, "upper")
+	workDir := filepath.Join(dir, "work")
+	for _, d := range []string{upperDir, workDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return nil, fmt.Errorf("create %s: %w", d, err)
+		}
+	}
+	return []string{"upperdir=" + upperDir, "workdir=" + workDir}, nil
+}
+
+// imageMountLowerdir returns the overlay lowerdir option for an image mount.
+// The leftmost lower directory takes precedence in overlay, so the order
+// decides whether the image or the scratch directory wins on conflicts.
+func imageMountLowerdir(order libconfig.ImageMountLowerdirOrderType, mountPoint, imageVolumesPath string) string {
+	if order == libconfig.ImageMountLowerdirScratchFirst {
+		return "lowerdir=" + imageVolumesPath + ":" + mountPoint
+	}
+	return "lowerdir=" + mountPoint + ":" + imageVolumesPath
+}
+
 func (s *Server) ensureImageVolumesPath(ctx context.Context, mounts []*types.Mount) (string, error) {
 	// Check if we need to anything at all
 	noop := true
@@ -1043,7 +1627,7 @@ func (s *Server) ensureImageVolumesPath(ctx context.Context, mounts []*types.Mou
 		return "", nil
 	}
 
-	imageVolumesPath := filepath.Join(filepath.Dir(s.Config().ContainerExitsDir), "image-volumes")
+	imageVolumesPath := s.config.ImageVolumesDir
 	log.Debugf(ctx, "Using image volumes path: %s", imageVolumesPath)
 
 	if err := os.MkdirAll(imageVolumesPath, 0o700); err != nil {
@@ -1054,10 +1638,11 @@ func (s *Server) ensureImageVolumesPath(ctx context.Context, mounts []*types.Mou
 	if err != nil {
 		return "", fmt.Errorf("open image volumes path %s: %w", imageVolumesPath, err)
 	}
+	defer f.Close()
 
 	_, readErr := f.ReadDir(1)
 	if readErr != nil && !errors.Is(readErr, io.EOF) {
-		return "", fmt.Errorf("unable to read dir names of image volumes path %s: %w", imageVolumesPath, err)
+		return "", fmt.Errorf("unable to read dir names of image volumes path %s: %w", imageVolumesPath, readErr)
 	}
 	if readErr == nil {
 		return "", fmt.Errorf("image volumes path %s is not empty", imageVolumesPath)
@@ -1081,16 +1666,6 @@ func getOCIMappings(m []*types.IDMapping) []rspec.LinuxIDMapping {
 	return ids
 }
 
-// mountExists returns true if dest exists in the list of mounts.
-func mountExists(specMounts []rspec.Mount, dest string) bool {
-	for _, m := range specMounts {
-		if m.Destination == dest {
-			return true
-		}
-	}
-	return false
-}
-
 // systemd expects to have /run, /run/lock and /tmp on tmpfs
 // It also expects to be able to write to /sys/fs/cgroup/systemd and /var/log/journal.
 func setupSystemd(mounts []rspec.Mount, g generate.Generator) {
@@ -1247,6 +1822,9 @@ func (s *Server) specSetApparmorProfile(ctx context.Context, specgen *generate.G
 		if err != nil {
 			return fmt.Errorf("applying apparmor profile to container %s: %w", ctr.ID(), err)
 		}
+		if err := s.Config().AppArmor().CheckLoaded(profile); err != nil {
+			return err
+		}
 
 		log.Debugf(ctx, "Applied AppArmor profile %s to container %s", profile, ctr.ID())
 		specgen.SetProcessApparmorProfile(profile)
@@ -1259,7 +1837,7 @@ func (s *Server) specSetBlockioClass(specgen *generate.Generator, containerName
 	if s.Config().BlockIO().Enabled() {
 		if blockioClass, err := blockio.ContainerClassFromAnnotations(containerName, containerAnnotations, sandboxAnnotations); blockioClass != "" && err == nil {
 			if s.Config().BlockIO().ReloadRequired() {
-				if err := s.Config().BlockIO().Reload(); err != nil {
+				if err := s.Config().BlockIO().ReloadDebounced(); err != nil {
 					return err
 				}
 			}
@@ -2,11 +2,35 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/L-F-Z/TaskC/pkg/bundle"
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	json "github.com/json-iterator/go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/L-F-Z/cri-t/internal/factory/container"
+	"github.com/L-F-Z/cri-t/internal/lib"
+	"github.com/L-F-Z/cri-t/internal/naming"
+	"github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/internal/resourcestore"
+	"github.com/L-F-Z/cri-t/internal/storage"
+	crioann "github.com/L-F-Z/cri-t/pkg/annotations"
+	libconfig "github.com/L-F-Z/cri-t/pkg/config"
 )
 
 func TestAddOCIBindsForDev(t *testing.T) {
@@ -33,7 +57,7 @@ func TestAddOCIBindsForDev(t *testing.T) {
 	}
 
 	sut := &Server{}
-	_, binds, err := sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "")
+	_, binds, err := sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
 	if err != nil {
 		t.Error(err)
 	}
@@ -78,7 +102,7 @@ func TestAddOCIBindsForSys(t *testing.T) {
 	}
 
 	sut := &Server{}
-	_, binds, err := sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "")
+	_, binds, err := sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
 	if err != nil {
 		t.Error(err)
 	}
@@ -128,7 +152,7 @@ func TestAddOCIBindsRROMounts(t *testing.T) {
 	ctx := context.TODO()
 
 	sut := &Server{}
-	_, binds, err := sut.addOCIBindMounts(ctx, ctr, "", "", nil, false, false, false, false, true, "")
+	_, binds, err := sut.addOCIBindMounts(ctx, ctr, "", "", nil, false, false, false, false, true, "", resourcestore.NewResourceCleaner())
 	if err != nil {
 		t.Errorf("Should not fail to create RRO mount, got: %v", err)
 	}
@@ -149,6 +173,61 @@ func TestAddOCIBindsRROMounts(t *testing.T) {
 	}
 }
 
+func TestAddOCIBindsRROMountsFallback(t *testing.T) {
+	t.Parallel()
+
+	const hostPath = "/mnt"
+
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatalf("Should create a container, got: %v", err)
+	}
+
+	err = ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{
+				HostPath:          hostPath,
+				ContainerPath:     "/host",
+				Readonly:          true,
+				RecursiveReadOnly: true,
+				Propagation:       types.MountPropagation_PROPAGATION_PRIVATE,
+			},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "test-container",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "test-pod",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Should set container configuration, got: %v", err)
+	}
+
+	sut := &Server{}
+	if _, _, err := sut.addOCIBindMounts(context.TODO(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner()); err == nil {
+		t.Error("Should fail to add an RRO mount without runtime support")
+	}
+
+	sut.config.RROMountsFallbackToReadOnly = true
+	_, binds, err := sut.addOCIBindMounts(context.TODO(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if err != nil {
+		t.Fatalf("Should fall back to a read-only mount, got: %v", err)
+	}
+
+	for _, m := range binds {
+		if m.Source != hostPath {
+			continue
+		}
+		if !slices.Contains(m.Options, "ro") || slices.Contains(m.Options, "rro") {
+			t.Errorf("Should add a read-only mount, got: %#v", m.Options)
+		}
+		return
+	}
+	t.Errorf("Should add a mount for %s, got: %#v", hostPath, binds)
+}
+
 func TestAddOCIBindsRROMountsError(t *testing.T) {
 	t.Parallel()
 
@@ -224,7 +303,7 @@ func TestAddOCIBindsRROMountsError(t *testing.T) {
 			}
 
 			sut := &Server{}
-			_, _, err = sut.addOCIBindMounts(ctx, ctr, "", "", nil, false, false, false, false, tc.rroSupport, "")
+			_, _, err = sut.addOCIBindMounts(ctx, ctr, "", "", nil, false, false, false, false, tc.rroSupport, "", resourcestore.NewResourceCleaner())
 			if err == nil {
 				t.Error("Should fail to add an RRO mount with a specific error")
 			}
@@ -254,7 +333,7 @@ func TestAddOCIBindsCGroupRW(t *testing.T) {
 		t.Error(err)
 	}
 	sut := &Server{}
-	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, true, false, false, "")
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, true, false, false, "", resourcestore.NewResourceCleaner())
 	if err != nil {
 		t.Error(err)
 	}
@@ -288,7 +367,7 @@ func TestAddOCIBindsCGroupRW(t *testing.T) {
 		t.Error(err)
 	}
 	var hasCgroupRO bool
-	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "")
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
 	if err != nil {
 		t.Error(err)
 	}
@@ -337,38 +416,1327 @@ func TestAddOCIBindsErrorWithoutIDMap(t *testing.T) {
 		t.Fatal(err)
 	}
 	sut := &Server{}
-	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "")
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
 	if err == nil {
 		t.Errorf("Should have failed to create id mapped mount with no id map support")
 	}
 
-	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, true, false, "")
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, true, false, "", resourcestore.NewResourceCleaner())
 	if err != nil {
 		t.Errorf("%v", err)
 	}
 }
 
-func TestIsSubDirectoryOf(t *testing.T) {
+func TestAddOCIBindsTmpfs(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Error(err)
+	}
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{
+				ContainerPath:  "/scratch",
+				HostPath:       "tmpfs:size=64Mi,mode=1777",
+				Readonly:       true,
+				SelinuxRelabel: true,
+				Propagation:    types.MountPropagation_PROPAGATION_BIDIRECTIONAL,
+			},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Error(err)
+	}
+
+	sut := &Server{}
+	_, binds, err := sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binds) != 1 {
+		t.Fatalf("expected a single mount, got %d", len(binds))
+	}
+	if binds[0].Type != "tmpfs" || binds[0].Source != "tmpfs" || binds[0].Destination != "/scratch" {
+		t.Errorf("unexpected tmpfs mount: %+v", binds[0])
+	}
+	want := []string{"nosuid", "nodev", "size=67108864", "mode=1777", "ro"}
+	if strings.Join(binds[0].Options, ",") != strings.Join(want, ",") {
+		t.Errorf("got options %v, want %v", binds[0].Options, want)
+	}
+	if ctr.Spec().Config.Linux.RootfsPropagation != "" {
+		t.Errorf("tmpfs mount should not change the rootfs propagation")
+	}
+}
+
+func TestAddOCIBindsParallelRelabel(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mounts := []*types.Mount{}
+	for _, dir := range []string{"a", "b", "c"} {
+		mounts = append(mounts, &types.Mount{
+			ContainerPath:  "/" + dir,
+			HostPath:       t.TempDir(),
+			SelinuxRelabel: true,
+			Propagation:    types.MountPropagation_PROPAGATION_PRIVATE,
+		})
+	}
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: mounts,
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	sut.config.RelabelWorkers = 2
+	_, binds, err := sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binds) != len(mounts) {
+		t.Errorf("expected %d mounts, got %d", len(mounts), len(binds))
+	}
+}
+
+func TestAddOCIBindsDuplicateHostPaths(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostPath := filepath.Join(t.TempDir(), "missing")
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{ContainerPath: "/a", HostPath: hostPath, Propagation: types.MountPropagation_PROPAGATION_PRIVATE},
+			{ContainerPath: "/b", HostPath: hostPath, Propagation: types.MountPropagation_PROPAGATION_PRIVATE},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	_, binds, err := sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binds) != 2 {
+		t.Fatalf("expected 2 mounts, got %d", len(binds))
+	}
+	for _, b := range binds {
+		if b.Source != hostPath {
+			t.Errorf("got source %s, want %s", b.Source, hostPath)
+		}
+	}
+	if _, err := os.Stat(hostPath); err != nil {
+		t.Errorf("expected the missing source to be created: %v", err)
+	}
+}
+
+func TestTmpfsMountFromCRIError(t *testing.T) {
 	tests := []struct {
-		base, target string
-		want         bool
+		name  string
+		mount *types.Mount
 	}{
-		{"/var/lib/containers/storage", "/", true},
-		{"/var/lib/containers/storage", "/var/lib", true},
-		{"/var/lib/containers/storage", "/var/lib/containers", true},
-		{"/var/lib/containers/storage", "/var/lib/containers/storage", true},
-		{"/var/lib/containers/storage", "/var/lib/containers/storage/extra", false},
-		{"/var/lib/containers/storage", "/va", false},
-		{"/var/lib/containers/storage", "/var/tmp/containers", false},
+		{"invalid size", &types.Mount{ContainerPath: "/tmp", HostPath: "tmpfs:size=foo"}},
+		{"zero size", &types.Mount{ContainerPath: "/tmp", HostPath: "tmpfs:size=0"}},
+		{"invalid mode", &types.Mount{ContainerPath: "/tmp", HostPath: "tmpfs:mode=999"}},
+		{"unknown option", &types.Mount{ContainerPath: "/tmp", HostPath: "tmpfs:exec"}},
+		{"recursive read-only", &types.Mount{ContainerPath: "/tmp", HostPath: "tmpfs:", Readonly: true, RecursiveReadOnly: true}},
+		{"idmap", &types.Mount{ContainerPath: "/tmp", HostPath: "tmpfs:", UidMappings: []*types.IDMapping{{ContainerId: 0, HostId: 1000, Length: 1}}}},
 	}
 
 	for _, tt := range tests {
-		testname := tt.base + " " + tt.target
-		t.Run(testname, func(t *testing.T) {
-			res := isSubDirectoryOf(tt.base, tt.target)
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tmpfsMountFromCRI(tt.mount); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestAddImageVolumesSkipsCRIMounts(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	sut.config.ImageVolumes = libconfig.ImageVolumesBind
+	containerInfo := &storage.ContainerInfo{
+		RunDir: t.TempDir(),
+		Config: &v1.Image{
+			Config: v1.ImageConfig{
+				Volumes: map[string]struct{}{
+					"/data":  {},
+					"/cache": {},
+				},
+			},
+		},
+	}
+	criMounts := []rspec.Mount{{Destination: "/data", Source: "/host/data"}}
+
+	mounts, err := addImageVolumes(context.Background(), t.TempDir(), sut, containerInfo, "", ctr.Spec(), criMounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mounts) != 1 || mounts[0].Destination != "/cache" {
+		t.Errorf("expected only the /cache image volume, got %+v", mounts)
+	}
+}
+
+func TestAddImageVolumesReadOnly(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	sut.config.ImageVolumes = libconfig.ImageVolumesBind
+	sut.config.ImageVolumesReadOnly = true
+	containerInfo := &storage.ContainerInfo{
+		RunDir: t.TempDir(),
+		Config: &v1.Image{
+			Config: v1.ImageConfig{
+				Volumes: map[string]struct{}{"/data": {}},
+			},
+		},
+	}
+
+	mounts, err := addImageVolumes(context.Background(), t.TempDir(), sut, containerInfo, "", ctr.Spec(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mounts) != 1 {
+		t.Fatalf("expected a single image volume, got %+v", mounts)
+	}
+	if want := []string{"private", "bind", "ro"}; strings.Join(mounts[0].Options, ",") != strings.Join(want, ",") {
+		t.Errorf("got options %v, want %v", mounts[0].Options, want)
+	}
+}
+
+func TestImageMountLowerdir(t *testing.T) {
+	tests := []struct {
+		order libconfig.ImageMountLowerdirOrderType
+		want  string
+	}{
+		{libconfig.ImageMountLowerdirImageFirst, "lowerdir=/image:/scratch"},
+		{libconfig.ImageMountLowerdirScratchFirst, "lowerdir=/scratch:/image"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			res := imageMountLowerdir(tt.order, "/image", "/scratch")
 			if res != tt.want {
 				t.Errorf("got %v, want %v", res, tt.want)
 			}
 		})
 	}
 }
+
+func TestAddHostnameEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		imageEnvs []string
+		kubeEnvs  []*types.KeyValue
+		want      string
+	}{
+		{"unset", nil, nil, "HOSTNAME=sandbox"},
+		{"from image", []string{"HOSTNAME=image"}, nil, "HOSTNAME=image"},
+		{"from CRI", []string{"HOSTNAME=image"}, []*types.KeyValue{{Key: "HOSTNAME", Value: "kube"}}, "HOSTNAME=kube"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr, err := container.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			specgen := ctr.Spec()
+			specgen.ClearProcessEnv()
+
+			imageConfig := &v1.Image{Config: v1.ImageConfig{Env: tt.imageEnvs}}
+			for _, e := range mergeEnvs(imageConfig, tt.kubeEnvs) {
+				parts := strings.SplitN(e, "=", 2)
+				specgen.AddProcessEnv(parts[0], parts[1])
+			}
+			addHostnameEnv(specgen, "sandbox")
+
+			var found []string
+			for _, e := range specgen.Config.Process.Env {
+				if strings.HasPrefix(e, "HOSTNAME=") {
+					found = append(found, e)
+				}
+			}
+			if len(found) != 1 || found[0] != tt.want {
+				t.Errorf("got %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddProcessEnvs(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specgen := ctr.Spec()
+	specgen.ClearProcessEnv()
+
+	defaultEnv := []string{"GLOBAL=global", "HANDLER=global", "IMAGE=global", "CRI=global"}
+	handlerEnv := []string{"HANDLER=handler", "IMAGE=handler", "CRI=handler"}
+	imageConfig := &v1.Image{Config: v1.ImageConfig{Env: []string{"IMAGE=image", "CRI=image"}}}
+	kubeEnvs := []*types.KeyValue{{Key: "CRI", Value: "kube"}}
+
+	addProcessEnvs(specgen, defaultEnv, handlerEnv, mergeEnvs(imageConfig, kubeEnvs))
+
+	want := []string{"GLOBAL=global", "HANDLER=handler", "IMAGE=image", "CRI=kube"}
+	if got := specgen.Config.Process.Env; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestContainerUmask(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotation   string
+		defaultUmask string
+		want         *uint32
+		wantErr      bool
+	}{
+		{"unset", "", "", nil, false},
+		{"default", "", "0027", ptrUint32(0o027), false},
+		{"annotation", "0077", "", ptrUint32(0o077), false},
+		{"annotation overrides default", "0077", "0027", ptrUint32(0o077), false},
+		{"invalid annotation", "0o77", "0027", nil, true},
+		{"invalid default", "", "999", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.annotation != "" {
+				annotations[crioann.UmaskAnnotation] = tt.annotation
+			}
+			runtimeConfig := &libconfig.RuntimeConfig{DefaultUmask: tt.defaultUmask}
+
+			got, err := containerUmask(annotations, runtimeConfig)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerStopSignal(t *testing.T) {
+	tests := []struct {
+		name          string
+		imageSignal   string
+		defaultSignal string
+		want          syscall.Signal
+	}{
+		{"image", "SIGQUIT", "SIGINT", syscall.SIGQUIT},
+		{"default", "", "SIGINT", syscall.SIGINT},
+		{"numeric default", "", "9", syscall.SIGKILL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeConfig := &libconfig.RuntimeConfig{DefaultStopSignal: tt.defaultSignal}
+
+			ctr, err := oci.NewContainer("id", "name", "", "", nil, nil, nil, "", nil, nil, "", &types.ContainerMetadata{}, "sandbox", false, false, false, "", t.TempDir(), time.Now(), containerStopSignal(tt.imageSignal, runtimeConfig))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ctr.StopSignal(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateContainerStopSignalAnnotation(t *testing.T) {
+	sut := newTestServer(t)
+	sut.config.DefaultStopSignal = "SIGINT"
+	handler := sut.config.Runtimes[sut.config.DefaultRuntime]
+	handler.AllowedAnnotations = append(handler.AllowedAnnotations, crioann.DryRunAnnotation)
+	ctx := context.Background()
+
+	sbReq := newTestRunPodSandboxRequest("pod")
+	sbResp, err := sut.runPodSandbox(ctx, sbReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sut.CreateContainer(ctx, &types.CreateContainerRequest{
+		PodSandboxId: sbResp.PodSandboxId,
+		Config: &types.ContainerConfig{
+			Metadata:    &types.ContainerMetadata{Name: "ctr"},
+			Image:       &types.ImageSpec{Image: testPauseImage},
+			Command:     []string{"/pause"},
+			Annotations: map[string]string{crioann.DryRunAnnotation: "true"},
+			Linux: &types.LinuxContainerConfig{
+				SecurityContext: &types.LinuxContainerSecurityContext{
+					NamespaceOptions: sbReq.Config.Linux.SecurityContext.NamespaceOptions,
+				},
+			},
+		},
+		SandboxConfig: sbReq.Config,
+	})
+	var dryRun *dryRunError
+	if !errors.As(err, &dryRun) {
+		t.Fatalf("expected a dry run, got %v", err)
+	}
+	defer os.Remove(dryRun.specPath)
+
+	// The image has no stop signal, the annotation restored containers
+	// are rebuilt from has to carry the configured default.
+	var spec rspec.Spec
+	data, err := os.ReadFile(dryRun.specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.Annotations["org.opencontainers.image.stopSignal"]; got != "SIGINT" {
+		t.Errorf("expected the default stop signal annotation SIGINT, got %q", got)
+	}
+}
+
+func TestCreateContainerRestoreFromCheckpoint(t *testing.T) {
+	sut := newTestServer(t)
+	handler := sut.config.Runtimes[sut.config.DefaultRuntime]
+	handler.AllowedAnnotations = append(handler.AllowedAnnotations, crioann.DryRunAnnotation, crioann.RestoreFromCheckpointAnnotation)
+	ctx := context.Background()
+
+	bundleName, err := bundle.ParseBundleName(testPauseImage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := sut.StorageService().ImageStatusByName(bundleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// newCheckpoint writes a checkpoint directory taken from a container of
+	// the image imageRef.
+	newCheckpoint := func(imageRef string) string {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, metadata.CheckpointDirectory), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, metadata.CheckpointDirectory, "inventory.img"), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := metadata.WriteJSONFile(&metadata.ContainerConfig{Name: "checkpointed", RootfsImageRef: imageRef}, dir, metadata.ConfigDumpFile); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	sbReq := newTestRunPodSandboxRequest("pod")
+	sbResp, err := sut.runPodSandbox(ctx, sbReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createRestored := func(name, source string) error {
+		_, err := sut.CreateContainer(ctx, &types.CreateContainerRequest{
+			PodSandboxId: sbResp.PodSandboxId,
+			Config: &types.ContainerConfig{
+				Metadata: &types.ContainerMetadata{Name: name},
+				Image:    &types.ImageSpec{Image: testPauseImage},
+				Command:  []string{"/pause"},
+				Annotations: map[string]string{
+					crioann.DryRunAnnotation:                "true",
+					crioann.RestoreFromCheckpointAnnotation: source,
+				},
+				Linux: &types.LinuxContainerConfig{
+					SecurityContext: &types.LinuxContainerSecurityContext{
+						NamespaceOptions: sbReq.Config.Linux.SecurityContext.NamespaceOptions,
+					},
+				},
+			},
+			SandboxConfig: sbReq.Config,
+		})
+		return err
+	}
+
+	t.Run("criu support disabled", func(t *testing.T) {
+		sut.config.EnableCriuSupport = false
+
+		err := createRestored("disabled", newCheckpoint(img.Id))
+
+		if code := status.Code(err); code != codes.Unimplemented {
+			t.Errorf("expected code %s, got %s: %v", codes.Unimplemented, code, err)
+		}
+	})
+
+	t.Run("other image", func(t *testing.T) {
+		sut.config.EnableCriuSupport = true
+
+		err := createRestored("mismatch", newCheckpoint("otherImageID"))
+
+		if !errors.Is(err, lib.ErrCheckpointImageMismatch) {
+			t.Errorf("expected a checkpoint image mismatch, got %v", err)
+		}
+	})
+
+	t.Run("matching image", func(t *testing.T) {
+		sut.config.EnableCriuSupport = true
+
+		err := createRestored("restored", newCheckpoint(img.Id))
+
+		// The dry run stops the creation right before the runtime restores
+		// the container, so the checkpoint got imported.
+		var dryRun *dryRunError
+		if !errors.As(err, &dryRun) {
+			t.Fatalf("expected the checkpoint to be imported, got %v", err)
+		}
+		os.Remove(dryRun.specPath)
+	})
+}
+
+func ptrUint32(v uint32) *uint32 {
+	return &v
+}
+
+func TestAddTermEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		imageEnvs []string
+		kubeEnvs  []*types.KeyValue
+		term      string
+		want      []string
+	}{
+		{"unset", nil, nil, "xterm", []string{"TERM=xterm"}},
+		{"custom default", nil, nil, "xterm-256color", []string{"TERM=xterm-256color"}},
+		{"disabled", nil, nil, "", nil},
+		{"from image", []string{"TERM=vt100"}, nil, "xterm", []string{"TERM=vt100"}},
+		{"from CRI", []string{"TERM=vt100"}, []*types.KeyValue{{Key: "TERM", Value: "linux"}}, "xterm", []string{"TERM=linux"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr, err := container.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			specgen := ctr.Spec()
+			specgen.ClearProcessEnv()
+
+			imageConfig := &v1.Image{Config: v1.ImageConfig{Env: tt.imageEnvs}}
+			for _, e := range mergeEnvs(imageConfig, tt.kubeEnvs) {
+				parts := strings.SplitN(e, "=", 2)
+				specgen.AddProcessEnv(parts[0], parts[1])
+			}
+			addTermEnv(specgen, tt.term)
+
+			var found []string
+			for _, e := range specgen.Config.Process.Env {
+				if strings.HasPrefix(e, "TERM=") {
+					found = append(found, e)
+				}
+			}
+			if !slices.Equal(found, tt.want) {
+				t.Errorf("got %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetupContainerUserMergesImageGroups(t *testing.T) {
+	rootfs := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rootfs, "etc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "passwd"), []byte("app:x:1000:1000::/home/app:/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rootfs, "etc", "group"), []byte("app:x:1000:\nwheel:x:10:app\naudio:x:63:app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specgen := ctr.Spec()
+	// Avoid generating /etc/passwd and /etc/group.
+	specgen.AddMount(rspec.Mount{Destination: "/etc", Source: filepath.Join(rootfs, "etc"), Type: "bind"})
+
+	imageConfig := &v1.Image{Config: v1.ImageConfig{
+		User:   "app",
+		Labels: map[string]string{crioann.AdditionalGIDsAnnotation: "63, 2000,1000"},
+	}}
+	sc := &types.LinuxContainerSecurityContext{
+		SupplementalGroups:       []int64{2000, 3000},
+		SupplementalGroupsPolicy: types.SupplementalGroupsPolicy_Merge,
+	}
+
+	if err := setupContainerUser(context.Background(), specgen, rootfs, "", t.TempDir(), sc, imageConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []uint32{1000, 10, 63, 2000, 3000}
+	if got := specgen.Config.Process.User.AdditionalGids; !slices.Equal(got, want) {
+		t.Errorf("got additional GIDs %v, want %v", got, want)
+	}
+}
+
+func TestSetupContainerUserInvalidImageGroups(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specgen := ctr.Spec()
+	specgen.AddMount(rspec.Mount{Destination: "/etc", Source: "/etc", Type: "bind"})
+
+	imageConfig := &v1.Image{Config: v1.ImageConfig{
+		Labels: map[string]string{crioann.AdditionalGIDsAnnotation: "wheel"},
+	}}
+	sc := &types.LinuxContainerSecurityContext{
+		RunAsUser:                &types.Int64Value{Value: 0},
+		SupplementalGroupsPolicy: types.SupplementalGroupsPolicy_Merge,
+	}
+
+	if err := setupContainerUser(context.Background(), specgen, t.TempDir(), "", t.TempDir(), sc, imageConfig); err == nil {
+		t.Error("expected an error for a non numeric image GID")
+	}
+}
+
+func TestSetupContainerUserUnsupportedPolicy(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	specgen := ctr.Spec()
+	specgen.AddMount(rspec.Mount{Destination: "/etc", Source: "/etc", Type: "bind"})
+
+	sc := &types.LinuxContainerSecurityContext{
+		RunAsUser:                &types.Int64Value{Value: 0},
+		SupplementalGroupsPolicy: types.SupplementalGroupsPolicy(42),
+	}
+
+	err = setupContainerUser(context.Background(), specgen, t.TempDir(), "", t.TempDir(), sc, nil)
+	if !errors.Is(err, ErrUnsupportedSupplementalGroupsPolicy) {
+		t.Errorf("expected ErrUnsupportedSupplementalGroupsPolicy, got %v", err)
+	}
+}
+
+func TestEnsureImageVolumesPath(t *testing.T) {
+	sut := &Server{}
+	sut.config.ImageVolumesDir = filepath.Join(t.TempDir(), "image-volumes")
+	mounts := []*types.Mount{{Image: &types.ImageSpec{Image: "image"}}}
+
+	path, err := sut.ensureImageVolumesPath(context.Background(), mounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != sut.config.ImageVolumesDir {
+		t.Errorf("expected the configured path %s, got %s", sut.config.ImageVolumesDir, path)
+	}
+
+	if err := os.WriteFile(filepath.Join(path, "file"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.ensureImageVolumesPath(context.Background(), mounts); err == nil {
+		t.Error("expected an error for a non-empty image volumes path")
+	}
+
+	if path, err := sut.ensureImageVolumesPath(context.Background(), nil); err != nil || path != "" {
+		t.Errorf("expected no image volumes path without image mounts, got %q, %v", path, err)
+	}
+}
+
+func TestImageMountCopyOnWrite(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		enabled  bool
+		readonly bool
+		want     bool
+	}{
+		{"disabled", false, false, false},
+		{"disabled read-only", false, true, false},
+		{"enabled", true, false, true},
+		{"enabled read-only", true, true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := &Server{}
+			sut.config.ImageMountsCopyOnWrite = tc.enabled
+
+			got := sut.imageMountCopyOnWrite(&types.Mount{Readonly: tc.readonly})
+
+			if got != tc.want {
+				t.Errorf("expected copy-on-write %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestImageMountsCOWPath(t *testing.T) {
+	sut := &Server{}
+	sut.config.ImageVolumesDir = "/data/crio/image-volumes/"
+
+	if path := sut.imageMountsCOWPath("ctrID"); path != "/data/crio/image-volumes-cow/ctrID" {
+		t.Errorf("expected the copy-on-write path next to the image volumes dir, got %s", path)
+	}
+}
+
+func TestImageMountCOWOptions(t *testing.T) {
+	cowPath := t.TempDir()
+
+	options, err := imageMountCOWOptions(cowPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options) != 2 {
+		t.Fatalf("expected upperdir and workdir options, got %v", options)
+	}
+	for i, prefix := range []string{"upperdir=", "workdir="} {
+		dir, ok := strings.CutPrefix(options[i], prefix)
+		if !ok {
+			t.Fatalf("expected %s option, got %s", prefix, options[i])
+		}
+		// Writes must land below the per container directory and never in
+		// the shared image mount point.
+		if !strings.HasPrefix(dir, cowPath+"/") {
+			t.Errorf("%s is not below %s", dir, cowPath)
+		}
+		if _, err := os.Stat(dir); err != nil {
+			t.Error(err)
+		}
+	}
+
+	other, err := imageMountCOWOptions(cowPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other[0] == options[0] {
+		t.Errorf("expected distinct upper directories per image mount, got %s twice", options[0])
+	}
+}
+
+func newImageMountsTestMounts() []*types.Mount {
+	mounts := []*types.Mount{}
+	for i := range 2 * maxParallelImageMounts {
+		mounts = append(mounts, &types.Mount{
+			ContainerPath: fmt.Sprintf("/image%d", i),
+			Image:         &types.ImageSpec{Image: fmt.Sprintf("image%d", i)},
+		})
+	}
+	// Mounts without an image are skipped.
+	return append(mounts, &types.Mount{ContainerPath: "/bind", HostPath: "/tmp"})
+}
+
+func TestMountImagesKeepsOrder(t *testing.T) {
+	mounts := newImageMountsTestMounts()
+	var running, maxRunning atomic.Int32
+
+	imageMounts, err := mountImages(mounts, func(m *types.Mount) (*imageMount, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			current := maxRunning.Load()
+			if n <= current || maxRunning.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		// Let earlier mounts finish last.
+		i := slices.Index(mounts, m)
+		time.Sleep(time.Duration(len(mounts)-i) * time.Millisecond)
+		return &imageMount{
+			volume: oci.ContainerVolume{ContainerPath: m.ContainerPath, Image: m.Image},
+		}, nil
+	}, func(*imageMount) {
+		t.Error("unexpected unmount")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imageMounts) != len(mounts)-1 {
+		t.Fatalf("expected %d image mounts, got %d", len(mounts)-1, len(imageMounts))
+	}
+	for i, m := range mounts[:len(mounts)-1] {
+		if imageMounts[i].volume.ContainerPath != m.ContainerPath {
+			t.Errorf("expected image mount %d at %s, got %s", i, m.ContainerPath, imageMounts[i].volume.ContainerPath)
+		}
+	}
+	if maxRunning.Load() > maxParallelImageMounts {
+		t.Errorf("expected at most %d concurrent mounts, got %d", maxParallelImageMounts, maxRunning.Load())
+	}
+}
+
+func TestMountImagesUnmountsOnFailure(t *testing.T) {
+	mounts := newImageMountsTestMounts()
+	errMount := errors.New("mount failed")
+	var lock sync.Mutex
+	mounted := map[string]bool{}
+
+	_, err := mountImages(mounts, func(m *types.Mount) (*imageMount, error) {
+		if m.Image.Image == "image3" {
+			return nil, errMount
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		mounted[m.Image.Image] = true
+		return &imageMount{volume: oci.ContainerVolume{Image: m.Image}}, nil
+	}, func(im *imageMount) {
+		lock.Lock()
+		defer lock.Unlock()
+		if !mounted[im.volume.Image.Image] {
+			t.Errorf("unmounting %s which is not mounted", im.volume.Image.Image)
+		}
+		delete(mounted, im.volume.Image.Image)
+	})
+
+	if !errors.Is(err, errMount) {
+		t.Fatalf("expected %v, got %v", errMount, err)
+	}
+	if len(mounted) != 0 {
+		t.Errorf("expected all mounted images to be unmounted, got %v left", mounted)
+	}
+}
+
+func TestAddOCIBindsCleansUpImageMountsCOW(t *testing.T) {
+	sut := newTestServer(t)
+	sut.config.ImageVolumesDir = filepath.Join(t.TempDir(), "image-volumes")
+	sut.config.ImageMountsCopyOnWrite = true
+
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{ContainerPath: "/image", Image: &types.ImageSpec{Image: testPauseImage}},
+			{ContainerPath: "/missing", Image: &types.ImageSpec{Image: "missing"}},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctr.SetNameAndID(naming.Default(), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	resourceCleaner := resourcestore.NewResourceCleaner()
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourceCleaner)
+	if err == nil {
+		t.Fatal("expected mounting the missing image to fail")
+	}
+	cowPath := sut.imageMountsCOWPath(ctr.ID())
+	var dirs []string
+	for _, name := range []string{"upper", "work"} {
+		matches, err := filepath.Glob(filepath.Join(cowPath, "*", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 1 {
+			t.Fatalf("expected the %s directory of the first image mount, got %v", name, matches)
+		}
+		dirs = append(dirs, matches[0])
+	}
+
+	if err := resourceCleaner.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range append(dirs, cowPath) {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed after the failed creation, got %v", dir, err)
+		}
+	}
+}
+
+func TestIsSubDirectoryOf(t *testing.T) {
+	tests := []struct {
+		base, target string
+		want         bool
+	}{
+		{"/var/lib/containers/storage", "/", true},
+		{"/var/lib/containers/storage", "/var/lib", true},
+		{"/var/lib/containers/storage", "/var/lib/containers", true},
+		{"/var/lib/containers/storage", "/var/lib/containers/storage", true},
+		{"/var/lib/containers/storage", "/var/lib/containers/storage/extra", false},
+		{"/var/lib/containers/storage", "/va", false},
+		{"/var/lib/containers/storage", "/var/tmp/containers", false},
+	}
+
+	for _, tt := range tests {
+		testname := tt.base + " " + tt.target
+		t.Run(testname, func(t *testing.T) {
+			res := isSubDirectoryOf(tt.base, tt.target)
+			if res != tt.want {
+				t.Errorf("got %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerShmSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        int64
+		wantErr     bool
+	}{
+		{"unset", map[string]string{}, 0, false},
+		{"pod wide only", map[string]string{"io.kubernetes.cri-o.ShmSize": "128Mi"}, 0, false},
+		{"other container", map[string]string{"io.kubernetes.cri-o.ShmSize.other": "128Mi"}, 0, false},
+		{"valid", map[string]string{"io.kubernetes.cri-o.ShmSize.testctr": "128Mi"}, 128 * 1024 * 1024, false},
+		{"invalid", map[string]string{"io.kubernetes.cri-o.ShmSize.testctr": "foo"}, 0, true},
+		{"zero", map[string]string{"io.kubernetes.cri-o.ShmSize.testctr": "0"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := containerShmSize(tt.annotations, "testctr")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if res != tt.want {
+				t.Errorf("got %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerPrivateShm(t *testing.T) {
+	tests := []struct {
+		name                 string
+		sandboxAnnotations   map[string]string
+		containerAnnotations map[string]string
+		want                 bool
+	}{
+		{"unset", map[string]string{}, map[string]string{}, false},
+		{"container", map[string]string{}, map[string]string{"io.kubernetes.cri-o.PrivateShm": "true"}, true},
+		{"sandbox", map[string]string{"io.kubernetes.cri-o.PrivateShm.testctr": "true"}, map[string]string{}, true},
+		{"other container", map[string]string{"io.kubernetes.cri-o.PrivateShm.other": "true"}, map[string]string{}, false},
+		{"pod wide only", map[string]string{"io.kubernetes.cri-o.PrivateShm": "true"}, map[string]string{}, false},
+		{"false", map[string]string{}, map[string]string{"io.kubernetes.cri-o.PrivateShm": "false"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if res := containerPrivateShm(tt.sandboxAnnotations, tt.containerAnnotations, "testctr"); res != tt.want {
+				t.Errorf("got %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNamespaceTarget(t *testing.T) {
+	const targetID = "0123456789ab"
+	if err := validateNamespaceTarget(targetID, nil); err == nil || !strings.Contains(err.Error(), targetID+" not found") {
+		t.Errorf("expected an error naming the missing target, got %v", err)
+	}
+
+	target := oci.NewSpoofedContainer(targetID, "target", nil, "sandbox", time.Now(), t.TempDir())
+	target.State().Status = oci.ContainerStateStopped
+	if err := validateNamespaceTarget(targetID, target); err == nil || !strings.Contains(err.Error(), targetID+" is not running") {
+		t.Errorf("expected an error naming the stopped target, got %v", err)
+	}
+
+	target.State().Status = oci.ContainerStateRunning
+	if err := validateNamespaceTarget(targetID, target); err != nil {
+		t.Errorf("expected no error for a running target, got %v", err)
+	}
+}
+
+func TestValidateFipsAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{"unset", map[string]string{}, false},
+		{"disable", map[string]string{crioann.DisableFIPSAnnotation: "true"}, false},
+		{"enable", map[string]string{crioann.EnableFIPSAnnotation: "true"}, false},
+		{"enable and not disable", map[string]string{crioann.EnableFIPSAnnotation: "true", crioann.DisableFIPSAnnotation: "false"}, false},
+		{"enable and disable", map[string]string{crioann.EnableFIPSAnnotation: "true", crioann.DisableFIPSAnnotation: "true"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFipsAnnotations(tt.annotations)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEnableFipsForContainer(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	if err := enableFipsForContainer(ctr, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var fipsMount *rspec.Mount
+	for _, m := range ctr.Spec().Mounts() {
+		if m.Destination == "/proc/sys/crypto/fips_enabled" {
+			fipsMount = &m
+		}
+	}
+	if fipsMount == nil {
+		t.Fatal("expected a mount over fips_enabled")
+	}
+	content, err := os.ReadFile(fipsMount.Source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "1\n" {
+		t.Errorf("expected fips_enabled to be 1, got %q", content)
+	}
+}
+
+func TestContainerDNSConfig(t *testing.T) {
+	const annotation = crioann.DNSConfigAnnotation
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *types.DNSConfig
+		wantErr     bool
+	}{
+		{"unset", map[string]string{}, nil, false},
+		{"servers, searches and options", map[string]string{
+			annotation: `{"servers":["127.0.0.53"],"searches":["svc.local"],"options":["ndots:1"]}`,
+		}, &types.DNSConfig{Servers: []string{"127.0.0.53"}, Searches: []string{"svc.local"}, Options: []string{"ndots:1"}}, false},
+		{"servers only", map[string]string{
+			annotation: `{"servers":["127.0.0.53"]}`,
+		}, &types.DNSConfig{Servers: []string{"127.0.0.53"}}, false},
+		{"invalid", map[string]string{annotation: "127.0.0.53"}, nil, true},
+		{"empty", map[string]string{annotation: `{}`}, nil, true},
+		{"newline in server", map[string]string{
+			annotation: `{"servers":["127.0.0.53\nnameserver 10.0.0.1"]}`,
+		}, nil, true},
+		{"carriage return in option", map[string]string{
+			annotation: `{"servers":["127.0.0.53"],"options":["ndots:1\r"]}`,
+		}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := containerDNSConfig(tt.annotations)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && (!slices.Equal(got.Servers, tt.want.Servers) ||
+				!slices.Equal(got.Searches, tt.want.Searches) || !slices.Equal(got.Options, tt.want.Options))) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSymbolicLinkCycle(t *testing.T) {
+	dir := t.TempDir()
+	loop := filepath.Join(dir, "loop")
+	if err := os.Symlink(loop, loop); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := resolveSymbolicLink("", loop)
+	if !errors.Is(err, ErrTooManySymlinks) {
+		t.Errorf("expected too many symlinks error, got %v", err)
+	}
+}
+
+func TestResolveSymbolicLinkDepth(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.Mkdir(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// link0 -> link1 -> ... -> linkN -> target
+	chain := func(n int) string {
+		prev := target
+		for i := n; i >= 0; i-- {
+			link := filepath.Join(dir, fmt.Sprintf("chain%d-link%d", n, i))
+			if err := os.Symlink(prev, link); err != nil {
+				t.Fatal(err)
+			}
+			prev = link
+		}
+		return prev
+	}
+
+	resolved, err := resolveSymbolicLink("", chain(maxSymlinkDepth-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != target {
+		t.Errorf("expected %s, got %s", target, resolved)
+	}
+
+	if _, err := resolveSymbolicLink("", chain(maxSymlinkDepth)); !errors.Is(err, ErrTooManySymlinks) {
+		t.Errorf("expected too many symlinks error, got %v", err)
+	}
+}
+
+func TestCheckMountSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		allowed []string
+		denied  []string
+		wantErr bool
+	}{
+		{"no lists", "/etc/kubernetes", nil, nil, false},
+		{"denied", "/etc/kubernetes", nil, []string{"/etc/kubernetes"}, true},
+		{"below denied", "/etc/kubernetes/pki", nil, []string{"/etc/kubernetes"}, true},
+		{"denied with trailing slash", "/etc/kubernetes/pki", nil, []string{"/etc/kubernetes/"}, true},
+		{"sibling of denied", "/etc/kubernetes-other", nil, []string{"/etc/kubernetes"}, false},
+		{"parent of denied", "/etc", nil, []string{"/etc/kubernetes"}, true},
+		{"root", "/", nil, []string{"/etc/kubernetes"}, true},
+		{"allowed", "/data/volume", []string{"/data"}, nil, false},
+		{"not allowed", "/etc/volume", []string{"/data"}, nil, true},
+		{"denied below allowed", "/data/secret", []string{"/data"}, []string{"/data/secret"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkMountSource(tt.path, tt.allowed, tt.denied)
+			if tt.wantErr != errors.Is(err, ErrMountSourceNotAllowed) {
+				t.Errorf("expected mount source not allowed %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddOCIBindsDeniedSymlinkSource(t *testing.T) {
+	dir := t.TempDir()
+	denied := filepath.Join(dir, "denied")
+	if err := os.Mkdir(denied, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(denied, link); err != nil {
+		t.Fatal(err)
+	}
+
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{ContainerPath: "/data", HostPath: link},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	sut.config.DeniedMountSources = []string{denied}
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if !errors.Is(err, ErrMountSourceNotAllowed) || !strings.Contains(err.Error(), denied) {
+		t.Errorf("expected the symlinked source to be denied, got %v", err)
+	}
+}
+
+func TestAddOCIBindsDeniedRoot(t *testing.T) {
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{ContainerPath: "/host", HostPath: "/"},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	sut.config.DeniedMountSources = []string{"/etc/kubernetes"}
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if !errors.Is(err, ErrMountSourceNotAllowed) || !strings.Contains(err.Error(), "/etc/kubernetes") {
+		t.Errorf("expected the host root to be denied, got %v", err)
+	}
+}
+
+func TestAddOCIBindsDeniedSymlinkParent(t *testing.T) {
+	dir := t.TempDir()
+	denied := filepath.Join(dir, "denied")
+	if err := os.Mkdir(denied, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(denied, link); err != nil {
+		t.Fatal(err)
+	}
+
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{ContainerPath: "/data", HostPath: filepath.Join(link, "pki")},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	sut.config.DeniedMountSources = []string{denied}
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if !errors.Is(err, ErrMountSourceNotAllowed) || !strings.Contains(err.Error(), denied) {
+		t.Errorf("expected the source below a symlinked parent to be denied, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(denied, "pki")); !os.IsNotExist(err) {
+		t.Errorf("expected the denied source not to be created, got %v", err)
+	}
+}
+
+func TestAddOCIBindsDeniedSymlinkEntry(t *testing.T) {
+	// Like /var/run linking to /run, the denied entry is reached through a
+	// symlink, while the mount requests the link target.
+	dir := t.TempDir()
+	run := filepath.Join(dir, "run")
+	if err := os.MkdirAll(filepath.Join(run, "secrets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	varRun := filepath.Join(dir, "var-run")
+	if err := os.Symlink(run, varRun); err != nil {
+		t.Fatal(err)
+	}
+	denied := filepath.Join(varRun, "secrets")
+
+	ctr, err := container.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctr.SetConfig(&types.ContainerConfig{
+		Mounts: []*types.Mount{
+			{ContainerPath: "/secrets", HostPath: filepath.Join(run, "secrets", "token")},
+		},
+		Metadata: &types.ContainerMetadata{
+			Name: "testctr",
+		},
+	}, &types.PodSandboxConfig{
+		Metadata: &types.PodSandboxMetadata{
+			Name: "testpod",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sut := &Server{}
+	sut.config.DeniedMountSources = []string{denied}
+	_, _, err = sut.addOCIBindMounts(context.Background(), ctr, "", "", nil, false, false, false, false, false, "", resourcestore.NewResourceCleaner())
+	if !errors.Is(err, ErrMountSourceNotAllowed) || !strings.Contains(err.Error(), denied) {
+		t.Errorf("expected the source below the symlinked denied entry to be denied, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(run, "secrets", "token")); !os.IsNotExist(err) {
+		t.Errorf("expected the denied source not to be created, got %v", err)
+	}
+}
+
+func TestRelabelMountSources(t *testing.T) {
+	for _, workers := range []int{0, 2} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			sources := []string{"/a", "/b", "/c", "/d"}
+			var (
+				relabeled         sync.Map
+				running, maxInUse atomic.Int32
+			)
+
+			err := relabelMountSources(sources, workers, func(src string) error {
+				inUse := running.Add(1)
+				defer running.Add(-1)
+				for {
+					current := maxInUse.Load()
+					if inUse <= current || maxInUse.CompareAndSwap(current, inUse) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				relabeled.Store(src, true)
+				if src == "/b" || src == "/d" {
+					return fmt.Errorf("relabel failed %s", src)
+				}
+				return nil
+			})
+
+			if err == nil || !strings.Contains(err.Error(), "/b") || !strings.Contains(err.Error(), "/d") {
+				t.Errorf("expected the errors of all failed relabels, got %v", err)
+			}
+			for _, src := range sources {
+				if _, ok := relabeled.Load(src); !ok {
+					t.Errorf("expected %s to be relabeled", src)
+				}
+			}
+			if limit := int32(max(workers, 1)); maxInUse.Load() > limit {
+				t.Errorf("expected at most %d parallel relabels, got %d", limit, maxInUse.Load())
+			}
+		})
+	}
+}
@@ -9,8 +9,9 @@ import (
 	"github.com/L-F-Z/cri-t/internal/factory/container"
 	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
 	"github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/internal/resourcestore"
 )
 
-func (s *Server) createSandboxContainer(ctx context.Context, ctr container.Container, sb *sandbox.Sandbox) (*oci.Container, error) {
+func (s *Server) createSandboxContainer(ctx context.Context, ctr container.Container, sb *sandbox.Sandbox, resourceCleaner *resourcestore.ResourceCleaner) (*oci.Container, error) {
 	return nil, fmt.Errorf("not implemented yet")
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/L-F-Z/cri-t/internal/log"
 	"github.com/L-F-Z/cri-t/internal/oci"
 	"github.com/L-F-Z/cri-t/internal/storage"
+	"github.com/L-F-Z/cri-t/server/metrics"
 )
 
 // RemoveContainer removes the container. If the container is running, the container
@@ -42,8 +43,6 @@ func (s *Server) RemoveContainer(ctx context.Context, req *types.RemoveContainer
 		return nil, err
 	}
 
-	s.removeSeccompNotifier(ctx, c)
-
 	s.generateCRIEvent(ctx, c, types.ContainerEventType_CONTAINER_DELETED_EVENT)
 	log.Infof(ctx, "Removed container %s: %s", c.ID(), c.Description())
 	return &types.RemoveContainerResponse{}, nil
@@ -73,16 +72,26 @@ func (s *Server) removeContainerInPod(ctx context.Context, sb *sandbox.Sandbox,
 
 	c.CleanupConmonCgroup(ctx)
 
+	if !c.IsInfra() {
+		s.removeSeccompNotifier(ctx, c)
+		s.unmountContainerShm(ctx, c)
+		s.removeImageMountsCOW(ctx, c.ID())
+	}
+
 	if err := s.StorageService().DeleteContainer(ctx, c.ID()); err != nil && !errors.Is(err, storage.ErrContainerUnknown) {
 		return fmt.Errorf("failed to delete container %s in pod sandbox %s: %w", c.Name(), sb.ID(), err)
 	}
 
 	s.ReleaseContainerName(ctx, c.Name())
+	metrics.Instance().MetricResourceStageDurationDelete(c.Name())
 	s.removeContainer(ctx, c)
 	if err := s.CtrIDIndex().Delete(c.ID()); err != nil {
 		return fmt.Errorf("failed to delete container %s in pod sandbox %s from index: %w", c.Name(), sb.ID(), err)
 	}
 	sb.RemoveContainer(ctx, c)
+	if !c.IsInfra() {
+		s.auditContainerRemoval(ctx, sb, c)
+	}
 
 	return nil
 }
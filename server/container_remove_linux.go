@@ -2,14 +2,22 @@ package server
 
 import (
 	"context"
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
 
 	"github.com/L-F-Z/cri-t/internal/config/seccomp"
+	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
 	"github.com/L-F-Z/cri-t/internal/log"
 	"github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/pkg/annotations"
 )
 
+// removeSeccompNotifier closes the seccomp notifier of a container, if it has
+// one, and forgets it.
 func (s *Server) removeSeccompNotifier(ctx context.Context, c *oci.Container) {
-	if notifier, ok := s.seccompNotifiers.Load(c.ID()); ok {
+	if notifier, ok := s.seccompNotifiers.LoadAndDelete(c.ID()); ok {
 		n, ok := notifier.(*seccomp.Notifier)
 		if ok {
 			if err := n.Close(); err != nil {
@@ -18,3 +26,24 @@ func (s *Server) removeSeccompNotifier(ctx context.Context, c *oci.Container) {
 		}
 	}
 }
+
+// unmountContainerShm unmounts the dedicated shm of a container, if one was
+// requested during its creation.
+func (s *Server) unmountContainerShm(ctx context.Context, c *oci.Container) {
+	shmPath, ok := c.CrioAnnotations()[annotations.ShmPath]
+	if !ok || shmPath == "" || shmPath == sandbox.DevShmPath {
+		return
+	}
+	if err := unix.Unmount(shmPath, unix.MNT_DETACH); err != nil &&
+		!errors.Is(err, unix.EINVAL) && !errors.Is(err, unix.ENOENT) {
+		log.Warnf(ctx, "Unable to unmount shm of container %s: %v", c.ID(), err)
+	}
+}
+
+// removeImageMountsCOW removes the upper and work directories of the
+// read-write image mounts of a container.
+func (s *Server) removeImageMountsCOW(ctx context.Context, ctrID string) {
+	if err := os.RemoveAll(s.imageMountsCOWPath(ctrID)); err != nil {
+		log.Warnf(ctx, "Unable to remove copy-on-write image mounts of container %s: %v", ctrID, err)
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/L-F-Z/cri-t/internal/config/seccomp"
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+func TestRemoveSeccompNotifier(t *testing.T) {
+	ctx := context.Background()
+	sut := &Server{}
+	ctr := oci.NewSpoofedContainer("ctr", "ctr", nil, "sandbox", time.Now(), t.TempDir())
+
+	notifier, err := seccomp.NewNotifier(ctx, make(chan seccomp.Notification), ctr.ID(),
+		filepath.Join(t.TempDir(), "seccomp.sock"), map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sut.seccompNotifiers.Store(ctr.ID(), notifier)
+
+	sut.removeSeccompNotifier(ctx, ctr)
+
+	if _, ok := sut.seccompNotifiers.Load(ctr.ID()); ok {
+		t.Error("expected the seccomp notifier to be removed")
+	}
+}
+
+func TestRemoveImageMountsCOW(t *testing.T) {
+	sut := &Server{}
+	sut.config.ImageVolumesDir = filepath.Join(t.TempDir(), "image-volumes")
+	cowPath := sut.imageMountsCOWPath("ctr")
+	if _, err := imageMountCOWOptions(cowPath); err != nil {
+		t.Fatal(err)
+	}
+	other := sut.imageMountsCOWPath("other")
+	if _, err := imageMountCOWOptions(other); err != nil {
+		t.Fatal(err)
+	}
+
+	sut.removeImageMountsCOW(context.Background(), "ctr")
+
+	if _, err := os.Stat(cowPath); !os.IsNotExist(err) {
+		t.Errorf("expected the upper and work directories to be removed, got %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("expected the directories of other containers to be kept: %v", err)
+	}
+}
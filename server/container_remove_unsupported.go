@@ -10,3 +10,9 @@ import (
 
 func (s *Server) removeSeccompNotifier(ctx context.Context, c *oci.Container) {
 }
+
+func (s *Server) unmountContainerShm(ctx context.Context, c *oci.Container) {
+}
+
+func (s *Server) removeImageMountsCOW(ctx context.Context, ctrID string) {
+}
@@ -25,6 +25,11 @@ func (s *Server) StartContainer(ctx context.Context, req *types.StartContainerRe
 	}
 
 	state := c.State()
+	if c.Restore() && state.Status == oci.ContainerStateRunning {
+		// The process of a restored container runs since its creation.
+		log.Infof(ctx, "Container %s got restored from a checkpoint and is already running", c.ID())
+		return &types.StartContainerResponse{}, nil
+	}
 	if state.Status != oci.ContainerStateCreated {
 		return nil, fmt.Errorf("container %s is not in created state: %s", c.ID(), state.Status)
 	}
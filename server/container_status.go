@@ -136,10 +136,11 @@ func (s *Server) ContainerStatus(ctx context.Context, req *types.ContainerStatus
 }
 
 type containerInfo struct {
-	SandboxID   string    `json:"sandboxID"`
-	Pid         int       `json:"pid"`
-	RuntimeSpec spec.Spec `json:"runtimeSpec"`
-	Privileged  bool      `json:"privileged"`
+	SandboxID       string               `json:"sandboxID"`
+	Pid             int                  `json:"pid"`
+	RuntimeSpec     spec.Spec            `json:"runtimeSpec"`
+	Privileged      bool                 `json:"privileged"`
+	SecurityPosture *oci.SecurityPosture `json:"securityPosture"`
 }
 
 func (s *Server) createContainerInfo(container *oci.Container) (map[string]string, error) {
@@ -150,10 +151,11 @@ func (s *Server) createContainerInfo(container *oci.Container) (map[string]strin
 
 	bytes, err := func(metadata *storage.RuntimeContainerMetadata) ([]byte, error) {
 		localContainerInfo := containerInfo{
-			SandboxID:   container.Sandbox(),
-			Pid:         container.StateNoLock().InitPid,
-			RuntimeSpec: container.Spec(),
-			Privileged:  metadata.Privileged,
+			SandboxID:       container.Sandbox(),
+			Pid:             container.StateNoLock().InitPid,
+			RuntimeSpec:     container.Spec(),
+			Privileged:      metadata.Privileged,
+			SecurityPosture: container.SecurityPosture(),
 		}
 
 		return json.Marshal(localContainerInfo)
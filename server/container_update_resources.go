@@ -10,6 +10,7 @@ import (
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 	"k8s.io/utils/cpuset"
 
+	"github.com/L-F-Z/cri-t/internal/config/cgmgr"
 	"github.com/L-F-Z/cri-t/internal/config/node"
 	"github.com/L-F-Z/cri-t/internal/log"
 	"github.com/L-F-Z/cri-t/internal/oci"
@@ -39,13 +40,26 @@ func (s *Server) UpdateContainerResources(ctx context.Context, req *types.Update
 		if updated == nil {
 			updated = req.Linux
 		}
-		resources := toOCIResources(updated)
+		sb := s.GetSandbox(c.Sandbox())
+		if sb == nil {
+			return nil, fmt.Errorf("unable to update resources of container %s: sandbox %s not found", c.ID(), c.Sandbox())
+		}
+		containerMinMemory, err := s.Runtime().GetContainerMinMemory(sb.RuntimeHandler())
+		if err != nil {
+			return nil, err
+		}
+		resources, err := toOCIResources(updated, containerMinMemory)
+		if err != nil {
+			return nil, fmt.Errorf("update resources of container %s: %w", c.ID(), err)
+		}
 		if err := s.Runtime().UpdateContainer(ctx, c, resources); err != nil {
 			return nil, err
 		}
 
-		// update memory store with updated resources
-		s.UpdateContainerLinuxResources(c, resources)
+		// update memory store and the spec on disk with updated resources
+		if err := s.UpdateContainerLinuxResources(c, resources); err != nil {
+			return nil, err
+		}
 
 		if err := s.nri.postUpdateContainer(ctx, c); err != nil {
 			log.Errorf(ctx, "NRI container post-update failed: %v", err)
@@ -55,8 +69,10 @@ func (s *Server) UpdateContainerResources(ctx context.Context, req *types.Update
 	return &types.UpdateContainerResourcesResponse{}, nil
 }
 
-// toOCIResources converts CRI resource constraints to OCI.
-func toOCIResources(r *types.LinuxContainerResources) *rspec.LinuxResources {
+// toOCIResources converts CRI resource constraints to OCI. The memory limit
+// is validated against the minimum memory of the runtime handler and, like
+// at creation, a swap limit lower than the memory limit is refused.
+func toOCIResources(r *types.LinuxContainerResources, containerMinMemory int64) (*rspec.LinuxResources, error) {
 	update := rspec.LinuxResources{
 		// TODO(runcom): OOMScoreAdj is missing
 		CPU: &rspec.LinuxCPU{
@@ -77,13 +93,30 @@ func toOCIResources(r *types.LinuxContainerResources) *rspec.LinuxResources {
 
 	memory := r.MemoryLimitInBytes
 	if memory != 0 {
+		if err := cgmgr.VerifyMemoryIsEnough(memory, containerMinMemory); err != nil {
+			return nil, err
+		}
 		update.Memory.Limit = proto.Int64(memory)
 
+		if r.MemorySwapLimitInBytes != 0 {
+			if r.MemorySwapLimitInBytes > 0 && r.MemorySwapLimitInBytes < memory {
+				return nil, fmt.Errorf("memory swap limit (%d) cannot be lower than memory limit (%d)", r.MemorySwapLimitInBytes, memory)
+			}
+			memory = r.MemorySwapLimitInBytes
+		}
 		if node.CgroupHasMemorySwap() {
 			update.Memory.Swap = proto.Int64(memory)
 		}
 	}
-	return &update
+
+	// Unified resources only exist on cgroup v2, on v1 they are ignored like at creation.
+	if node.CgroupIsV2() && len(r.Unified) != 0 {
+		update.Unified = make(map[string]string, len(r.Unified))
+		for key, value := range r.Unified {
+			update.Unified[key] = value
+		}
+	}
+	return &update, nil
 }
 
 // reapplySharedCPUs appends shared CPUs and update the quota to handle CPUManager.
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestToOCIResources(t *testing.T) {
+	resources, err := toOCIResources(&types.LinuxContainerResources{
+		CpuShares:          512,
+		CpuQuota:           50000,
+		CpuPeriod:          100000,
+		CpusetCpus:         "0-1",
+		MemoryLimitInBytes: 64 * 1024 * 1024,
+	}, 12*1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *resources.CPU.Shares != 512 || *resources.CPU.Quota != 50000 || *resources.CPU.Period != 100000 {
+		t.Errorf("unexpected CPU resources %+v", resources.CPU)
+	}
+	if resources.CPU.Cpus != "0-1" {
+		t.Errorf("expected cpuset 0-1, got %q", resources.CPU.Cpus)
+	}
+	if *resources.Memory.Limit != 64*1024*1024 {
+		t.Errorf("expected memory limit of 64MiB, got %d", *resources.Memory.Limit)
+	}
+}
+
+func TestToOCIResourcesInvalidMemory(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		resources *types.LinuxContainerResources
+	}{
+		{"below minimum memory", &types.LinuxContainerResources{MemoryLimitInBytes: 1024}},
+		{"swap below memory", &types.LinuxContainerResources{
+			MemoryLimitInBytes:     64 * 1024 * 1024,
+			MemorySwapLimitInBytes: 32 * 1024 * 1024,
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := toOCIResources(tc.resources, 12*1024*1024); err == nil {
+				t.Error("expected the update to be refused")
+			}
+		})
+	}
+}
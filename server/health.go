@@ -63,7 +63,8 @@ func (s *Server) checkCRIHealth(ctx context.Context, timeout time.Duration) erro
 func (s *Server) cniPluginReadinessCheck(ctx context.Context) {
 	cniInitOnce.Do(func() {
 		go func() {
-			if err := s.waitForCNIPlugin(ctx, ""); err != nil {
+			// The readiness check waits as long as it takes.
+			if err := s.waitForCNIPlugin(ctx, "", 0); err != nil {
 				log.Errorf(ctx, "CNI plugin not ready: %v", err)
 			} else {
 				log.Infof(ctx, "CNI plugin is ready")
@@ -7,38 +7,37 @@ import (
 
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
-	crioStorage "github.com/L-F-Z/cri-t/utils"
+	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/storage"
 )
 
 // ImageFsInfo returns information of the filesystem that is used to store images.
-func (s *Server) ImageFsInfo(context.Context, *types.ImageFsInfoRequest) (*types.ImageFsInfoResponse, error) {
-	// TODO: move this function to TaskC
-	bundleRoot := "/var/lib/taskc/Bundle"
-	instanceRoot := "/var/lib/taskc/Instance"
-	bundleUsage, err := getUsage(bundleRoot)
+func (s *Server) ImageFsInfo(ctx context.Context, req *types.ImageFsInfoRequest) (*types.ImageFsInfoResponse, error) {
+	imageUsage, err := s.StorageService().ImageFsUsage()
 	if err != nil {
-		return nil, fmt.Errorf("unable to get usage for %s: %w", bundleRoot, err)
+		return nil, fmt.Errorf("unable to get image filesystem usage: %w", err)
 	}
-	instanceUsage, err := getUsage(instanceRoot)
+	containerUsage, err := s.StorageService().ContainerFsUsage()
 	if err != nil {
-		return nil, fmt.Errorf("unable to get usage for %s: %w", instanceRoot, err)
+		return nil, fmt.Errorf("unable to get container filesystem usage: %w", err)
+	}
+
+	for _, usage := range []*storage.FilesystemUsage{imageUsage, containerUsage} {
+		log.Debugf(ctx, "Filesystem of %s: %d bytes used, %d bytes and %d inodes available",
+			usage.Mountpoint, usage.UsedBytes, usage.AvailableBytes, usage.AvailableInodes)
 	}
 
 	return &types.ImageFsInfoResponse{
-		ImageFilesystems:     []*types.FilesystemUsage{bundleUsage},
-		ContainerFilesystems: []*types.FilesystemUsage{instanceUsage},
+		ImageFilesystems:     []*types.FilesystemUsage{criFilesystemUsage(imageUsage)},
+		ContainerFilesystems: []*types.FilesystemUsage{criFilesystemUsage(containerUsage)},
 	}, nil
 }
 
-func getUsage(containerPath string) (*types.FilesystemUsage, error) {
-	bytes, inodes, err := crioStorage.GetDiskUsageStats(containerPath)
-	if err != nil {
-		return nil, fmt.Errorf("get disk usage for path %s: %w", containerPath, err)
-	}
+func criFilesystemUsage(usage *storage.FilesystemUsage) *types.FilesystemUsage {
 	return &types.FilesystemUsage{
 		Timestamp:  time.Now().UnixNano(),
-		FsId:       &types.FilesystemIdentifier{Mountpoint: containerPath},
-		UsedBytes:  &types.UInt64Value{Value: bytes},
-		InodesUsed: &types.UInt64Value{Value: inodes},
-	}, nil
+		FsId:       &types.FilesystemIdentifier{Mountpoint: usage.Mountpoint},
+		UsedBytes:  &types.UInt64Value{Value: usage.UsedBytes},
+		InodesUsed: &types.UInt64Value{Value: usage.InodesUsed},
+	}
 }
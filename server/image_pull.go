@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"syscall"
 	"time"
@@ -14,6 +15,8 @@ import (
 
 	"github.com/L-F-Z/TaskC/pkg/bundle"
 	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/storage"
+	"github.com/L-F-Z/cri-t/pkg/annotations"
 	"github.com/L-F-Z/cri-t/server/metrics"
 )
 
@@ -40,6 +43,14 @@ func (s *Server) PullImage(ctx context.Context, req *types.PullImageRequest) (*t
 		}
 	}
 
+	// The kubelet only requests a pull if it wants the image to be fetched,
+	// so pull it even if a local bundle exists unless the pod asks otherwise.
+	policy, err := s.sandboxImagePullPolicy(sc, img.GetRuntimeHandler())
+	if err != nil {
+		return nil, err
+	}
+	pullArgs.policy = policy
+
 	// We use the server's pullOperationsInProgress to record which images are
 	// currently being pulled. This allows for avoiding pulling the same image
 	// in parallel. Hence, if a given image is currently being pulled, we queue
@@ -103,7 +114,7 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (bundle
 	}
 
 	// TODO: Cancel the pull if no progress is made
-	repoDigest, err := s.StorageService().PullImage(ctx, name)
+	repoDigest, err := s.StorageService().PullImage(ctx, name, pullArgs.policy)
 	if err != nil {
 		log.Debugf(ctx, "Error pulling image %s: %v", name, err)
 		tryIncrementImagePullFailureMetric(err)
@@ -114,6 +125,28 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (bundle
 	return repoDigest, nil
 }
 
+// sandboxImagePullPolicy returns the pull policy of an image pulled for the
+// pod sandbox config sc. The ImagePullPolicy annotation of the pod is only
+// honored if the runtime handler or the workload of the pod allows it.
+func (s *Server) sandboxImagePullPolicy(sc *types.PodSandboxConfig, runtimeHandler string) (storage.PullPolicy, error) {
+	podAnnotations := maps.Clone(sc.GetAnnotations())
+	if err := s.FilterDisallowedAnnotations(podAnnotations, podAnnotations, runtimeHandler); err != nil {
+		return "", err
+	}
+	return imagePullPolicy(podAnnotations, storage.PullAlways)
+}
+
+// imagePullPolicy returns the pull policy set by the ImagePullPolicy annotation
+// of a pod, or defaultPolicy if the annotation is not set. The annotations have
+// to be filtered by the allowed annotations already.
+func imagePullPolicy(podAnnotations map[string]string, defaultPolicy storage.PullPolicy) (storage.PullPolicy, error) {
+	policy, ok := podAnnotations[annotations.ImagePullPolicyAnnotation]
+	if !ok {
+		return defaultPolicy, nil
+	}
+	return storage.ParsePullPolicy(policy)
+}
+
 func tryIncrementImagePullFailureMetric(err error) {
 	// We try to cover some basic use-cases
 	const labelUnknown = "UNKNOWN"
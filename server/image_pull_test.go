@@ -0,0 +1,68 @@
+package server
+
+import (
+	"testing"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/storage"
+	crioann "github.com/L-F-Z/cri-t/pkg/annotations"
+)
+
+func TestImagePullPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		want        storage.PullPolicy
+		wantErr     bool
+	}{
+		{"default", nil, storage.PullIfNotPresent, false},
+		{"always", map[string]string{crioann.ImagePullPolicyAnnotation: "Always"}, storage.PullAlways, false},
+		{"never", map[string]string{crioann.ImagePullPolicyAnnotation: "Never"}, storage.PullNever, false},
+		{"invalid", map[string]string{crioann.ImagePullPolicyAnnotation: "Sometimes"}, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := imagePullPolicy(tc.annotations, storage.PullIfNotPresent)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got policy %s", policy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if policy != tc.want {
+				t.Errorf("expected policy %s, got %s", tc.want, policy)
+			}
+		})
+	}
+}
+
+func TestSandboxImagePullPolicy(t *testing.T) {
+	sut := newTestServer(t)
+	sc := &types.PodSandboxConfig{
+		Annotations: map[string]string{crioann.ImagePullPolicyAnnotation: "Never"},
+	}
+
+	policy, err := sut.sandboxImagePullPolicy(sc, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy != storage.PullAlways {
+		t.Errorf("expected the disallowed annotation to be ignored, got policy %s", policy)
+	}
+	if _, ok := sc.Annotations[crioann.ImagePullPolicyAnnotation]; !ok {
+		t.Error("expected the annotations of the request to be left alone")
+	}
+
+	handler := sut.config.Runtimes[sut.config.DefaultRuntime]
+	handler.AllowedAnnotations = append(handler.AllowedAnnotations, crioann.ImagePullPolicyAnnotation)
+	policy, err = sut.sandboxImagePullPolicy(sc, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy != storage.PullNever {
+		t.Errorf("expected the allowed annotation to set policy %s, got %s", storage.PullNever, policy)
+	}
+}
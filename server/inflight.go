@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/L-F-Z/cri-t/internal/log"
+)
+
+// ErrShuttingDown is returned for creation requests received after the
+// server started shutting down. Clients get it with the Unavailable gRPC
+// status code, so that they retry the creation against the next server.
+var ErrShuttingDown error = unavailableError("server is shutting down")
+
+// inFlightCreates tracks the container and pod sandbox creations which are
+// still running, so that a shutdown can wait for them to settle instead of
+// abandoning them half way.
+type inFlightCreates struct {
+	lock     sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+	// ctx is canceled if the creations do not settle within the shutdown
+	// timeout, which aborts them and lets their cleanup run.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newInFlightCreates() *inFlightCreates {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &inFlightCreates{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// start registers a new creation. The returned context is canceled when the
+// creation gets aborted by a shutdown, and the returned function has to be
+// called once the creation finished.
+func (i *inFlightCreates) start(ctx context.Context) (context.Context, func(), error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.draining {
+		return ctx, nil, ErrShuttingDown
+	}
+	i.wg.Add(1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(i.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		i.wg.Done()
+	}, nil
+}
+
+// drain stops accepting new creations and waits for the in-flight ones to
+// finish. Creations still running after the timeout get canceled, and drain
+// waits for them to clean up. A zero timeout cancels them right away.
+func (i *inFlightCreates) drain(ctx context.Context, timeout time.Duration) {
+	i.lock.Lock()
+	i.draining = true
+	i.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		i.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(timeout):
+		log.Warnf(ctx, "In-flight creations did not finish within %s, canceling them", timeout)
+		i.cancel()
+	}
+	<-done
+}
+
+// WaitForInFlightCreates stops accepting new container and pod sandbox
+// creations and waits up to the configured shutdown timeout for the in-flight
+// ones to settle. It has to be called before stopping the gRPC server, which
+// would otherwise wait for the creations without any timeout.
+func (s *Server) WaitForInFlightCreates(ctx context.Context) {
+	s.inFlightCreates.drain(ctx, s.config.ShutdownTimeout)
+}
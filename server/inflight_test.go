@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInFlightCreatesDrainWaits(t *testing.T) {
+	sut := newInFlightCreates()
+
+	_, done, err := sut.start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		sut.drain(context.Background(), time.Minute)
+		close(drained)
+	}()
+
+	// New creations are refused as soon as the drain started.
+	for {
+		_, probeDone, err := sut.start(context.Background())
+		if errors.Is(err, ErrShuttingDown) {
+			if code := status.Code(err); code != codes.Unavailable {
+				t.Errorf("expected the %v gRPC status code, got %v", codes.Unavailable, code)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		probeDone()
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned while a creation is still in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	done()
+	select {
+	case <-drained:
+	case <-time.After(time.Minute):
+		t.Fatal("drain did not return after the creation finished")
+	}
+}
+
+func TestInFlightCreatesDrainCancelsAfterTimeout(t *testing.T) {
+	sut := newInFlightCreates()
+
+	ctx, done, err := sut.start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aborted := make(chan error, 1)
+	go func() {
+		defer done()
+		<-ctx.Done()
+		aborted <- ctx.Err()
+	}()
+
+	sut.drain(context.Background(), 10*time.Millisecond)
+
+	if err := <-aborted; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the creation to be canceled, got %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	json "github.com/json-iterator/go"
 	"github.com/sirupsen/logrus"
 
+	"github.com/L-F-Z/cri-t/internal/config/nsmgr"
 	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
 	"github.com/L-F-Z/cri-t/internal/log"
 	"github.com/L-F-Z/cri-t/internal/oci"
@@ -94,9 +95,23 @@ func (s *Server) getContainerInfo(ctx context.Context, id string, getContainerFu
 	}, nil
 }
 
+func getSandboxNamespaces(sb *sandbox.Sandbox) []types.NamespaceInfo {
+	namespaces := sb.ManagedNamespaces()
+	infos := make([]types.NamespaceInfo, 0, len(namespaces))
+	for _, ns := range namespaces {
+		infos = append(infos, types.NamespaceInfo{
+			Type:   string(ns.Type()),
+			Path:   ns.Path(),
+			Pinned: nsmgr.IsNamespacePinned(ns.Path()),
+		})
+	}
+	return infos
+}
+
 const (
 	InspectConfigEndpoint     = "/config"
 	InspectContainersEndpoint = "/containers"
+	InspectSandboxesEndpoint  = "/sandboxes"
 	InspectInfoEndpoint       = "/info"
 	InspectPauseEndpoint      = "/pause"
 	InspectUnpauseEndpoint    = "/unpause"
@@ -162,6 +177,24 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 		}
 	}))
 
+	mux.Get(InspectSandboxesEndpoint+"/{id}/namespaces", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sandboxID := chi.URLParam(req, "id")
+		sb, err := s.LookupSandbox(sandboxID)
+		if err != nil {
+			http.Error(w, "can't find the sandbox with id "+sandboxID, http.StatusNotFound)
+			return
+		}
+		js, err := json.Marshal(getSandboxNamespaces(sb))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	mux.Get(InspectPauseEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		containerID := chi.URLParam(req, "id")
 		ctx := context.TODO()
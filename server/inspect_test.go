@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	json "github.com/json-iterator/go"
+
+	"github.com/L-F-Z/cri-t/pkg/types"
+)
+
+func TestInspectSandboxNamespaces(t *testing.T) {
+	sut := newTestServer(t)
+	resp, err := sut.runPodSandbox(context.Background(), newTestRunPodSandboxRequest("pod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := sut.GetExtendInterfaceMux(false)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, InspectSandboxesEndpoint+"/"+resp.PodSandboxId+"/namespaces", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	var infos []types.NamespaceInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &infos); err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) == 0 {
+		t.Fatal("expected the managed namespaces of the sandbox")
+	}
+	for _, info := range infos {
+		if info.Type == "" || info.Path == "" || !info.Pinned {
+			t.Errorf("expected a pinned namespace, got %+v", info)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, InspectSandboxesEndpoint+"/unknown/namespaces", http.NoBody))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for an unknown sandbox, got %d", http.StatusNotFound, rec.Code)
+	}
+}
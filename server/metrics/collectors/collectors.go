@@ -63,6 +63,9 @@ const (
 
 	// ResourcesStalledAtStage is the key for the resources stalled at different stages in container and pod creation.
 	ResourcesStalledAtStage Collector = crioPrefix + "resources_stalled_at_stage"
+
+	// ResourceStageDurationSeconds is the key for the duration of the stages of container and pod creations.
+	ResourceStageDurationSeconds Collector = crioPrefix + "resource_stage_duration_seconds"
 )
 
 // FromSlice converts a string slice to a Collectors type.
@@ -101,6 +104,7 @@ func All() Collectors {
 		ContainersOOMCountTotal.Stripped(),
 		ContainersSeccompNotifierCountTotal.Stripped(),
 		ResourcesStalledAtStage.Stripped(),
+		ResourceStageDurationSeconds.Stripped(),
 	}
 }
 
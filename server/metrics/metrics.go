@@ -78,6 +78,7 @@ type Metrics struct {
 	metricContainersOOMCountTotal             *prometheus.CounterVec
 	metricContainersSeccompNotifierCountTotal *prometheus.CounterVec
 	metricResourcesStalledAtStage             *prometheus.CounterVec
+	metricResourceStageDurationSeconds        *prometheus.GaugeVec
 }
 
 var instance *Metrics
@@ -234,6 +235,16 @@ func New(config *libconfig.MetricsConfig) *Metrics {
 			},
 			[]string{"stage"},
 		),
+		metricResourceStageDurationSeconds: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ResourceStageDurationSeconds.String(),
+				Help:      "Duration of the creation stages of pods and containers by their name and stage.",
+			},
+			// The label `name` has the cardinality of the pods and containers on the node. The values of a
+			// resource are deleted again once it got removed.
+			[]string{"name", "stage"},
+		),
 	}
 	return Instance()
 }
@@ -396,6 +407,19 @@ func (m *Metrics) MetricResourcesStalledAtStage(stage string) {
 	c.Inc()
 }
 
+func (m *Metrics) MetricResourceStageDurationSet(name, stage string, seconds float64) {
+	c, err := m.metricResourceStageDurationSeconds.GetMetricWithLabelValues(name, stage)
+	if err != nil {
+		logrus.Warnf("Unable to write resource stage duration metric: %v", err)
+		return
+	}
+	c.Set(seconds)
+}
+
+func (m *Metrics) MetricResourceStageDurationDelete(name string) {
+	m.metricResourceStageDurationSeconds.DeletePartialMatch(prometheus.Labels{"name": name})
+}
+
 // createEndpoint creates a /metrics endpoint for prometheus monitoring.
 func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 	for collector, metric := range map[collectors.Collector]prometheus.Collector{
@@ -415,6 +439,7 @@ func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 		collectors.OperationsTotal:                     m.metricOperationsTotal,
 		collectors.ProcessesDefunct:                    m.metricProcessesDefunct,
 		collectors.ResourcesStalledAtStage:             m.metricResourcesStalledAtStage,
+		collectors.ResourceStageDurationSeconds:        m.metricResourceStageDurationSeconds,
 	} {
 		if m.config.MetricsCollectors.Contains(collector) {
 			logrus.Debugf("Enabling metric: %s", collector.Stripped())
@@ -454,7 +479,7 @@ func (m *Metrics) startEndpoint(
 			}
 
 			var cc *cert.Config
-			cc, err = cert.NewCertConfig(ctx, stop, m.config.MetricsCert, m.config.MetricsKey, "")
+			cc, err = cert.NewCertConfig(ctx, stop, m.config.MetricsCert, m.config.MetricsKey, "", false)
 			if err != nil {
 				log.Fatalf(ctx, "Creating key pair reloader: %v", err)
 			}
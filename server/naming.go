@@ -2,29 +2,19 @@ package server
 
 import (
 	"errors"
-	"strconv"
-	"strings"
 
 	"github.com/containers/storage/pkg/stringid"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
+	"github.com/L-F-Z/cri-t/internal/naming"
 	"github.com/L-F-Z/cri-t/internal/oci"
 )
 
-const (
-	kubePrefix    = "k8s"
-	nameDelimiter = "_"
-)
-
-func makeSandboxContainerName(sandboxConfig *types.PodSandboxConfig) string {
-	return strings.Join([]string{
-		kubePrefix,
-		oci.InfraContainerName,
-		sandboxConfig.Metadata.Name,
-		sandboxConfig.Metadata.Namespace,
-		sandboxConfig.Metadata.Uid,
-		strconv.FormatUint(uint64(sandboxConfig.Metadata.Attempt), 10),
-	}, nameDelimiter)
+func makeSandboxContainerName(strategy naming.Strategy, sandboxConfig *types.PodSandboxConfig) string {
+	return strategy.ContainerName(&types.ContainerMetadata{
+		Name:    oci.InfraContainerName,
+		Attempt: sandboxConfig.Metadata.Attempt,
+	}, sandboxConfig.Metadata)
 }
 
 func (s *Server) ReserveSandboxContainerIDAndName(config *types.PodSandboxConfig) (string, error) {
@@ -32,8 +22,13 @@ func (s *Server) ReserveSandboxContainerIDAndName(config *types.PodSandboxConfig
 		return "", errors.New("cannot generate sandbox container name without metadata")
 	}
 
+	strategy, err := s.config.Naming()
+	if err != nil {
+		return "", err
+	}
+
 	id := stringid.GenerateNonCryptoID()
-	name, err := s.ReserveContainerName(id, makeSandboxContainerName(config))
+	name, err := s.ReserveContainerName(id, makeSandboxContainerName(strategy, config))
 	if err != nil {
 		return "", err
 	}
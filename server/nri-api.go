@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/containerd/nri/pkg/api"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/L-F-Z/cri-t/internal/config/cgmgr"
 	"github.com/L-F-Z/cri-t/internal/config/node"
+	nriconfig "github.com/L-F-Z/cri-t/internal/config/nri"
 	"github.com/L-F-Z/cri-t/internal/config/rdt"
 	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
 	"github.com/L-F-Z/cri-t/internal/log"
@@ -41,17 +43,52 @@ func (a *nriAPI) isEnabled() bool {
 	return a != nil && a.nri != nil && a.nri.IsEnabled()
 }
 
+// errNRIAdjustment is wrapped by the errors of applying an NRI adjustment to
+// a container spec. The spec may be partially adjusted at that point, so such
+// errors fail the creation regardless of the failure policy.
+var errNRIAdjustment = errors.New("NRI adjustment failed")
+
+// runNRIHook runs the NRI hook fn within the configured NRI call timeout and
+// applies the failure policy to its error.
+func (s *Server) runNRIHook(ctx context.Context, hook string, policy nriconfig.FailurePolicy, fn func(context.Context) error) error {
+	err := runStage(ctx, "NRI "+hook, s.config.NRI.CallTimeout, fn)
+	if err == nil {
+		return nil
+	}
+
+	if policy == nriconfig.FailurePolicyIgnore && !errors.Is(err, errNRIAdjustment) && ctx.Err() == nil {
+		log.Warnf(ctx, "Ignoring failed NRI %s hook: %v", hook, err)
+		return nil
+	}
+	return fmt.Errorf("NRI %s hook failed: %w", hook, err)
+}
+
+// skipNRI returns true if NRI processing has been disabled for the pod or
+// the container by the SkipNRI annotation. Both arguments may be nil.
+func skipNRI(criPod *sandbox.Sandbox, criCtr *oci.Container) bool {
+	if criPod != nil && criPod.Annotations()[annotations.SkipNRIAnnotation] == "true" {
+		return true
+	}
+	return criCtr != nil && criCtr.Annotations()[annotations.SkipNRIAnnotation] == "true"
+}
+
 //
 // CRI 'downward' interface for NRI
 //
 // These functions are used in the CRI plugin to hook NRI processing into
-// the corresponding CRI pod and container lifecycle events.
+// the corresponding CRI pod and container lifecycle events. Pods and
+// containers annotated to skip NRI are never passed to NRI, so neither are
+// their later lifecycle events nor the undo of their creation.
 //
 
 func (a *nriAPI) runPodSandbox(ctx context.Context, criPod *sandbox.Sandbox) error {
 	if !a.isEnabled() {
 		return nil
 	}
+	if skipNRI(criPod, nil) {
+		log.Infof(ctx, "Skipping NRI for pod sandbox %s", criPod.ID())
+		return nil
+	}
 
 	pod := nriPodSandbox(ctx, criPod)
 	err := a.nri.RunPodSandbox(ctx, pod)
@@ -68,7 +105,7 @@ func (a *nriAPI) runPodSandbox(ctx context.Context, criPod *sandbox.Sandbox) err
 }
 
 func (a *nriAPI) stopPodSandbox(ctx context.Context, criPod *sandbox.Sandbox) error {
-	if !a.isEnabled() {
+	if !a.isEnabled() || skipNRI(criPod, nil) {
 		return nil
 	}
 
@@ -78,7 +115,7 @@ func (a *nriAPI) stopPodSandbox(ctx context.Context, criPod *sandbox.Sandbox) er
 }
 
 func (a *nriAPI) removePodSandbox(ctx context.Context, criPod *sandbox.Sandbox) error {
-	if !a.isEnabled() {
+	if !a.isEnabled() || skipNRI(criPod, nil) {
 		return nil
 	}
 
@@ -91,6 +128,10 @@ func (a *nriAPI) createContainer(ctx context.Context, specgen *generate.Generato
 	if !a.isEnabled() {
 		return nil
 	}
+	if skipNRI(criPod, criCtr) {
+		log.Infof(ctx, "Skipping NRI for container %s", criCtr.ID())
+		return nil
+	}
 
 	pod := nriPodSandbox(ctx, criPod)
 	ctr := &criContainer{
@@ -163,14 +204,14 @@ func (a *nriAPI) createContainer(ctx context.Context, specgen *generate.Generato
 	)
 
 	if err := wrapgen.Adjust(adjust); err != nil {
-		return fmt.Errorf("failed to adjust container %s: %w", ctr.GetID(), err)
+		return fmt.Errorf("%w: failed to adjust container %s: %w", errNRIAdjustment, ctr.GetID(), err)
 	}
 
 	return nil
 }
 
 func (a *nriAPI) postCreateContainer(ctx context.Context, criPod *sandbox.Sandbox, criCtr *oci.Container) error {
-	if !a.isEnabled() {
+	if !a.isEnabled() || skipNRI(criPod, criCtr) {
 		return nil
 	}
 
@@ -184,7 +225,7 @@ func (a *nriAPI) postCreateContainer(ctx context.Context, criPod *sandbox.Sandbo
 }
 
 func (a *nriAPI) startContainer(ctx context.Context, criPod *sandbox.Sandbox, criCtr *oci.Container) error {
-	if !a.isEnabled() {
+	if !a.isEnabled() || skipNRI(criPod, criCtr) {
 		return nil
 	}
 
@@ -198,7 +239,7 @@ func (a *nriAPI) startContainer(ctx context.Context, criPod *sandbox.Sandbox, cr
 }
 
 func (a *nriAPI) postStartContainer(ctx context.Context, criPod *sandbox.Sandbox, criCtr *oci.Container) error {
-	if !a.isEnabled() {
+	if !a.isEnabled() || skipNRI(criPod, criCtr) {
 		return nil
 	}
 
@@ -219,6 +260,9 @@ func (a *nriAPI) updateContainer(ctx context.Context, criCtr *oci.Container, req
 	const noOomAdj = 0
 
 	criPod := a.cri.getSandbox(ctx, criCtr.Sandbox())
+	if skipNRI(criPod, criCtr) {
+		return req, nil
+	}
 	pod := nriPodSandbox(ctx, criPod)
 	ctr := &criContainer{
 		api: a,
@@ -239,6 +283,9 @@ func (a *nriAPI) postUpdateContainer(ctx context.Context, criCtr *oci.Container)
 	}
 
 	criPod := a.cri.getSandbox(ctx, criCtr.Sandbox())
+	if skipNRI(criPod, criCtr) {
+		return nil
+	}
 	pod := nriPodSandbox(ctx, criPod)
 	ctr := &criContainer{
 		api: a,
@@ -273,13 +320,16 @@ func (a *nriAPI) stopContainer(ctx context.Context, criPod *sandbox.Sandbox, cri
 		}
 	}
 
+	if skipNRI(criPod, criCtr) {
+		return nil
+	}
 	pod := nriPodSandbox(ctx, criPod)
 
 	return a.nri.StopContainer(ctx, pod, ctr)
 }
 
 func (a *nriAPI) removeContainer(ctx context.Context, criPod *sandbox.Sandbox, criCtr *oci.Container) error {
-	if !a.isEnabled() {
+	if !a.isEnabled() || skipNRI(criPod, criCtr) {
 		return nil
 	}
 
@@ -293,7 +343,7 @@ func (a *nriAPI) removeContainer(ctx context.Context, criPod *sandbox.Sandbox, c
 }
 
 func (a *nriAPI) undoCreateContainer(ctx context.Context, specgen *generate.Generator, criPod *sandbox.Sandbox, criCtr *oci.Container) {
-	if !a.isEnabled() {
+	if !a.isEnabled() || skipNRI(criPod, criCtr) {
 		return
 	}
 
@@ -332,7 +382,7 @@ func (a *nriAPI) GetName() string {
 func (a *nriAPI) ListPodSandboxes(ctx context.Context) []nri.PodSandbox {
 	pods := []nri.PodSandbox{}
 	for _, pod := range a.cri.ContainerServer.ListSandboxes() {
-		if pod.Created() {
+		if pod.Created() && !skipNRI(pod, nil) {
 			pods = append(pods, nriPodSandbox(ctx, pod))
 		}
 	}
@@ -346,6 +396,9 @@ func (a *nriAPI) ListContainers() []nri.Container {
 		log.Warnf(context.TODO(), "Failed to list containers: %v", err)
 	}
 	for _, ctr := range ctrList {
+		if skipNRI(a.cri.ContainerServer.GetSandbox(ctr.Sandbox()), ctr) {
+			continue
+		}
 		switch ctr.State().Status {
 		case oci.ContainerStateCreated, oci.ContainerStateRunning, oci.ContainerStatePaused:
 			containers = append(containers, &criContainer{
@@ -395,6 +448,11 @@ func (a *nriAPI) UpdateContainer(ctx context.Context, u *api.ContainerUpdate) er
 		return nil
 	}
 
+	if skipNRI(a.cri.ContainerServer.GetSandbox(ctr.Sandbox()), ctr) {
+		log.Warnf(ctx, "Ignoring NRI update of CRI container %q which skips NRI", u.GetContainerId())
+		return nil
+	}
+
 	resources := u.GetLinux().GetResources().ToOCI()
 	if err = a.cri.Runtime().UpdateContainer(ctx, ctr, resources); err != nil {
 		log.Errorf(ctx, "Failed to update CRI container %q: %v", u.GetContainerId(), err)
@@ -404,7 +462,9 @@ func (a *nriAPI) UpdateContainer(ctx context.Context, u *api.ContainerUpdate) er
 		return fmt.Errorf("failed to update CRI container %q: %w", u.GetContainerId(), err)
 	}
 
-	a.cri.UpdateContainerLinuxResources(ctr, resources)
+	if err := a.cri.UpdateContainerLinuxResources(ctr, resources); err != nil {
+		log.Errorf(ctx, "Failed to store update of CRI container %q: %v", u.GetContainerId(), err)
+	}
 
 	return nil
 }
@@ -416,6 +476,10 @@ func (a *nriAPI) EvictContainer(ctx context.Context, e *api.ContainerEviction) e
 		log.Errorf(ctx, "Failed to evict CRI container %q: %v", e.GetContainerId(), err)
 		return nil
 	}
+	if skipNRI(a.cri.ContainerServer.GetSandbox(ctr.Sandbox()), ctr) {
+		log.Warnf(ctx, "Ignoring NRI eviction of CRI container %q which skips NRI", e.GetContainerId())
+		return nil
+	}
 	if err = a.cri.stopContainer(ctx, ctr, 0); err != nil {
 		log.Errorf(ctx, "Failed to evict CRI container %q: %v", e.GetContainerId(), err)
 		return err
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/runtime-tools/generate"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	nriconfig "github.com/L-F-Z/cri-t/internal/config/nri"
+	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
+	"github.com/L-F-Z/cri-t/internal/nri"
+	"github.com/L-F-Z/cri-t/internal/oci"
+	crioann "github.com/L-F-Z/cri-t/pkg/annotations"
+)
+
+func TestRunNRIHook(t *testing.T) {
+	errPlugin := errors.New("plugin failed")
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	failing := func(context.Context) error {
+		return errPlugin
+	}
+	adjustFailing := func(context.Context) error {
+		return fmt.Errorf("%w: invalid adjustment", errNRIAdjustment)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		policy  nriconfig.FailurePolicy
+		hook    func(context.Context) error
+		wantErr error
+	}{
+		{"hanging with fail policy", nriconfig.FailurePolicyFail, hanging, context.DeadlineExceeded},
+		{"failing with fail policy", nriconfig.FailurePolicyFail, failing, errPlugin},
+		{"hanging with ignore policy", nriconfig.FailurePolicyIgnore, hanging, nil},
+		{"failing with ignore policy", nriconfig.FailurePolicyIgnore, failing, nil},
+		{"failing adjustment with ignore policy", nriconfig.FailurePolicyIgnore, adjustFailing, errNRIAdjustment},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := &Server{}
+			sut.config.NRI = nriconfig.New()
+			sut.config.NRI.CallTimeout = 10 * time.Millisecond
+
+			err := sut.runNRIHook(context.Background(), "CreateContainer", tc.policy, tc.hook)
+
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected the failure to be ignored, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunNRIHookTimeoutNamesHook(t *testing.T) {
+	sut := &Server{}
+	sut.config.NRI = nriconfig.New()
+	sut.config.NRI.CallTimeout = 10 * time.Millisecond
+
+	err := sut.runNRIHook(context.Background(), "RunPodSandbox", nriconfig.FailurePolicyFail, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var timeoutErr *StageTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a stage timeout error, got %v", err)
+	}
+	if timeoutErr.Stage != "NRI RunPodSandbox" {
+		t.Errorf("expected stage %q, got %q", "NRI RunPodSandbox", timeoutErr.Stage)
+	}
+}
+
+func TestRunNRIHookIgnorePolicyKeepsRequestCancellation(t *testing.T) {
+	sut := &Server{}
+	sut.config.NRI = nriconfig.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := sut.runNRIHook(ctx, "RunPodSandbox", nriconfig.FailurePolicyIgnore, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the canceled request to fail, got %v", err)
+	}
+}
+
+// recordingNRI records the invoked NRI hooks. Hooks it does not implement
+// panic through the nil embedded interface.
+type recordingNRI struct {
+	nri.API
+	calls []string
+}
+
+func (r *recordingNRI) IsEnabled() bool { return true }
+
+func (r *recordingNRI) RunPodSandbox(context.Context, nri.PodSandbox) error {
+	r.calls = append(r.calls, "RunPodSandbox")
+	return nil
+}
+
+func (r *recordingNRI) StopPodSandbox(context.Context, nri.PodSandbox) error {
+	r.calls = append(r.calls, "StopPodSandbox")
+	return nil
+}
+
+func (r *recordingNRI) RemovePodSandbox(context.Context, nri.PodSandbox) error {
+	r.calls = append(r.calls, "RemovePodSandbox")
+	return nil
+}
+
+func (r *recordingNRI) StopContainer(context.Context, nri.PodSandbox, nri.Container) error {
+	r.calls = append(r.calls, "StopContainer")
+	return nil
+}
+
+func (r *recordingNRI) RemoveContainer(context.Context, nri.PodSandbox, nri.Container) error {
+	r.calls = append(r.calls, "RemoveContainer")
+	return nil
+}
+
+func newTestSandbox(t *testing.T, annotations map[string]string) *sandbox.Sandbox {
+	t.Helper()
+	builder := sandbox.NewBuilder()
+	builder.SetID("sandboxID")
+	builder.SetName("sandboxName")
+	builder.SetCreatedAt(time.Now())
+	if err := builder.SetCRISandbox("sandboxID", map[string]string{}, annotations, &types.PodSandboxMetadata{}); err != nil {
+		t.Fatal(err)
+	}
+	sb, err := builder.GetSandbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sb
+}
+
+func newNRITestContainer(t *testing.T, annotations map[string]string) *oci.Container {
+	t.Helper()
+	ctr, err := oci.NewContainer("containerID", "", "", "",
+		map[string]string{}, map[string]string{}, annotations, "", nil, nil, "",
+		&types.ContainerMetadata{}, "sandboxID", false, false,
+		false, "", "", time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctr
+}
+
+func TestNRISkipAnnotation(t *testing.T) {
+	skip := map[string]string{crioann.SkipNRIAnnotation: "true"}
+
+	for _, tc := range []struct {
+		name           string
+		podAnnotations map[string]string
+		ctrAnnotations map[string]string
+	}{
+		{"pod annotation", skip, map[string]string{}},
+		{"container annotation", map[string]string{}, skip},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			recorder := &recordingNRI{}
+			sut := &nriAPI{nri: recorder}
+			sb := newTestSandbox(t, tc.podAnnotations)
+			ctr := newNRITestContainer(t, tc.ctrAnnotations)
+			specgen, err := generate.New("linux")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tc.ctrAnnotations) == 0 {
+				if err := sut.runPodSandbox(ctx, sb); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := sut.createContainer(ctx, &specgen, sb, ctr); err != nil {
+				t.Fatal(err)
+			}
+			sut.undoCreateContainer(ctx, &specgen, sb, ctr)
+			for _, hook := range []func(context.Context, *sandbox.Sandbox, *oci.Container) error{
+				sut.postCreateContainer,
+				sut.startContainer,
+				sut.postStartContainer,
+				sut.stopContainer,
+				sut.removeContainer,
+			} {
+				if err := hook(ctx, sb, ctr); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(tc.ctrAnnotations) == 0 {
+				if err := sut.stopPodSandbox(ctx, sb); err != nil {
+					t.Fatal(err)
+				}
+				if err := sut.removePodSandbox(ctx, sb); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if len(recorder.calls) != 0 {
+				t.Errorf("expected NRI not to be invoked, got %v", recorder.calls)
+			}
+		})
+	}
+}
+
+func TestNRIWithoutSkipAnnotation(t *testing.T) {
+	ctx := context.Background()
+	recorder := &recordingNRI{}
+	sut := &nriAPI{nri: recorder}
+	sb := newTestSandbox(t, map[string]string{crioann.SkipNRIAnnotation: "false"})
+	ctr := newNRITestContainer(t, map[string]string{})
+	specgen, err := generate.New("linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sut.runPodSandbox(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	sut.undoCreateContainer(ctx, &specgen, sb, ctr)
+
+	want := []string{"RunPodSandbox", "StopContainer", "RemoveContainer"}
+	if fmt.Sprint(recorder.calls) != fmt.Sprint(want) {
+		t.Errorf("expected NRI calls %v, got %v", want, recorder.calls)
+	}
+}
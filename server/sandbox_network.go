@@ -25,19 +25,6 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	overallStart := time.Now()
-	// Give a network Start call a full 5 minutes, independent of the context of the request.
-	// This is to prevent the CNI plugin from taking an unbounded amount of time,
-	// but to still allow a long-running sandbox creation to be cached and reused,
-	// rather than failing and recreating it.
-	// Adding on top of the specified deadline ensures this deadline will be respected, regardless of
-	// how Kubelet's runtime-request-timeout changes.
-	startTimeout := 5 * time.Minute
-	if initialDeadline, ok := ctx.Deadline(); ok {
-		startTimeout += time.Until(initialDeadline)
-	}
-	startCtx, startCancel := context.WithTimeout(context.Background(), startTimeout)
-	defer startCancel()
-
 	if sb.HostNetwork() {
 		return nil, nil, nil
 	}
@@ -59,17 +46,23 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 		}
 	}()
 
-	podSetUpStart := time.Now()
-	_, err = s.config.CNIPlugin().SetUpPodWithContext(startCtx, podNetwork)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create pod network sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
-	}
-	// metric about the CNI network setup operation
-	metrics.Instance().MetricOperationsLatencySet("network_setup_pod", podSetUpStart)
+	var podNetworkStatus []ocicni.NetResult
+	// The setup is bounded by the request, and by setup_timeout if set.
+	if err := runStage(ctx, "sandbox network creation", s.config.NetworkConfig.SetupTimeout, func(startCtx context.Context) error {
+		podSetUpStart := time.Now()
+		if _, err := s.config.CNIPlugin().SetUpPodWithContext(startCtx, podNetwork); err != nil {
+			return fmt.Errorf("failed to create pod network sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+		}
+		// metric about the CNI network setup operation
+		metrics.Instance().MetricOperationsLatencySet("network_setup_pod", podSetUpStart)
 
-	podNetworkStatus, err := s.config.CNIPlugin().GetPodNetworkStatusWithContext(startCtx, podNetwork)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get network status for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+		podNetworkStatus, err = s.config.CNIPlugin().GetPodNetworkStatusWithContext(startCtx, podNetwork)
+		if err != nil {
+			return fmt.Errorf("failed to get network status for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
 	}
 
 	// only one cnitypes.Result is returned since newPodNetwork sets Networks list empty
@@ -278,15 +271,58 @@ func (s *Server) networkGC(ctx context.Context, validPods []*sandbox.Sandbox) er
 	})
 }
 
-// WaitForCNIPlugin waits for the CNI plugin to be ready.
-func (s *Server) waitForCNIPlugin(ctx context.Context, sboxName string) error {
-	if err := s.config.CNIPluginReadyOrError(); err != nil {
-		watcher := s.config.CNIPluginAddWatcher()
-		log.Infof(ctx, "CNI plugin not ready. Waiting to create %s", sboxName)
-		if ready := <-watcher; !ready {
-			return fmt.Errorf("server shutdown before CNI plugin was ready: %w", err)
+// ErrCNIPluginNotReady is returned if the CNI plugin did not get ready within
+// the plugin_wait_timeout. Clients get it with the Unavailable gRPC status
+// code, so that the kubelet retries the creation.
+var ErrCNIPluginNotReady error = unavailableError("CNI plugin not ready")
+
+// cniPluginPollInterval is the interval of the debug logs while waiting for
+// the CNI plugin.
+const cniPluginPollInterval = time.Second
+
+// waitForCNIPlugin waits for the CNI plugin to be ready. Waiting longer than
+// timeout fails with ErrCNIPluginNotReady, a zero timeout waits until the
+// plugin gets ready, the server shuts down or ctx is done.
+func (s *Server) waitForCNIPlugin(ctx context.Context, sboxName string, timeout time.Duration) error {
+	err := s.config.CNIPluginReadyOrError()
+	if err == nil {
+		return nil
+	}
+	watcher := s.config.CNIPluginAddWatcher()
+	defer s.config.CNIPluginRemoveWatcher(watcher)
+	// The plugin may have gotten ready before the watcher was added.
+	if err = s.config.CNIPluginReadyOrError(); err == nil {
+		return nil
+	}
+	log.Infof(ctx, "CNI plugin not ready. Waiting to create %s", sboxName)
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	ticker := time.NewTicker(cniPluginPollInterval)
+	defer ticker.Stop()
+	start := time.Now()
+
+	for {
+		select {
+		case ready := <-watcher:
+			if !ready {
+				return fmt.Errorf("server shutdown before CNI plugin was ready: %w", err)
+			}
+			log.Infof(ctx, "CNI plugin is now ready. Continuing to create %s", sboxName)
+			return nil
+		case <-ticker.C:
+			if lastErr := s.config.CNIPluginReadyOrError(); lastErr != nil {
+				err = lastErr
+			}
+			log.Debugf(ctx, "Waiting for CNI plugin to create %s for %s: %v", sboxName, time.Since(start).Round(time.Millisecond), err)
+		case <-expired:
+			return fmt.Errorf("%w after waiting %s to create %s: %w", ErrCNIPluginNotReady, timeout, sboxName, err)
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for CNI plugin to create %s: %w", sboxName, ctx.Err())
 		}
-		log.Infof(ctx, "CNI plugin is now ready. Continuing to create %s", sboxName)
 	}
-	return nil
 }
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// notReadyCNIPlugin is a CNI plugin which never gets ready. Calls it does not
+// implement panic through the nil embedded interface.
+type notReadyCNIPlugin struct {
+	ocicni.CNIPlugin
+}
+
+var errCNIConfigMissing = errors.New("no CNI configuration file")
+
+func (notReadyCNIPlugin) Status() error { return errCNIConfigMissing }
+
+func (notReadyCNIPlugin) Shutdown() error { return nil }
+
+func TestWaitForCNIPluginTimeout(t *testing.T) {
+	sut := &Server{}
+	if err := sut.config.SetCNIPlugin(notReadyCNIPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := sut.waitForCNIPlugin(context.Background(), "sandbox", 10*time.Millisecond)
+
+	if !errors.Is(err, ErrCNIPluginNotReady) {
+		t.Fatalf("expected %v, got %v", ErrCNIPluginNotReady, err)
+	}
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf("expected the %v gRPC status code, got %v", codes.Unavailable, code)
+	}
+	if !errors.Is(err, errCNIConfigMissing) {
+		t.Errorf("expected the error to name the plugin status, got %v", err)
+	}
+}
+
+func TestWaitForCNIPluginCanceled(t *testing.T) {
+	sut := &Server{}
+	if err := sut.config.SetCNIPlugin(notReadyCNIPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := sut.waitForCNIPlugin(ctx, "sandbox", 0)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the wait to be canceled, got %v", err)
+	}
+}
+
+func TestRunPodSandboxWaitsForCNIPluginWithinRequest(t *testing.T) {
+	sut := newTestServer(t)
+	if err := sut.config.SetCNIPlugin(notReadyCNIPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	sut.config.PluginWaitTimeout = 0
+	req := newTestRunPodSandboxRequest("pod")
+	req.Config.Linux.SecurityContext.NamespaceOptions.Network = types.NamespaceMode_POD
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := sut.runPodSandbox(ctx, req)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to end with the request, got %v", err)
+	}
+	if errors.Is(err, ErrCNIPluginNotReady) {
+		t.Errorf("expected no plugin wait timeout, got %v", err)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/L-F-Z/cri-t/internal/lib/sandbox"
 	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/server/metrics"
 )
 
 // RemovePodSandbox deletes the sandbox. If there are any running containers in the
@@ -45,10 +48,19 @@ func (s *Server) removePodSandbox(ctx context.Context, sb *sandbox.Sandbox) erro
 	containers := sb.Containers().List()
 
 	// Delete all the containers in the sandbox
-	for _, c := range containers {
-		if err := s.removeContainerInPod(ctx, sb, c); err != nil {
-			return err
+	if err := removeContainers(containers, func(c *oci.Container) error {
+		return s.removeContainerInPod(ctx, sb, c)
+	}); err != nil {
+		// Keep the sandbox so that the removal can be retried, but still
+		// release its network and namespaces.
+		errs := []error{err}
+		if err := s.networkStop(ctx, sb); err != nil {
+			errs = append(errs, fmt.Errorf("stop pod network: %w", err))
 		}
+		if err := sb.RemoveManagedNamespaces(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to remove managed namespaces: %w", err))
+		}
+		return utilerrors.NewAggregate(errs)
 	}
 
 	if err := sb.UnmountShm(ctx); err != nil {
@@ -75,6 +87,7 @@ func (s *Server) removePodSandbox(ctx context.Context, sb *sandbox.Sandbox) erro
 	}
 
 	s.ReleasePodName(sb.Name())
+	metrics.Instance().MetricResourceStageDurationDelete(sb.Name())
 	if err := s.removeSandbox(ctx, sb.ID()); err != nil {
 		log.Warnf(ctx, "Failed to remove sandbox: %v", err)
 	}
@@ -90,3 +103,16 @@ func (s *Server) removePodSandbox(ctx context.Context, sb *sandbox.Sandbox) erro
 	log.Infof(ctx, "Removed pod sandbox: %s", sb.ID())
 	return nil
 }
+
+// removeContainers calls remove for all the given containers. A failing
+// container does not prevent the removal of the others, the errors of all the
+// failed containers are aggregated.
+func removeContainers(containers []*oci.Container, remove func(*oci.Container) error) error {
+	var errs []error
+	for _, c := range containers {
+		if err := remove(c); err != nil {
+			errs = append(errs, fmt.Errorf("remove container %s: %w", c.ID(), err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+func TestRemoveContainersAttemptsAll(t *testing.T) {
+	errStuck := errors.New("stuck")
+	var containers []*oci.Container
+	for _, id := range []string{"clean1", "stuck1", "clean2", "stuck2"} {
+		containers = append(containers, oci.NewSpoofedContainer(id, id, nil, "sandbox", time.Now(), t.TempDir()))
+	}
+
+	var attempted []string
+	err := removeContainers(containers, func(c *oci.Container) error {
+		attempted = append(attempted, c.ID())
+		if strings.HasPrefix(c.ID(), "stuck") {
+			return errStuck
+		}
+		return nil
+	})
+
+	if want := []string{"clean1", "stuck1", "clean2", "stuck2"}; !slices.Equal(attempted, want) {
+		t.Errorf("attempted %v, want %v", attempted, want)
+	}
+	if !errors.Is(err, errStuck) {
+		t.Fatalf("expected the container errors, got %v", err)
+	}
+	for _, id := range []string{"stuck1", "stuck2"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("expected error %q to mention %s", err, id)
+		}
+	}
+	if strings.Contains(err.Error(), "clean") {
+		t.Errorf("expected error %q to only mention failed containers", err)
+	}
+}
+
+func TestRemoveContainersSucceeds(t *testing.T) {
+	containers := []*oci.Container{
+		oci.NewSpoofedContainer("ctr", "ctr", nil, "sandbox", time.Now(), t.TempDir()),
+	}
+
+	if err := removeContainers(containers, func(*oci.Container) error { return nil }); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
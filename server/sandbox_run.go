@@ -12,11 +12,6 @@ import (
 )
 
 const (
-	// PodInfraOOMAdj is the value that we set for oom score adj for
-	// the pod infra container.
-	// TODO: Remove this const once this value is provided over CRI
-	// See https://github.com/kubernetes/kubernetes/issues/47938
-	PodInfraOOMAdj int = -998
 	// PodInfraCPUshares is default cpu shares for sandbox container.
 	PodInfraCPUshares = 2
 )
@@ -66,6 +61,12 @@ func (s *Server) runtimeHandler(req *types.RunPodSandboxRequest) (string, error)
 
 // RunPodSandbox creates and runs a pod-level sandbox.
 func (s *Server) RunPodSandbox(ctx context.Context, req *types.RunPodSandboxRequest) (*types.RunPodSandboxResponse, error) {
+	ctx, done, err := s.inFlightCreates.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
 	// platform dependent call
 	return s.runPodSandbox(ctx, req)
 }
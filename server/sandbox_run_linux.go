@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +20,7 @@ import (
 	"github.com/opencontainers/selinux/go-selinux/label"
 	"golang.org/x/sys/unix"
 	"k8s.io/apimachinery/pkg/api/resource"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 	kubeletTypes "k8s.io/kubelet/pkg/types"
 
@@ -34,12 +37,19 @@ import (
 	"github.com/L-F-Z/cri-t/internal/storage"
 	"github.com/L-F-Z/cri-t/pkg/annotations"
 	libconfig "github.com/L-F-Z/cri-t/pkg/config"
+	"github.com/L-F-Z/cri-t/server/metrics"
 	"github.com/L-F-Z/cri-t/utils"
 )
 
 func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequest) (resp *types.RunPodSandboxResponse, retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+	releaseSlot, err := s.sandboxSlots.reserve("pod sandboxes", s.ContainerServer.SandboxCount, s.config.MaxPods)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseSlot()
+
 	sbox := libsandbox.NewBuilder()
 	if err := sbox.SetConfig(req.Config); err != nil {
 		return nil, fmt.Errorf("setting sandbox config: %w", err)
@@ -60,13 +70,18 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	// we need to fill in the container name, as it is not present in the request. Luckily, it is a constant.
 	log.Infof(ctx, "Running pod sandbox: %s%s", oci.LabelsToDescription(sbox.Config().Labels), oci.InfraContainerName)
 
-	if err := sbox.GenerateNameAndID(); err != nil {
+	namingStrategy, err := s.config.Naming()
+	if err != nil {
+		return nil, err
+	}
+	if err := sbox.GenerateNameAndID(namingStrategy); err != nil {
 		return nil, fmt.Errorf("setting pod sandbox name and id: %w", err)
 	}
 	sboxID := sbox.ID()
 	sboxName := sbox.Name()
 
 	sbox.SetName(sboxName)
+
 	resourceCleaner := resourcestore.NewResourceCleaner()
 	// in some cases, it is still necessary to reserve container resources when an error occurs (such as just a request context timeout error)
 	storeResource := false
@@ -76,7 +91,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 			return
 		}
 		if err := resourceCleaner.Cleanup(); err != nil {
-			log.Errorf(ctx, "Unable to cleanup: %v", err)
+			logCleanupError(ctx, "Unable to cleanup", err)
 		}
 	}()
 
@@ -96,13 +111,14 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		}
 		return nil, fmt.Errorf("%w: %w", resourceErr, err)
 	}
-	resourceCleaner.Add(ctx, "runSandbox: releasing pod sandbox name: "+sboxName, func() error {
+	resourceCleaner.AddResource(ctx, "pod sandbox name", sboxName, "runSandbox: releasing pod sandbox name: "+sboxName, func() error {
 		s.ReleasePodName(sboxName)
+		metrics.Instance().MetricResourceStageDurationDelete(sboxName)
 		return nil
 	})
 
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox creating")
+	s.setResourceStage(ctx, sboxName, "sandbox creating")
 
 	securityContext := sbox.Config().Linux.SecurityContext
 
@@ -112,14 +128,16 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	hostNetwork := securityContext.NamespaceOptions.Network == types.NamespaceMode_NODE
 	sbox.SetHostNetwork(hostNetwork)
 
+	// The wait is bounded by the request, so that a plugin_wait_timeout of
+	// zero waits as long as the request and no longer.
 	if !hostNetwork {
-		if err := s.waitForCNIPlugin(ctx, sboxName); err != nil {
+		if err := s.waitForCNIPlugin(ctx, sboxName, s.config.NetworkConfig.PluginWaitTimeout); err != nil {
 			return nil, err
 		}
 	}
 
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox network ready")
+	s.setResourceStage(ctx, sboxName, "sandbox network ready")
 
 	// validate the runtime handler
 	runtimeHandler, err := s.runtimeHandler(req)
@@ -160,7 +178,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err != nil {
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "runSandbox: releasing container name: "+containerName, func() error {
+	resourceCleaner.AddResource(ctx, "container name", containerName, "runSandbox: releasing container name: "+containerName, func() error {
 		s.ReleaseContainerName(ctx, containerName)
 		return nil
 	})
@@ -175,11 +193,16 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	sbox.SetPrivileged(privileged)
 
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox storage creation")
+	s.setResourceStage(ctx, sboxName, "sandbox storage creation")
 	pauseImage := s.config.ParsePauseImage()
+	pauseImagePullPolicy, err := imagePullPolicy(kubeAnnotations, storage.PullIfNotPresent)
+	if err != nil {
+		return nil, fmt.Errorf("pause image pull policy: %w", err)
+	}
 	podContainer, err := s.StorageService().CreatePodSandbox(
 		sboxName, sboxID,
 		pauseImage,
+		pauseImagePullPolicy,
 		containerName,
 		kubeName,
 		sbox.Config().Metadata.Uid,
@@ -194,7 +217,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err != nil {
 		return nil, fmt.Errorf("creating pod sandbox with name %q: %w", sboxName, err)
 	}
-	resourceCleaner.Add(ctx, "runSandbox: removing pod sandbox from storage: "+sboxID, func() error {
+	resourceCleaner.AddResource(ctx, "sandbox storage", sboxID, "runSandbox: removing pod sandbox from storage: "+sboxID, func() error {
 		return s.StorageService().DeleteContainer(ctx, sboxID)
 	})
 
@@ -209,11 +232,8 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		logDir = filepath.Join(s.config.LogDir, sboxID)
 	}
 	// This should always be absolute from k8s.
-	if !filepath.IsAbs(logDir) {
-		return nil, fmt.Errorf("requested logDir for sbuilder ID %s is a relative path: %s", sboxID, logDir)
-	}
-	if err := os.MkdirAll(logDir, 0o700); err != nil {
-		return nil, err
+	if err := libconfig.ValidateLogDir(logDir); err != nil {
+		return nil, fmt.Errorf("invalid log directory for pod sandbox %s: %w", sboxID, err)
 	}
 	sbox.SetLogDir(logDir)
 
@@ -258,11 +278,15 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	hostIPC := securityContext.NamespaceOptions.Ipc == types.NamespaceMode_NODE
 	hostPID := securityContext.NamespaceOptions.Pid == types.NamespaceMode_NODE
-
-	// Don't use SELinux separation with Host Pid or IPC Namespace or privileged.
-	if hostPID || hostIPC {
-		processLabel, mountLabel = "", ""
+	// There is no CRI option for the UTS namespace, pods in the host network
+	// namespace can opt in to the host UTS namespace with an annotation.
+	hostUTS, err := sandboxHostUTS(kubeAnnotations, hostNetwork)
+	if err != nil {
+		return nil, err
 	}
+	sbox.SetHostUTS(hostUTS)
+
+	processLabel, mountLabel = infraEffectiveLabels(processLabel, mountLabel, hostPID, hostIPC)
 	g := sbox.Spec()
 	g.SetProcessSelinuxLabel(processLabel)
 	g.SetLinuxMountLabel(mountLabel)
@@ -272,24 +296,20 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	// create shm mount for the pod containers.
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox shm creation")
+	s.setResourceStage(ctx, sboxName, "sandbox shm creation")
 	var shmPath string
 	if hostIPC {
 		shmPath = libsandbox.DevShmPath
 	} else {
-		shmSize := int64(libsandbox.DefaultShmSize)
-		if shmSizeStr, ok := kubeAnnotations[annotations.ShmSizeAnnotation]; ok {
-			quantity, err := resource.ParseQuantity(shmSizeStr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse shm size '%s': %w", shmSizeStr, err)
-			}
-			shmSize = quantity.Value()
+		shmSize, err := s.sandboxShmSize(kubeAnnotations)
+		if err != nil {
+			return nil, err
 		}
 		shmPath, err = libsandbox.SetupShm(podContainer.RunDir, mountLabel, shmSize)
 		if err != nil {
 			return nil, err
 		}
-		resourceCleaner.Add(ctx, "runSandbox: unmounting shmPath for sandbox "+sboxID, func() error {
+		resourceCleaner.AddResource(ctx, "sandbox shm mount", sboxID, "runSandbox: unmounting shmPath for sandbox "+sboxID, func() error {
 			if err := unix.Unmount(shmPath, unix.MNT_DETACH); err != nil {
 				return fmt.Errorf("failed to unmount shm for sandbox: %w", err)
 			}
@@ -306,7 +326,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox spec configuration")
+	s.setResourceStage(ctx, sboxName, "sandbox spec configuration")
 
 	mnt := spec.Mount{
 		Type:        "bind",
@@ -325,7 +345,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err := s.CtrIDIndex().Add(sboxID); err != nil {
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "runSandbox: deleting container ID from idIndex for sandbox "+sboxID, func() error {
+	resourceCleaner.AddResource(ctx, "container ID index entry", sboxID, "runSandbox: deleting container ID from idIndex for sandbox "+sboxID, func() error {
 		if err := s.CtrIDIndex().Delete(sboxID); err != nil && !strings.Contains(err.Error(), noSuchID) {
 			return fmt.Errorf("could not delete ctr id %s from idIndex: %w", sboxID, err)
 		}
@@ -345,7 +365,9 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 	sbox.SetHostname(hostname)
-	g.SetHostname(hostname)
+	if !hostUTS {
+		g.SetHostname(hostname)
+	}
 
 	g.AddAnnotation(annotations.Metadata, string(metadataJSON))
 	g.AddAnnotation(annotations.Labels, string(labelsJSON))
@@ -453,7 +475,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err := s.addSandbox(ctx, sb); err != nil {
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "runSandbox: removing pod sandbox "+sboxID, func() error {
+	resourceCleaner.AddResource(ctx, "sandbox", sboxID, "runSandbox: removing pod sandbox "+sboxID, func() error {
 		if err := s.removeSandbox(ctx, sboxID); err != nil {
 			return fmt.Errorf("could not remove pod sandbox: %w", err)
 		}
@@ -463,7 +485,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err := s.PodIDIndex().Add(sboxID); err != nil {
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "runSandbox: deleting pod ID "+sboxID+" from idIndex", func() error {
+	resourceCleaner.AddResource(ctx, "pod ID index entry", sboxID, "runSandbox: deleting pod ID "+sboxID+" from idIndex", func() error {
 		if err := s.PodIDIndex().Delete(sboxID); err != nil && !strings.Contains(err.Error(), noSuchID) {
 			return fmt.Errorf("could not delete pod id %s from idIndex: %w", sboxID, err)
 		}
@@ -478,12 +500,19 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	// Add default sysctls given in crio.conf
-	sysctls := s.configureGeneratorForSysctls(ctx, g, hostNetwork, hostIPC, req.Config.Linux.Sysctls)
+	allowedUnsafeSysctls, err := s.Runtime().RuntimeAllowedUnsafeSysctls(runtimeHandler)
+	if err != nil {
+		return nil, err
+	}
+	sysctls, err := s.configureGeneratorForSysctls(ctx, g, hostNetwork, hostIPC, hostUTS, allowedUnsafeSysctls, req.Config.Linux.Sysctls)
+	if err != nil {
+		return nil, err
+	}
 
 	// set up namespaces
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox namespace creation")
-	nsCleanupFuncs, err := s.configureGeneratorForSandboxNamespaces(ctx, hostNetwork, hostIPC, hostPID, sysctls, sb, g)
+	s.setResourceStage(ctx, sboxName, "sandbox namespace creation")
+	nsCleanupFuncs, err := s.configureGeneratorForSandboxNamespaces(ctx, hostNetwork, hostIPC, hostPID, hostUTS, sysctls, sb, g)
 	// We want to cleanup after ourselves if we are managing any namespaces and fail in this function.
 	// However, we don't immediately register this func with resourceCleaner because we need to pair the
 	// ns cleanup with networkStop. Otherwise, we could try to cleanup the namespace before the network stop runs,
@@ -498,7 +527,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil
 	}
 	if err != nil {
-		resourceCleaner.Add(ctx, nsCleanupDescription, nsCleanupFunc)
+		resourceCleaner.AddResource(ctx, "sandbox namespaces", sboxID, nsCleanupDescription, nsCleanupFunc)
 		return nil, err
 	}
 
@@ -507,13 +536,13 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	var result cnitypes.Result
 
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox network creation")
+	s.setResourceStage(ctx, sboxName, "sandbox network creation")
 	ips, result, err = s.networkStart(ctx, sb)
 	if err != nil {
-		resourceCleaner.Add(ctx, nsCleanupDescription, nsCleanupFunc)
+		resourceCleaner.AddResource(ctx, "sandbox namespaces", sboxID, nsCleanupDescription, nsCleanupFunc)
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "runSandbox: stopping network for sandbox"+sb.ID(), func() error {
+	resourceCleaner.AddResource(ctx, "sandbox network", sb.ID(), "runSandbox: stopping network for sandbox"+sb.ID(), func() error {
 		// use a new context to prevent an expired context from preventing a stop
 		if err := s.networkStop(context.Background(), sb); err != nil {
 			return fmt.Errorf("error stopping network on cleanup: %w", err)
@@ -535,19 +564,9 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		g.AddAnnotation(annotations.CNIResult, string(cniResultJSON))
 	}
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox storage start")
-
-	// Set OOM score adjust of the infra container to be very low
-	// so it doesn't get killed.
-	g.SetProcessOOMScoreAdj(PodInfraOOMAdj)
-
-	g.SetLinuxResourcesCPUShares(PodInfraCPUshares)
+	s.setResourceStage(ctx, sboxName, "sandbox storage start")
 
-	// When infra-ctr-cpuset specified, set the infra container CPU set
-	if s.config.InfraCtrCPUSet != "" {
-		log.Debugf(ctx, "Set the infra container cpuset to %q", s.config.InfraCtrCPUSet)
-		g.SetLinuxResourcesCPUCpus(s.config.InfraCtrCPUSet)
-	}
+	s.setInfraCtrResources(ctx, g)
 
 	saveOptions := generate.ExportOptions{}
 	g.AddAnnotation(annotations.MountPoint, podContainer.RootFs)
@@ -635,12 +654,12 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	s.addInfraContainer(ctx, container)
-	resourceCleaner.Add(ctx, "runSandbox: removing infra container "+container.ID(), func() error {
+	resourceCleaner.AddResource(ctx, "infra container", container.ID(), "runSandbox: removing infra container "+container.ID(), func() error {
 		s.removeInfraContainer(ctx, container)
 		return nil
 	})
 	// TODO: Pass interface instead of individual field.
-	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox container runtime creation")
+	s.setResourceStage(ctx, sboxName, "sandbox container runtime creation")
 	if err := s.createContainerPlatform(ctx, container, sb.CgroupParent()); err != nil {
 		return nil, err
 	}
@@ -658,7 +677,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if err := s.Runtime().StartContainer(ctx, container); err != nil {
 		return nil, err
 	}
-	resourceCleaner.Add(ctx, "runSandbox: stopping container "+container.ID(), func() error {
+	resourceCleaner.AddResource(ctx, "infra container process", container.ID(), "runSandbox: stopping container "+container.ID(), func() error {
 		// Clean-up steps from RemovePodSandbox
 		if err := s.stopContainer(ctx, container, stopTimeoutFromContext(ctx)); err != nil {
 			return errors.New("failed to stop container for removal")
@@ -684,12 +703,19 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 	sb.AddIPs(ips)
 
-	if err := s.nri.runPodSandbox(ctx, sb); err != nil {
+	if err := s.runNRIHook(ctx, "RunPodSandbox", s.config.NRI.RunPodSandboxFailurePolicy, func(ctx context.Context) error {
+		return s.nri.runPodSandbox(ctx, sb)
+	}); err != nil {
 		return nil, err
 	}
 
+	// A creation which got through all stages but outlived its request is
+	// parked with its namespaces, network and storage, so that the retried
+	// request is handed out this sandbox instead of creating another one.
+	// Stages aborted by the request context are rolled back entirely, which
+	// leaves nothing behind for a retry to trip over.
 	if isContextError(ctx.Err()) {
-		if err := s.resourceStore.Put(sboxName, sb, resourceCleaner); err != nil {
+		if err := s.parkResource(ctx, "sandbox", sboxName, sb, resourceCleaner); err != nil {
 			log.Errorf(ctx, "RunSandbox: failed to save progress of sandbox %s: %v", sboxID, err)
 		}
 		log.Infof(ctx, "RunSandbox: context was either canceled or the deadline was exceeded: %v", ctx.Err())
@@ -699,7 +725,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	// Since it's not a context error, we can delete the resource from the store, it will be tracked in the server from now on.
-	s.resourceStore.Delete(sboxName)
+	s.finishResourceCreation(ctx, sboxName)
 
 	sb.SetCreated()
 	s.generateCRIEvent(ctx, sb.InfraContainer(), types.ContainerEventType_CONTAINER_STARTED_EVENT)
@@ -728,7 +754,12 @@ func populateSandboxLabels(labels map[string]string, kubeName, kubePodUID, names
 	return labels
 }
 
-func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.Generator, hostNetwork, hostIPC bool, sysctls map[string]string) map[string]string {
+// configureGeneratorForSysctls adds the default sysctls and the sysctls of
+// the pod to the generator and returns them. Sysctls which are not known to be
+// namespaced are only valid if they match allowedUnsafeSysctls, sysctls known
+// not to be namespaced never are. Invalid sysctls are skipped, unless
+// strict_sysctls is set, then an error listing all of them is returned.
+func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.Generator, hostNetwork, hostIPC, hostUTS bool, allowedUnsafeSysctls []string, sysctls map[string]string) (map[string]string, error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	sysctlsToReturn := make(map[string]string)
@@ -737,8 +768,21 @@ func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.G
 		log.Warnf(ctx, "Sysctls invalid: %v", err)
 	}
 
+	validate := func(sysctl *libconfig.Sysctl) error {
+		err := sysctl.Validate(hostNetwork, hostIPC)
+		if errors.Is(err, libconfig.ErrSysctlNotWhitelisted) {
+			return sysctl.ValidateUnsafe(allowedUnsafeSysctls, hostUTS)
+		}
+		return err
+	}
+
+	var errs []error
 	for _, sysctl := range defaultSysctls {
-		if err := sysctl.Validate(hostNetwork, hostIPC); err != nil {
+		if err := validate(&sysctl); err != nil {
+			if s.config.StrictSysctls {
+				errs = append(errs, fmt.Errorf("sysctl %s specified by config: %w", sysctl.Key(), err))
+				continue
+			}
 			log.Warnf(ctx, "Skipping invalid sysctl specified by config %s: %v", sysctl, err)
 			continue
 		}
@@ -748,23 +792,32 @@ func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.G
 
 	// extract linux sysctls from annotations and pass down to oci runtime
 	// Will override any duplicate default systcl from crio.conf
-	for key, value := range sysctls {
+	for _, key := range slices.Sorted(maps.Keys(sysctls)) {
+		value := sysctls[key]
 		sysctl := libconfig.NewSysctl(key, value)
-		if err := sysctl.Validate(hostNetwork, hostIPC); err != nil {
+		if err := validate(sysctl); err != nil {
+			if s.config.StrictSysctls {
+				errs = append(errs, fmt.Errorf("sysctl %s specified over CRI: %w", key, err))
+				continue
+			}
 			log.Warnf(ctx, "Skipping invalid sysctl specified over CRI %s: %v", sysctl, err)
 			continue
 		}
 		g.AddLinuxSysctl(key, value)
 		sysctlsToReturn[key] = value
 	}
-	return sysctlsToReturn
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid sysctls: %w", utilerrors.NewAggregate(errs))
+	}
+	return sysctlsToReturn, nil
 }
 
 // configureGeneratorForSandboxNamespaces set the linux namespaces for the generator, based on whether the pod is sharing namespaces with the host,
 // as well as whether CRI-O should be managing the namespace lifecycle.
 // it returns a slice of cleanup funcs, all of which are the respective NamespaceRemove() for the sandbox.
 // The caller should defer the cleanup funcs if there is an error, to make sure each namespace we are managing is properly cleaned up.
-func (s *Server) configureGeneratorForSandboxNamespaces(ctx context.Context, hostNetwork, hostIPC, hostPID bool, sysctls map[string]string, sb *libsandbox.Sandbox, g *generate.Generator) (cleanupFuncs []func() error, retErr error) {
+func (s *Server) configureGeneratorForSandboxNamespaces(ctx context.Context, hostNetwork, hostIPC, hostPID, hostUTS bool, sysctls map[string]string, sb *libsandbox.Sandbox, g *generate.Generator) (cleanupFuncs []func() error, retErr error) {
 	_, span := log.StartSpan(ctx)
 	defer span.End()
 	// Since we need a process to hold open the PID namespace, CRI-O can't manage the NS lifecycle
@@ -784,11 +837,19 @@ func (s *Server) configureGeneratorForSandboxNamespaces(ctx context.Context, hos
 				Type: nsmgr.NETNS,
 				Host: hostNetwork,
 			},
-			{
-				Type: nsmgr.UTSNS, // there is no option for host UTSNS
-			},
 		},
 	}
+	// Like the PID namespace, the host UTS namespace is used by not creating one.
+	// Pinning it would let the runtime change the hostname of the host.
+	if hostUTS {
+		if err := g.RemoveLinuxNamespace(string(spec.UTSNamespace)); err != nil {
+			return nil, err
+		}
+	} else {
+		namespaceConfig.Namespaces = append(namespaceConfig.Namespaces, &nsmgr.PodNamespaceConfig{
+			Type: nsmgr.UTSNS,
+		})
+	}
 
 	// now that we've configured the namespaces we're sharing, create them
 	namespaces, err := s.config.NamespaceManager().NewPodNamespaces(namespaceConfig)
@@ -800,9 +861,69 @@ func (s *Server) configureGeneratorForSandboxNamespaces(ctx context.Context, hos
 
 	cleanupFuncs = append(cleanupFuncs, sb.RemoveManagedNamespaces)
 
-	if err := ctrfactory.ConfigureGeneratorGivenNamespacePaths(sb.NamespacePaths(), g); err != nil {
+	nsPaths := sb.NamespacePaths()
+	// Catch stale or unmounted namespaces here, rather than with a late error of the runtime.
+	for _, ns := range nsPaths {
+		if ns.Path() == "" {
+			continue
+		}
+		if err := s.config.NamespaceManager().ValidateNamespacePath(ns.Path(), ns.Type()); err != nil {
+			return cleanupFuncs, fmt.Errorf("invalid sandbox namespace: %w", err)
+		}
+	}
+
+	if err := ctrfactory.ConfigureGeneratorGivenNamespacePaths(nsPaths, g); err != nil {
 		return cleanupFuncs, err
 	}
 
 	return cleanupFuncs, nil
 }
+
+// sandboxHostUTS returns whether a sandbox uses the host UTS namespace, which
+// pods in the host network namespace can request with the HostUTS annotation.
+func sandboxHostUTS(kubeAnnotations map[string]string, hostNetwork bool) (bool, error) {
+	if !libsandbox.HostUTSRequested(kubeAnnotations) {
+		return false, nil
+	}
+	if !hostNetwork {
+		return false, fmt.Errorf("annotation %s requires the host network namespace", annotations.HostUTSAnnotation)
+	}
+	return true, nil
+}
+
+// sandboxShmSize returns the size of the shm shared by the containers of a
+// pod. The ShmSize annotation of the pod takes precedence over the configured
+// default size.
+func (s *Server) sandboxShmSize(kubeAnnotations map[string]string) (int64, error) {
+	if shmSizeStr, ok := kubeAnnotations[annotations.ShmSizeAnnotation]; ok {
+		quantity, err := resource.ParseQuantity(shmSizeStr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse shm size '%s': %w", shmSizeStr, err)
+		}
+		return quantity.Value(), nil
+	}
+	defaultShmSize, err := s.config.DefaultShmSizeBytes()
+	if err != nil {
+		return 0, err
+	}
+	if defaultShmSize > 0 {
+		return defaultShmSize, nil
+	}
+	return libsandbox.DefaultShmSize, nil
+}
+
+// setInfraCtrResources sets the OOM score adjustment and the CPU resources of
+// an infra container.
+func (s *Server) setInfraCtrResources(ctx context.Context, g *generate.Generator) {
+	// The OOM score adjust of the infra container defaults to be very low
+	// so it doesn't get killed.
+	g.SetProcessOOMScoreAdj(s.config.InfraCtrOOMScoreAdj)
+
+	g.SetLinuxResourcesCPUShares(PodInfraCPUshares)
+
+	// When infra-ctr-cpuset specified, set the infra container CPU set
+	if s.config.InfraCtrCPUSet != "" {
+		log.Debugf(ctx, "Set the infra container cpuset to %q", s.config.InfraCtrCPUSet)
+		g.SetLinuxResourcesCPUCpus(s.config.InfraCtrCPUSet)
+	}
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/runtime-tools/generate"
+
+	"github.com/L-F-Z/cri-t/pkg/annotations"
+	libconfig "github.com/L-F-Z/cri-t/pkg/config"
+)
+
+func TestSetInfraCtrResources(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		oomScoreAdj int
+	}{
+		{"default", libconfig.DefaultInfraCtrOOMScoreAdj},
+		{"configured", -500},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := &Server{}
+			sut.config.InfraCtrOOMScoreAdj = tc.oomScoreAdj
+
+			g, err := generate.New("linux")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sut.setInfraCtrResources(context.Background(), &g)
+
+			if adj := g.Config.Process.OOMScoreAdj; adj == nil || *adj != tc.oomScoreAdj {
+				t.Errorf("expected OOM score adjustment %d, got %v", tc.oomScoreAdj, adj)
+			}
+			if shares := g.Config.Linux.Resources.CPU.Shares; shares == nil || *shares != PodInfraCPUshares {
+				t.Errorf("expected CPU shares %d, got %v", PodInfraCPUshares, shares)
+			}
+		})
+	}
+}
+
+func TestConfigureGeneratorForSysctls(t *testing.T) {
+	podSysctls := map[string]string{
+		"net.ipv4.ip_forward": "1",
+		"kernel.shmmax":       "4096",
+		"vm.swappiness":       "10",
+	}
+
+	for _, tc := range []struct {
+		name    string
+		strict  bool
+		hostUTS bool
+		allowed []string
+		want    map[string]string
+		wantErr []string
+	}{
+		{
+			name: "lenient",
+			want: map[string]string{"kernel.shmmax": "4096", "kernel.sem": "250"},
+		},
+		{
+			name:    "strict",
+			strict:  true,
+			wantErr: []string{"net.ipv4.ip_forward", "vm.swappiness", "kernel.domainname", "net.ipv4.ping_group_range"},
+		},
+		{
+			name:    "allowed unsafe",
+			allowed: []string{"kernel.domainname", "net.*"},
+			want:    map[string]string{"kernel.shmmax": "4096", "kernel.sem": "250", "kernel.domainname": "example"},
+		},
+		{
+			name:    "allowed unsafe not namespaced",
+			strict:  true,
+			allowed: []string{"vm.*", "kernel.domainname", "net.*"},
+			wantErr: []string{"vm.swappiness not namespaced"},
+		},
+		{
+			name:    "allowed unsafe with host uts",
+			hostUTS: true,
+			allowed: []string{"kernel.domainname"},
+			want:    map[string]string{"kernel.shmmax": "4096", "kernel.sem": "250"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := &Server{}
+			sut.config.DefaultSysctls = []string{"kernel.sem=250", "net.ipv4.ping_group_range=0 1", "kernel.domainname=example"}
+			sut.config.StrictSysctls = tc.strict
+
+			g, err := generate.New("linux")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := sut.configureGeneratorForSysctls(context.Background(), &g, true, false, tc.hostUTS, tc.allowed, podSysctls)
+			if len(tc.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				for _, key := range tc.wantErr {
+					if !strings.Contains(err.Error(), key) {
+						t.Errorf("expected error %q to contain %q", err, key)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(got, tc.want) {
+				t.Errorf("expected sysctls %v, got %v", tc.want, got)
+			}
+			if !maps.Equal(g.Config.Linux.Sysctl, tc.want) {
+				t.Errorf("expected generator sysctls %v, got %v", tc.want, g.Config.Linux.Sysctl)
+			}
+		})
+	}
+}
+
+func TestRunPodSandboxInfraCtrOOMScoreAdj(t *testing.T) {
+	sut := newTestServer(t)
+	sut.config.InfraCtrOOMScoreAdj = -500
+
+	resp, err := sut.runPodSandbox(context.Background(), newTestRunPodSandboxRequest("pod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sb := sut.GetSandbox(resp.PodSandboxId)
+	if sb == nil {
+		t.Fatalf("sandbox %s not found", resp.PodSandboxId)
+	}
+	if adj := sb.InfraContainer().Spec().Process.OOMScoreAdj; adj == nil || *adj != -500 {
+		t.Errorf("expected the infra container OOM score adjustment -500, got %v", adj)
+	}
+}
+
+func TestSandboxHostUTS(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotation  string
+		hostNetwork bool
+		want        bool
+		wantErr     bool
+	}{
+		{name: "pod network"},
+		{name: "host network", hostNetwork: true},
+		{name: "host network with annotation", annotation: "true", hostNetwork: true, want: true},
+		{name: "host network with false annotation", annotation: "false", hostNetwork: true},
+		{name: "pod network with annotation", annotation: "true", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeAnnotations := map[string]string{}
+			if tc.annotation != "" {
+				kubeAnnotations[annotations.HostUTSAnnotation] = tc.annotation
+			}
+
+			hostUTS, err := sandboxHostUTS(kubeAnnotations, tc.hostNetwork)
+
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if hostUTS != tc.want {
+				t.Errorf("expected host UTS %v, got %v", tc.want, hostUTS)
+			}
+		})
+	}
+}
+
+func TestRunPodSandboxCanceledAtStage(t *testing.T) {
+	for _, stage := range []string{
+		"sandbox storage creation",
+		"sandbox network creation",
+		"sandbox container runtime creation",
+	} {
+		t.Run(stage, func(t *testing.T) {
+			sut := newTestServer(t)
+			req := newTestRunPodSandboxRequest("pod")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sut.resourceStageHook = func(_, current string) {
+				if current == stage {
+					cancel()
+				}
+			}
+			if _, err := sut.runPodSandbox(ctx, req); !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected the canceled request to fail, got %v", err)
+			}
+			sut.resourceStageHook = nil
+			leftover := testStorageContainers(t, sut)
+
+			resp, err := sut.runPodSandbox(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// None of the stages is aborted by the canceled request here, so
+			// the creation is parked and the retry resumes it.
+			if len(leftover) != 1 || leftover[0] != resp.PodSandboxId {
+				t.Errorf("expected the retry to resume the parked sandbox %v, got %s", leftover, resp.PodSandboxId)
+			}
+			if ids := testStorageContainers(t, sut); len(ids) != 1 || ids[0] != resp.PodSandboxId {
+				t.Errorf("expected only the storage of sandbox %s, got %v", resp.PodSandboxId, ids)
+			}
+			if sandboxes := sut.ListSandboxes(); len(sandboxes) != 1 || !sandboxes[0].Created() {
+				t.Errorf("expected one created sandbox, got %d", len(sandboxes))
+			}
+		})
+	}
+}
+
+func TestRunPodSandboxFailureRollsBack(t *testing.T) {
+	sut := newTestServer(t)
+	req := newTestRunPodSandboxRequest("pod")
+	// The labels are validated after the storage has been created.
+	req.Config.Labels = map[string]string{"in valid": "label"}
+
+	if _, err := sut.runPodSandbox(context.Background(), req); err == nil {
+		t.Fatal("expected the creation to fail")
+	}
+	if ids := testStorageContainers(t, sut); len(ids) != 0 {
+		t.Errorf("expected the storage to be removed, got %v", ids)
+	}
+	if sandboxes := sut.ListSandboxes(); len(sandboxes) != 0 {
+		t.Errorf("expected no sandbox, got %d", len(sandboxes))
+	}
+
+	req.Config.Labels = nil
+	if _, err := sut.runPodSandbox(context.Background(), req); err != nil {
+		t.Fatalf("expected the name to be released, got %v", err)
+	}
+}
+
+func testStorageContainers(t *testing.T, sut *Server) []string {
+	t.Helper()
+	containers, err := sut.StorageService().Containers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for i := range containers {
+		ids = append(ids, containers[i].ID)
+	}
+	return ids
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+
+	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+// newSecurityPosture derives the security posture of a container from its
+// spec. Capabilities are reported as added or dropped relative to the
+// default capabilities.
+func newSecurityPosture(spec *rspec.Spec, privileged bool, seccompProfile string, defaultCapabilities []string) *oci.SecurityPosture {
+	posture := &oci.SecurityPosture{
+		Privileged:          privileged,
+		CapabilitiesAdded:   []string{},
+		CapabilitiesDropped: []string{},
+		SeccompProfile:      seccompProfile,
+	}
+	if spec.Root != nil {
+		posture.ReadOnlyRootfs = spec.Root.Readonly
+	}
+
+	var bounding []string
+	if spec.Process != nil {
+		posture.UID = spec.Process.User.UID
+		posture.GID = spec.Process.User.GID
+		posture.AppArmorProfile = spec.Process.ApparmorProfile
+		posture.SELinuxLabel = spec.Process.SelinuxLabel
+		if spec.Process.Capabilities != nil {
+			bounding = spec.Process.Capabilities.Bounding
+		}
+	}
+	defaults := make([]string, 0, len(defaultCapabilities))
+	for _, c := range defaultCapabilities {
+		defaults = append(defaults, "CAP_"+strings.ToUpper(c))
+	}
+	for _, c := range bounding {
+		if !slices.Contains(defaults, c) {
+			posture.CapabilitiesAdded = append(posture.CapabilitiesAdded, c)
+		}
+	}
+	for _, c := range defaults {
+		if !slices.Contains(bounding, c) {
+			posture.CapabilitiesDropped = append(posture.CapabilitiesDropped, c)
+		}
+	}
+	slices.Sort(posture.CapabilitiesAdded)
+	slices.Sort(posture.CapabilitiesDropped)
+
+	// A container without its own namespace of a type shares the one of
+	// the host.
+	posture.HostNetwork = !hasNamespace(spec, rspec.NetworkNamespace)
+	posture.HostPID = !hasNamespace(spec, rspec.PIDNamespace)
+	posture.HostIPC = !hasNamespace(spec, rspec.IPCNamespace)
+
+	return posture
+}
+
+// restoreSecurityPosture derives the security posture of a container restored
+// from disk. It runs before the server serves requests, so nothing reads the
+// posture concurrently.
+func (s *Server) restoreSecurityPosture(ctx context.Context, id string, privileged bool) {
+	ctr := s.GetContainer(ctx, id)
+	if ctr == nil {
+		log.Warnf(ctx, "Unable to find restored container %s to derive its security posture", id)
+		return
+	}
+	spec := ctr.Spec()
+	ctr.SetSecurityPosture(newSecurityPosture(&spec, privileged, ctr.SeccompProfilePath(), s.config.DefaultCapabilities))
+}
+
+func hasNamespace(spec *rspec.Spec, nsType rspec.LinuxNamespaceType) bool {
+	if spec.Linux == nil {
+		return false
+	}
+	return slices.ContainsFunc(spec.Linux.Namespaces, func(ns rspec.LinuxNamespace) bool {
+		return ns.Type == nsType
+	})
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/config/capabilities"
+	"github.com/L-F-Z/cri-t/internal/oci"
+)
+
+func TestSecurityPosturePrivilegedHostNetwork(t *testing.T) {
+	spec := &rspec.Spec{
+		Root: &rspec.Root{},
+		Process: &rspec.Process{
+			Capabilities: &rspec.LinuxCapabilities{
+				Bounding: []string{"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FSETID", "CAP_FOWNER", "CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP", "CAP_NET_BIND_SERVICE", "CAP_KILL", "CAP_SYS_ADMIN", "CAP_NET_ADMIN"},
+			},
+		},
+		Linux: &rspec.Linux{
+			Namespaces: []rspec.LinuxNamespace{
+				{Type: rspec.PIDNamespace},
+				{Type: rspec.IPCNamespace},
+				{Type: rspec.MountNamespace},
+			},
+		},
+	}
+
+	posture := newSecurityPosture(spec, true, "Unconfined", capabilities.Default())
+
+	if !posture.Privileged || !posture.HostNetwork {
+		t.Errorf("expected a privileged host network container, got %+v", posture)
+	}
+	if posture.HostPID || posture.HostIPC {
+		t.Errorf("expected own PID and IPC namespaces, got %+v", posture)
+	}
+	if want := []string{"CAP_NET_ADMIN", "CAP_SYS_ADMIN"}; !slices.Equal(posture.CapabilitiesAdded, want) {
+		t.Errorf("expected added capabilities %v, got %v", want, posture.CapabilitiesAdded)
+	}
+	if len(posture.CapabilitiesDropped) != 0 {
+		t.Errorf("expected no dropped capabilities, got %v", posture.CapabilitiesDropped)
+	}
+	if posture.ReadOnlyRootfs {
+		t.Error("expected a writable rootfs")
+	}
+}
+
+func TestSecurityPostureLockedDown(t *testing.T) {
+	spec := &rspec.Spec{
+		Root: &rspec.Root{Readonly: true},
+		Process: &rspec.Process{
+			ApparmorProfile: "crio-default",
+			SelinuxLabel:    "system_u:system_r:container_t:s0:c1,c2",
+			Capabilities: &rspec.LinuxCapabilities{
+				Bounding: []string{"CAP_NET_BIND_SERVICE"},
+			},
+		},
+		Linux: &rspec.Linux{
+			Namespaces: []rspec.LinuxNamespace{
+				{Type: rspec.NetworkNamespace, Path: "/var/run/netns/pod"},
+				{Type: rspec.PIDNamespace},
+				{Type: rspec.IPCNamespace},
+				{Type: rspec.MountNamespace},
+			},
+		},
+	}
+
+	posture := newSecurityPosture(spec, false, "RuntimeDefault", capabilities.Default())
+
+	if posture.Privileged || posture.HostNetwork || posture.HostPID || posture.HostIPC {
+		t.Errorf("expected an unprivileged container without host namespaces, got %+v", posture)
+	}
+	if !posture.ReadOnlyRootfs {
+		t.Error("expected a read-only rootfs")
+	}
+	if len(posture.CapabilitiesAdded) != 0 {
+		t.Errorf("expected no added capabilities, got %v", posture.CapabilitiesAdded)
+	}
+	if len(posture.CapabilitiesDropped) != len(capabilities.Default())-1 {
+		t.Errorf("expected all default capabilities but NET_BIND_SERVICE to be dropped, got %v", posture.CapabilitiesDropped)
+	}
+	if posture.SeccompProfile != "RuntimeDefault" || posture.AppArmorProfile != "crio-default" || posture.SELinuxLabel == "" {
+		t.Errorf("expected the confinement profiles, got %+v", posture)
+	}
+}
+
+func TestRestoreSecurityPosture(t *testing.T) {
+	sut := newTestServer(t)
+	ctx := context.Background()
+	sbResp, err := sut.runPodSandbox(ctx, newTestRunPodSandboxRequest("pod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr, err := oci.NewContainer("ctrID", "ctr", "", "", nil, nil, nil, "", nil, nil, "", &types.ContainerMetadata{}, sbResp.PodSandboxId, false, false, false, "", t.TempDir(), time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr.SetSpec(&rspec.Spec{
+		Root:    &rspec.Root{Readonly: true},
+		Process: &rspec.Process{User: rspec.User{UID: 1000, GID: 1000}},
+	})
+	sut.AddContainer(ctx, ctr)
+	// The container never ran, so it must not reach the runtime on removal.
+	t.Cleanup(func() { sut.ContainerServer.RemoveContainer(ctx, ctr) })
+
+	sut.restoreSecurityPosture(ctx, ctr.ID(), true)
+
+	posture := ctr.SecurityPosture()
+	if posture == nil {
+		t.Fatal("expected the posture of the restored container to be set")
+	}
+	if !posture.Privileged || !posture.ReadOnlyRootfs || posture.UID != 1000 || posture.GID != 1000 {
+		t.Errorf("expected the posture to be derived from the spec, got %+v", posture)
+	}
+}
@@ -38,3 +38,28 @@ func swapSELinuxLabel(cLabel, processLabel string) (string, error) {
 	dcon["type"] = scon["type"]
 	return dcon.Get(), nil
 }
+
+// effectiveLabels returns the SELinux process and mount labels of a container
+// or infra container from the labels allocated for it. There is no SELinux
+// separation for containers sharing the host PID or IPC namespace. Privileged
+// containers, and containers in the host network namespace if
+// hostNetworkDisableSELinux is set, keep their mount label but run without a
+// process label. Infra containers use infraEffectiveLabels instead.
+func effectiveLabels(processLabel, mountLabel string, hostPID, hostIPC, hostNet, privileged, hostNetworkDisableSELinux bool) (effectiveProcessLabel, effectiveMountLabel string) {
+	if hostPID || hostIPC {
+		return "", ""
+	}
+	if privileged || (hostNet && hostNetworkDisableSELinux) {
+		return "", mountLabel
+	}
+	return processLabel, mountLabel
+}
+
+// infraEffectiveLabels returns the SELinux process and mount labels of an
+// infra container from the labels allocated for its pod. Only sharing the host
+// PID or IPC namespace drops them. The infra container keeps its process label
+// in privileged and host network pods, only the containers of the pod drop it,
+// and VM runtimes derive the KVM label from it.
+func infraEffectiveLabels(processLabel, mountLabel string, hostPID, hostIPC bool) (effectiveProcessLabel, effectiveMountLabel string) {
+	return effectiveLabels(processLabel, mountLabel, hostPID, hostIPC, false, false, false)
+}
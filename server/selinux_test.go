@@ -0,0 +1,73 @@
+package server
+
+import "testing"
+
+func TestEffectiveLabels(t *testing.T) {
+	const (
+		processLabel = "system_u:system_r:container_t:s0:c1,c2"
+		mountLabel   = "system_u:object_r:container_file_t:s0:c1,c2"
+	)
+
+	for _, tc := range []struct {
+		name                      string
+		hostPID                   bool
+		hostIPC                   bool
+		hostNet                   bool
+		privileged                bool
+		hostNetworkDisableSELinux bool
+		wantProcessLabel          string
+		wantMountLabel            string
+	}{
+		{name: "isolated", wantProcessLabel: processLabel, wantMountLabel: mountLabel},
+		{name: "host PID", hostPID: true},
+		{name: "host IPC", hostIPC: true},
+		{name: "host PID and IPC", hostPID: true, hostIPC: true},
+		{name: "privileged", privileged: true, wantMountLabel: mountLabel},
+		{name: "privileged host PID", privileged: true, hostPID: true},
+		{name: "host network", hostNet: true, wantProcessLabel: processLabel, wantMountLabel: mountLabel},
+		{name: "host network SELinux disabled", hostNet: true, hostNetworkDisableSELinux: true, wantMountLabel: mountLabel},
+		{name: "host network SELinux disabled host IPC", hostNet: true, hostNetworkDisableSELinux: true, hostIPC: true},
+		{name: "SELinux disabled for host network only", hostNetworkDisableSELinux: true, wantProcessLabel: processLabel, wantMountLabel: mountLabel},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gotProcessLabel, gotMountLabel := effectiveLabels(processLabel, mountLabel,
+				tc.hostPID, tc.hostIPC, tc.hostNet, tc.privileged, tc.hostNetworkDisableSELinux)
+			if gotProcessLabel != tc.wantProcessLabel {
+				t.Errorf("expected process label %q, got %q", tc.wantProcessLabel, gotProcessLabel)
+			}
+			if gotMountLabel != tc.wantMountLabel {
+				t.Errorf("expected mount label %q, got %q", tc.wantMountLabel, gotMountLabel)
+			}
+		})
+	}
+}
+
+func TestInfraEffectiveLabels(t *testing.T) {
+	const (
+		processLabel = "system_u:system_r:container_t:s0:c1,c2"
+		mountLabel   = "system_u:object_r:container_file_t:s0:c1,c2"
+	)
+
+	for _, tc := range []struct {
+		name             string
+		hostPID          bool
+		hostIPC          bool
+		wantProcessLabel string
+		wantMountLabel   string
+	}{
+		{name: "isolated", wantProcessLabel: processLabel, wantMountLabel: mountLabel},
+		{name: "host PID", hostPID: true},
+		{name: "host IPC", hostIPC: true},
+		{name: "host PID and IPC", hostPID: true, hostIPC: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gotProcessLabel, gotMountLabel := infraEffectiveLabels(processLabel, mountLabel, tc.hostPID, tc.hostIPC)
+			if gotProcessLabel != tc.wantProcessLabel {
+				t.Errorf("expected process label %q, got %q", tc.wantProcessLabel, gotProcessLabel)
+			}
+			if gotMountLabel != tc.wantMountLabel {
+				t.Errorf("expected mount label %q, got %q", tc.wantMountLabel, gotMountLabel)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	kubetypes "k8s.io/kubelet/pkg/types"
 
 	"github.com/L-F-Z/TaskC/pkg/bundle"
+	"github.com/L-F-Z/cri-t/internal/audit"
 	"github.com/L-F-Z/cri-t/internal/cert"
 	"github.com/L-F-Z/cri-t/internal/config/seccomp"
 	"github.com/L-F-Z/cri-t/internal/hostport"
@@ -80,6 +81,15 @@ type Server struct {
 	pullOperationsLock sync.Mutex
 
 	resourceStore *resourcestore.ResourceStore
+	// resourceStageHook is called whenever a creation enters a new stage.
+	// It is only set by tests.
+	resourceStageHook func(name, stage string)
+
+	inFlightCreates *inFlightCreates
+
+	// sandboxSlots and containerSlots enforce the max_pods and
+	// max_containers limits.
+	sandboxSlots, containerSlots creationSlots
 
 	seccompNotifierChan chan seccomp.Notification
 	seccompNotifiers    sync.Map
@@ -89,6 +99,9 @@ type Server struct {
 
 	// NRI runtime interface
 	nri *nriAPI
+
+	// auditWebhook is nil if no audit webhook is configured.
+	auditWebhook *audit.Webhook
 }
 
 // pullArguments are used to identify a pullOperation via an input image name and
@@ -97,6 +110,7 @@ type pullArguments struct {
 	image         string
 	sandboxCgroup string
 	namespace     string
+	policy        storage.PullPolicy
 }
 
 // pullOperation is used to synchronize parallel pull operations via the
@@ -226,6 +240,9 @@ func (s *Server) restore(ctx context.Context) []bundle.BundleId {
 	// release the name associated with you.
 	for containerID := range podContainers {
 		err := s.LoadContainer(ctx, containerID)
+		if err == nil {
+			s.restoreSecurityPosture(ctx, containerID, podContainers[containerID].Privileged)
+		}
 		if err == nil || errors.Is(err, lib.ErrIsNonCrioContainer) {
 			delete(containersAndTheirImages, containerID)
 			continue
@@ -257,14 +274,14 @@ func (s *Server) restore(ctx context.Context) []bundle.BundleId {
 			}
 			return err
 		}
-		wipeResourceCleaner.Add(ctx, "cleanup sandbox network", cleanupFunc)
+		wipeResourceCleaner.AddResource(ctx, "sandbox network", sb.ID(), "cleanup sandbox network", cleanupFunc)
 	}
 
 	// If any failed to be deleted, the networking plugin is likely not ready.
 	// The cleanup should be retried until it succeeds.
 	go func() {
 		if err := wipeResourceCleaner.Cleanup(); err != nil {
-			log.Errorf(ctx, "Cleanup during server startup failed: %v", err)
+			logCleanupError(ctx, "Cleanup during server startup failed", err)
 		}
 	}()
 
@@ -392,6 +409,8 @@ func New(
 		minimumMappableGID:       config.MinimumMappableGID,
 		pullOperationsInProgress: make(map[pullArguments]*pullOperation),
 		resourceStore:            resourcestore.New(),
+		inFlightCreates:          newInFlightCreates(),
+		auditWebhook:             audit.New(config.AuditWebhookURL, config.AuditWebhookTimeout, config.AuditWebhookFailOpen),
 	}
 	if s.config.EnablePodEvents {
 		// creating a container events channel only if the evented pleg is enabled
@@ -431,7 +450,7 @@ func New(
 	if config.StreamIdleTimeout != "" {
 		idleTimeout, err := time.ParseDuration(config.StreamIdleTimeout)
 		if err != nil {
-			return nil, errors.New("unable to parse timeout as duration")
+			return nil, fmt.Errorf("unable to parse stream idle timeout as duration: %w", err)
 		}
 
 		streamServerConfig.StreamIdleTimeout = idleTimeout
@@ -441,7 +460,7 @@ func New(
 	s.stream.streamServerCloseCh = make(chan struct{})
 	if config.StreamEnableTLS {
 		log.Debugf(ctx, "TLS enabled for streaming server")
-		certConf, err := cert.NewCertConfig(ctx, s.stream.streamServerCloseCh, config.StreamTLSCert, config.StreamTLSKey, config.StreamTLSCA)
+		certConf, err := cert.NewCertConfig(ctx, s.stream.streamServerCloseCh, config.StreamTLSCert, config.StreamTLSKey, config.StreamTLSCA, config.StreamRequireClientCert)
 		if err != nil {
 			return nil, err
 		}
@@ -527,6 +546,8 @@ func (s *Server) startReloadWatcher(ctx context.Context) {
 				log.Errorf(ctx, "Unable to reload configuration: %v", err)
 				continue
 			}
+			// The runtime handlers may have changed their platform paths.
+			s.Runtime().ClearPlatformRuntimePathCache()
 			// ImageServer compiles the list with regex for both
 			// pinned and sandbox/pause images, we need to update them
 			s.StorageService().UpdatePinnedImagesList(append(s.config.PinnedImages, s.config.PauseImage))
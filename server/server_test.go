@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	json "github.com/json-iterator/go"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/L-F-Z/cri-t/internal/config/nsmgr"
+	"github.com/L-F-Z/cri-t/internal/lib"
+	"github.com/L-F-Z/cri-t/internal/resourcestore"
+	libconfig "github.com/L-F-Z/cri-t/pkg/config"
+)
+
+const testPauseImage = "pause 3.10"
+
+// testPinns pins the namespaces requested by the namespace manager by linking
+// them to the ones of the process using them, instead of unsharing new ones.
+const testPinns = `#!/bin/sh
+while [ $# -gt 0 ]; do
+	case "$1" in
+	-d) dir="$2"; shift ;;
+	-f) file="$2"; shift ;;
+	--ipc*) types="$types ipc" ;;
+	--net*) types="$types net" ;;
+	--uts*) types="$types uts" ;;
+	--user*) types="$types user" ;;
+	esac
+	shift
+done
+for t in $types; do
+	mkdir -p "$dir/${t}ns"
+	ln -s "/proc/self/ns/$t" "$dir/${t}ns/$file"
+done
+`
+
+// newTestServer returns a server which is able to run pod sandboxes without
+// an infra container, backed by storage in a temporary directory holding an
+// empty pause image. It requires root to mount the sandbox root file system.
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("running pod sandboxes requires root")
+	}
+	dir := t.TempDir()
+
+	cfg, err := libconfig.DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Root = filepath.Join(dir, "root")
+	cfg.RunRoot = filepath.Join(dir, "run")
+	cfg.LogDir = filepath.Join(dir, "log")
+	cfg.NamespacesDir = filepath.Join(dir, "ns")
+	cfg.ContainerExitsDir = filepath.Join(dir, "exits")
+	cfg.DropInfraCtr = true
+	cfg.PauseImage = testPauseImage
+	cfg.HooksDir = nil
+
+	pinns := filepath.Join(dir, "pinns")
+	if err := os.WriteFile(pinns, []byte(testPinns), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetNamespaceManager(nsmgr.New(cfg.NamespacesDir, pinns))
+
+	addTestPauseImage(t, cfg.Root)
+
+	containerServer, err := lib.New(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		ContainerServer: containerServer,
+		config:          *cfg,
+		resourceStore:   resourcestore.New(),
+		inFlightCreates: newInFlightCreates(),
+		nri:             &nriAPI{},
+	}
+	t.Cleanup(func() {
+		s.resourceStore.Close()
+		// Unmount what the sandboxes left behind, so that the temporary
+		// directory can be removed.
+		for _, sb := range s.ListSandboxes() {
+			if err := s.removePodSandbox(context.Background(), sb); err != nil {
+				t.Errorf("removing pod sandbox %s: %v", sb.ID(), err)
+			}
+		}
+	})
+	return s
+}
+
+// addTestPauseImage stores an empty image as the pause image below root.
+func addTestPauseImage(t *testing.T, root string) {
+	t.Helper()
+	const id = "pause"
+	bundleDir := filepath.Join(root, "Bundle")
+	if err := os.MkdirAll(filepath.Join(bundleDir, id, "rootfs"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := json.Marshal(map[string]any{
+		"Id":          id,
+		"PrefabPaths": []string{filepath.Join(bundleDir, id, "rootfs")},
+		"Blueprint":   map[string]any{"User": "0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bundleDir, id, "bundle.json"), spec, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Containers look their bundle up by the image ID.
+	if err := os.Symlink(id, filepath.Join(bundleDir, "sha256:"+id)); err != nil {
+		t.Fatal(err)
+	}
+	list, err := json.Marshal(map[string]map[string]string{"pause": {"3.10": id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bundleDir, "Bundles.json"), list, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestRunPodSandboxRequest returns a request for a pod sandbox which gets
+// along without an infra container.
+func newTestRunPodSandboxRequest(name string) *types.RunPodSandboxRequest {
+	return &types.RunPodSandboxRequest{
+		Config: &types.PodSandboxConfig{
+			Metadata: &types.PodSandboxMetadata{
+				Name:      name,
+				Uid:       name + "-uid",
+				Namespace: "default",
+			},
+			Linux: &types.LinuxPodSandboxConfig{
+				SecurityContext: &types.LinuxSandboxSecurityContext{
+					NamespaceOptions: &types.NamespaceOption{
+						Network: types.NamespaceMode_NODE,
+						Pid:     types.NamespaceMode_CONTAINER,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StageTimeoutError is returned if a stage of a container or pod sandbox
+// creation did not finish within its timeout.
+type StageTimeoutError struct {
+	// Stage is the name of the stage which timed out.
+	Stage string
+	// Timeout is the timeout of the stage.
+	Timeout time.Duration
+	// Err is the error returned by the stage.
+	Err error
+}
+
+func (e *StageTimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s: %v", e.Stage, e.Timeout, e.Err)
+}
+
+func (e *StageTimeoutError) Unwrap() []error {
+	return []error{context.DeadlineExceeded, e.Err}
+}
+
+// runStage runs fn with a context limited to timeout on top of ctx. If fn
+// fails because the timeout expired, the error is wrapped into a
+// StageTimeoutError naming the stage. The deadline of ctx caps the timeout,
+// and a timeout of zero leaves the stage to that deadline alone.
+func runStage(ctx context.Context, stage string, timeout time.Duration, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(stageCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return &StageTimeoutError{Stage: stage, Timeout: timeout, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunStageTimeout(t *testing.T) {
+	err := runStage(context.Background(), "sandbox network creation", 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var stageErr *StageTimeoutError
+	if !errors.As(err, &stageErr) {
+		t.Fatalf("expected a stage timeout error, got %v", err)
+	}
+	if stageErr.Stage != "sandbox network creation" {
+		t.Errorf("expected the stage to be named, got %q", stageErr.Stage)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the error to match context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRunStageParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runStage(ctx, "stage", time.Minute, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var stageErr *StageTimeoutError
+	if errors.As(err, &stageErr) {
+		t.Errorf("expected no stage timeout error if the parent got canceled, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRunStageCappedByParentDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	for _, timeout := range []time.Duration{0, time.Minute} {
+		err := runStage(ctx, "stage", timeout, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		var stageErr *StageTimeoutError
+		if errors.As(err, &stageErr) {
+			t.Errorf("expected no stage timeout error for timeout %s, got %v", timeout, err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the parent deadline to end the stage with timeout %s, got %v", timeout, err)
+		}
+	}
+}
+
+func TestRunStageError(t *testing.T) {
+	errStage := errors.New("stage failed")
+
+	err := runStage(context.Background(), "stage", time.Minute, func(context.Context) error {
+		return errStage
+	})
+
+	if !errors.Is(err, errStage) {
+		t.Errorf("expected the stage error, got %v", err)
+	}
+	var stageErr *StageTimeoutError
+	if errors.As(err, &stageErr) {
+		t.Errorf("expected no stage timeout error, got %v", err)
+	}
+}
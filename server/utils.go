@@ -4,32 +4,101 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/containers/storage/pkg/mount"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubeletTypes "k8s.io/kubelet/pkg/types"
 
 	"github.com/L-F-Z/cri-t/internal/log"
+	"github.com/L-F-Z/cri-t/internal/resourcestore"
 	"github.com/L-F-Z/cri-t/server/metrics"
 )
 
 const (
-	maxLabelSize = 4096
-
 	// defaultStopTimeout is the default container stop timeout in seconds.
 	defaultStopTimeout = 10
 )
 
+// unavailableError is an error which clients get with the Unavailable gRPC
+// status code, also if it is wrapped, so that they retry the request later.
+type unavailableError string
+
+func (e unavailableError) Error() string {
+	return string(e)
+}
+
+// GRPCStatus lets clients tell a temporary failure apart from others.
+func (e unavailableError) GRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, e.Error())
+}
+
+// creationSlots limits the number of resources of a kind for the max_pods and
+// max_containers options. Creations reserve a slot before they start, so that
+// concurrent creations can not exceed the limit together.
+type creationSlots struct {
+	lock     sync.Mutex
+	reserved int
+}
+
+// reserve takes a slot for a creation if the number of existing resources of
+// kind, returned by count, plus the creations holding a slot is below limit.
+// Otherwise it returns a ResourceExhausted error. The returned function
+// releases the slot and has to be called once the creation finished, when
+// the created resource is counted by count. A limit of zero means that the
+// number is not limited.
+func (c *creationSlots) reserve(kind string, count func() int, limit int) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if existing := count(); existing+c.reserved >= limit {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"limit reached: there are %d %s and %d being created, at most %d are allowed", existing, kind, c.reserved, limit)
+	}
+	c.reserved++
+
+	return func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.reserved--
+	}, nil
+}
+
+// validateLabels returns an error listing all labels which are not valid
+// Kubernetes labels. Keys are names of at most 63 characters with an optional
+// DNS subdomain prefix of at most 253 characters, values are at most 63
+// characters. The only exception is the pod name label, whose value is a DNS
+// subdomain of at most 253 characters like the names of pods.
 func validateLabels(labels map[string]string) error {
-	for k, v := range labels {
-		if (len(k) + len(v)) > maxLabelSize {
-			if len(k) > 10 {
-				k = k[:10]
-			}
-			return fmt.Errorf("label key and value greater than maximum size (%d bytes), key: %s", maxLabelSize, k)
+	var errs []error
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		v := labels[k]
+		for _, msg := range validation.IsQualifiedName(k) {
+			errs = append(errs, fmt.Errorf("invalid label key %q: %s", k, msg))
 		}
+		valueErrs := validation.IsValidLabelValue(v)
+		if k == kubeletTypes.KubernetesPodNameLabel {
+			valueErrs = validation.IsDNS1123Subdomain(v)
+		}
+		for _, msg := range valueErrs {
+			errs = append(errs, fmt.Errorf("invalid value %q of label %q: %s", v, k, msg))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid labels: %w", utilerrors.NewAggregate(errs))
 	}
 	return nil
 }
@@ -98,6 +167,60 @@ func isContextError(err error) bool {
 	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
 
+// logCleanupError logs the failure of a resource cleanup. If the error names
+// the outcome of the single cleanup steps, they are logged as fields, so that
+// leaked resources can be reclaimed manually.
+func logCleanupError(ctx context.Context, msg string, err error) {
+	var cleanupErr *resourcestore.CleanupError
+	if errors.As(err, &cleanupErr) {
+		log.WithFields(ctx, cleanupErr.Fields()).Errorf("%s: %v", msg, err)
+		return
+	}
+	log.Errorf(ctx, "%s: %v", msg, err)
+}
+
+// resourceStageCreated is the stage set once a creation finished, which ends
+// the timing of its last stage.
+const resourceStageCreated = "created"
+
+// setResourceStage sets the creation stage of the resource name in the
+// resource store and records the duration of the stage it left.
+func (s *Server) setResourceStage(ctx context.Context, name, stage string) {
+	previous, elapsed := s.resourceStore.SetStageForResource(ctx, name, stage)
+	if s.resourceStageHook != nil {
+		s.resourceStageHook(name, stage)
+	}
+	if previous == "" {
+		return
+	}
+	metrics.Instance().MetricResourceStageDurationSet(name, previous, elapsed.Seconds())
+}
+
+// finishResourceCreation records the duration of the last creation stage of
+// the resource name and removes it from the resource store, as the server
+// tracks the created resource from now on.
+func (s *Server) finishResourceCreation(ctx context.Context, name string) {
+	s.setResourceStage(ctx, name, resourceStageCreated)
+	s.resourceStore.Delete(name)
+}
+
+// parkResource saves a creation which outlived its request in the resource
+// store, so that a retried request can pick it up. Clients polling for the
+// resource can tell from the log that the creation is pending, not failed.
+func (s *Server) parkResource(ctx context.Context, resourceType, name string, resource resourcestore.IdentifiableCreatable, cleaner *resourcestore.ResourceCleaner) error {
+	stage := s.resourceStore.Stage(name)
+	if err := s.resourceStore.Put(name, resource, cleaner); err != nil {
+		return err
+	}
+	log.WithFields(ctx, map[string]any{
+		"resourceType": resourceType,
+		"name":         name,
+		"id":           resource.ID(),
+		"stage":        stage,
+	}).Infof("Parked %s in resource store, waiting for a retried request", resourceType)
+	return nil
+}
+
 func (s *Server) getResourceOrWait(ctx context.Context, name, resourceType string) (string, error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
@@ -166,8 +289,33 @@ func (s *Server) FilterDisallowedAnnotations(toFind, toFilter map[string]string,
 		return err
 	}
 	allowed = append(allowed, s.config.Workloads.AllowedAnnotations(toFind)...)
+	// toFind may be filtered as well, so look the workload up beforehand.
+	workload := s.config.Workloads.WorkloadName(toFind)
 
-	return s.config.Workloads.FilterDisallowedAnnotations(allowed, toFilter)
+	annotations := make([]string, 0, len(toFilter))
+	for ann := range toFilter {
+		annotations = append(annotations, ann)
+	}
+	if err := s.config.Workloads.FilterDisallowedAnnotations(allowed, toFilter); err != nil {
+		return err
+	}
+	// Users have no other way to find out why their annotation vanished, so
+	// name the lists which have to allow it.
+	for _, ann := range annotations {
+		if _, ok := toFilter[ann]; ok {
+			continue
+		}
+		entry := logrus.WithFields(logrus.Fields{
+			"annotation":     ann,
+			"runtimeHandler": runtimeHandler,
+		})
+		if workload == "" {
+			entry.Info("Removed disallowed annotation, it is not allowed by the runtime handler and no workload is activated")
+		} else {
+			entry.WithField("workload", workload).Info("Removed disallowed annotation, it is allowed by neither the runtime handler nor the workload")
+		}
+	}
+	return nil
 }
 
 // stopTimeoutFromContext returns the stop timeout in seconds for the provided
@@ -1,11 +1,20 @@
 package server
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/containers/storage/pkg/mount"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubeletTypes "k8s.io/kubelet/pkg/types"
+
+	"github.com/L-F-Z/cri-t/internal/oci"
+	"github.com/L-F-Z/cri-t/internal/resourcestore"
 )
 
 func TestMergeEnvs(t *testing.T) {
@@ -38,6 +47,98 @@ func TestMergeEnvs(t *testing.T) {
 	}
 }
 
+func TestCreationSlotsLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		count, limit int
+		wantErr      bool
+	}{
+		{"unlimited", 100, 0, false},
+		{"below limit", 2, 3, false},
+		{"at limit", 3, 3, true},
+		{"above limit", 4, 3, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var sut creationSlots
+			release, err := sut.reserve("containers", func() int { return tc.count }, tc.limit)
+			if tc.wantErr != (status.Code(err) == codes.ResourceExhausted) {
+				t.Errorf("expected limit reached %v, got %v", tc.wantErr, err)
+			}
+			if err == nil {
+				release()
+			}
+		})
+	}
+}
+
+func TestCreationSlotsReservesConcurrentCreations(t *testing.T) {
+	var sut creationSlots
+	count := func() int { return 1 }
+
+	// Neither creation is counted yet, but both hold a slot.
+	release, err := sut.reserve("pod sandboxes", count, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.reserve("pod sandboxes", count, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sut.reserve("pod sandboxes", count, 3); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected the creations in flight to exhaust the limit, got %v", err)
+	}
+
+	release()
+	if _, err := sut.reserve("pod sandboxes", count, 3); err != nil {
+		t.Errorf("expected the released slot to be available again, got %v", err)
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		labels     map[string]string
+		wantErrors []string
+	}{
+		{"nil labels", nil, nil},
+		{"valid labels", map[string]string{
+			"app":                               "web",
+			"example.com/tier":                  "front-end_1.0",
+			"empty":                             "",
+			kubeletTypes.KubernetesPodUIDLabel:  "a0b1c2d3-e4f5-0000-1111-222233334444",
+			kubeletTypes.KubernetesPodNameLabel: strings.Repeat("a", 253),
+		}, nil},
+		{"name too long", map[string]string{strings.Repeat("a", 64): "v"}, []string{strings.Repeat("a", 64)}},
+		{"prefix too long", map[string]string{strings.Repeat("a", 254) + "/name": "v"}, []string{"/name"}},
+		{"invalid key characters", map[string]string{"a b": "v"}, []string{`"a b"`}},
+		{"empty key", map[string]string{"": "v"}, []string{`key ""`}},
+		{"value too long", map[string]string{"key": strings.Repeat("v", 64)}, []string{`label "key"`}},
+		{"invalid pod name", map[string]string{kubeletTypes.KubernetesPodNameLabel: "Pod_Name"}, []string{"Pod_Name"}},
+		{"all invalid labels", map[string]string{
+			"a b":   "v",
+			"valid": "-v",
+			"key":   strings.Repeat("v", 64),
+		}, []string{`"a b"`, `"-v"`, `label "key"`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateLabels(tc.labels)
+			if len(tc.wantErrors) == 0 {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tc.wantErrors {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to mention %s, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
 func TestGetSourceMount(t *testing.T) {
 	mountinfo := []*mount.Info{
 		{Mountpoint: "/"},
@@ -79,3 +180,40 @@ func TestGetSourceMount(t *testing.T) {
 		}
 	}
 }
+
+func TestParkResource(t *testing.T) {
+	sut := &Server{resourceStore: resourcestore.New()}
+	defer sut.resourceStore.Close()
+	ctx := context.Background()
+
+	ctr := oci.NewSpoofedContainer("id", "name", nil, "sandbox", time.Now(), t.TempDir())
+	sut.resourceStore.SetStageForResource(ctx, "name", "container runtime creation")
+
+	if err := sut.parkResource(ctx, "container", "name", ctr, resourcestore.NewResourceCleaner()); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.parkResource(ctx, "container", "name", ctr, resourcestore.NewResourceCleaner()); err == nil {
+		t.Error("expected an error when parking a resource twice")
+	}
+	if id := sut.resourceStore.Get("name"); id != "id" {
+		t.Errorf("expected the parked resource, got ID %q", id)
+	}
+}
+
+func TestFinishResourceCreation(t *testing.T) {
+	sut := &Server{resourceStore: resourcestore.New()}
+	defer sut.resourceStore.Close()
+	ctx := context.Background()
+
+	sut.setResourceStage(ctx, "name", "container creating")
+	sut.setResourceStage(ctx, "name", "container runtime creation")
+	if stage := sut.resourceStore.Stage("name"); stage != "container runtime creation" {
+		t.Fatalf("expected stage %q, got %q", "container runtime creation", stage)
+	}
+
+	sut.finishResourceCreation(ctx, "name")
+
+	if stage := sut.resourceStore.Stage("name"); stage != resourcestore.StageUnknown {
+		t.Errorf("expected the resource to be removed from the store, got stage %q", stage)
+	}
+}